@@ -0,0 +1,238 @@
+package adapter
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vlog"
+)
+
+const (
+	// auditMaxFileSize is the size after which the log file is rotated.
+	auditMaxFileSize = 10 * 1024 * 1024
+
+	// auditMaxRotatedFiles is the number of rotated files kept per source.
+	auditMaxRotatedFiles = 5
+
+	auditRotationTimeFormat = "20060102-150405.000000000"
+)
+
+// AuditFSAdapter stores the audit entries in append-only JSON lines files.
+// Each source writes its own file, so that Vertex and the kernel never
+// write to the same file, but all of them are read by the queries.
+type AuditFSAdapter struct {
+	auditDir string
+	source   string
+
+	chown    bool
+	uid, gid uint32
+
+	maxFileSize int64
+	mutex       sync.Mutex
+}
+
+type AuditFSAdapterParams struct {
+	auditDir    string
+	maxFileSize int64
+
+	// Source is the name of the file written by this adapter.
+	Source string
+
+	// Chown gives the written files to Uid and Gid. It is used by the
+	// kernel, so that its entries can be read by Vertex.
+	Chown bool
+	Uid   uint32
+	Gid   uint32
+}
+
+func NewAuditFSAdapter(params *AuditFSAdapterParams) port.AuditAdapter {
+	if params == nil {
+		params = &AuditFSAdapterParams{}
+	}
+	if params.auditDir == "" {
+		params.auditDir = path.Join(storage.Path, "audit")
+	}
+	if params.maxFileSize == 0 {
+		params.maxFileSize = auditMaxFileSize
+	}
+	if params.Source == "" {
+		params.Source = types.AuditSourceVertex
+	}
+
+	a := &AuditFSAdapter{
+		auditDir:    params.auditDir,
+		source:      params.Source,
+		chown:       params.Chown && runtime.GOOS != "windows",
+		uid:         params.Uid,
+		gid:         params.Gid,
+		maxFileSize: params.maxFileSize,
+	}
+
+	err := os.MkdirAll(a.auditDir, 0750)
+	if err == nil {
+		err = a.own(a.auditDir)
+	}
+	if err != nil {
+		log.Error(err,
+			vlog.String("message", "failed to create directory"),
+			vlog.String("path", a.auditDir),
+		)
+		os.Exit(1)
+	}
+
+	return a
+}
+
+func (a *AuditFSAdapter) Append(entry types.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	err = a.rotate(int64(len(data)))
+	if err != nil {
+		log.Error(err)
+	}
+
+	p := a.logPath()
+	_, err = os.Stat(p)
+	created := errors.Is(err, os.ErrNotExist)
+
+	file, err := os.OpenFile(p, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if created {
+		err = a.own(p)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = file.Write(data)
+	return err
+}
+
+func (a *AuditFSAdapter) Query(filter types.AuditFilter) ([]types.AuditEntry, error) {
+	files, err := filepath.Glob(path.Join(a.auditDir, "*.log"))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []types.AuditEntry{}
+	for _, file := range files {
+		res, err := readAuditFile(file, filter)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, res...)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Time.After(entries[j].Time)
+	})
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+	return entries, nil
+}
+
+func (a *AuditFSAdapter) logPath() string {
+	return path.Join(a.auditDir, a.source+".log")
+}
+
+// rotate renames the log file if writing n bytes would make it exceed the
+// max size, and removes the oldest rotated files.
+func (a *AuditFSAdapter) rotate(n int64) error {
+	info, err := os.Stat(a.logPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if info.Size()+n <= a.maxFileSize {
+		return nil
+	}
+
+	rotated := path.Join(a.auditDir, a.source+"-"+time.Now().UTC().Format(auditRotationTimeFormat)+".log")
+	err = os.Rename(a.logPath(), rotated)
+	if err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(path.Join(a.auditDir, a.source+"-*.log"))
+	if err != nil {
+		return err
+	}
+
+	// The rotation time format sorts the files from the oldest.
+	sort.Strings(files)
+	for len(files) > auditMaxRotatedFiles {
+		err = os.Remove(files[0])
+		if err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+func (a *AuditFSAdapter) own(p string) error {
+	if !a.chown {
+		return nil
+	}
+	return os.Chown(p, int(a.uid), int(a.gid))
+}
+
+func readAuditFile(p string, filter types.AuditFilter) ([]types.AuditEntry, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []types.AuditEntry
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry types.AuditEntry
+		err = json.Unmarshal([]byte(line), &entry)
+		if err != nil {
+			// A line can be truncated if Vertex stopped while writing it.
+			log.Warn("invalid audit entry", vlog.String("file", p))
+			continue
+		}
+
+		if filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
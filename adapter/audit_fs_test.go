@@ -0,0 +1,78 @@
+package adapter
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/core/types"
+)
+
+type AuditFSAdapterTestSuite struct {
+	suite.Suite
+
+	dir     string
+	adapter *AuditFSAdapter
+}
+
+func TestAuditFSAdapterTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditFSAdapterTestSuite))
+}
+
+func (suite *AuditFSAdapterTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+	suite.adapter = NewAuditFSAdapter(&AuditFSAdapterParams{
+		auditDir: suite.dir,
+	}).(*AuditFSAdapter)
+}
+
+func (suite *AuditFSAdapterTestSuite) TestAppendQuery() {
+	now := time.Now()
+	suite.NoError(suite.adapter.Append(types.AuditEntry{ID: uuid.New(), Time: now.Add(-time.Hour), Actor: "alice", Route: "/api/settings"}))
+	suite.NoError(suite.adapter.Append(types.AuditEntry{ID: uuid.New(), Time: now, Actor: "bob", Route: "/api/settings"}))
+
+	kernel := NewAuditFSAdapter(&AuditFSAdapterParams{
+		auditDir: suite.dir,
+		Source:   types.AuditSourceKernel,
+	})
+	suite.NoError(kernel.Append(types.AuditEntry{ID: uuid.New(), Time: now.Add(-time.Minute), Route: "/api/docker/container"}))
+
+	info, err := os.Stat(path.Join(suite.dir, "vertex.log"))
+	suite.NoError(err)
+	suite.Equal(os.FileMode(0640), info.Mode().Perm())
+
+	entries, err := suite.adapter.Query(types.AuditFilter{})
+	suite.NoError(err)
+	suite.Len(entries, 3)
+	suite.Equal("bob", entries[0].Actor)
+	suite.Equal("/api/docker/container", entries[1].Route)
+
+	entries, err = suite.adapter.Query(types.AuditFilter{Actor: "alice"})
+	suite.NoError(err)
+	suite.Len(entries, 1)
+
+	entries, err = suite.adapter.Query(types.AuditFilter{Since: now.Add(-2 * time.Minute), Limit: 1})
+	suite.NoError(err)
+	suite.Len(entries, 1)
+	suite.Equal("bob", entries[0].Actor)
+}
+
+func (suite *AuditFSAdapterTestSuite) TestRotate() {
+	suite.adapter.maxFileSize = 1
+
+	for i := 0; i < auditMaxRotatedFiles+3; i++ {
+		suite.NoError(suite.adapter.Append(types.AuditEntry{ID: uuid.New(), Time: time.Now()}))
+	}
+
+	rotated, err := filepath.Glob(path.Join(suite.dir, "vertex-*.log"))
+	suite.NoError(err)
+	suite.Len(rotated, auditMaxRotatedFiles)
+
+	entries, err := suite.adapter.Query(types.AuditFilter{})
+	suite.NoError(err)
+	suite.Len(entries, auditMaxRotatedFiles+1)
+}
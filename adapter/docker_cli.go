@@ -1,14 +1,21 @@
 package adapter
 
 import (
+	"bytes"
 	"context"
 	"github.com/vertex-center/vertex/core/types"
 	"io"
 
 	dockertypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vlog"
 )
@@ -32,8 +39,8 @@ func NewDockerCliAdapter() DockerCliAdapter {
 	}
 }
 
-func (a DockerCliAdapter) ListContainers() ([]types.Container, error) {
-	res, err := a.cli.ContainerList(context.Background(), dockertypes.ContainerListOptions{All: true})
+func (a DockerCliAdapter) ListContainers(ctx context.Context) ([]types.Container, error) {
+	res, err := a.cli.ContainerList(ctx, dockertypes.ContainerListOptions{All: true})
 	if err != nil {
 		return nil, err
 	}
@@ -45,11 +52,11 @@ func (a DockerCliAdapter) ListContainers() ([]types.Container, error) {
 	return containers, nil
 }
 
-func (a DockerCliAdapter) DeleteContainer(id string) error {
-	return a.cli.ContainerRemove(context.Background(), id, dockertypes.ContainerRemoveOptions{})
+func (a DockerCliAdapter) DeleteContainer(ctx context.Context, id string) error {
+	return a.cli.ContainerRemove(ctx, id, dockertypes.ContainerRemoveOptions{})
 }
 
-func (a DockerCliAdapter) CreateContainer(options types.CreateContainerOptions) (types.CreateContainerResponse, error) {
+func (a DockerCliAdapter) CreateContainer(ctx context.Context, options types.CreateContainerOptions) (types.CreateContainerResponse, error) {
 	config := container.Config{
 		Image:        options.ImageName,
 		ExposedPorts: options.ExposedPorts,
@@ -65,9 +72,21 @@ func (a DockerCliAdapter) CreateContainer(options types.CreateContainerOptions)
 		PortBindings: options.PortBindings,
 		CapAdd:       options.CapAdd,
 		Sysctls:      options.Sysctls,
+		Privileged:   options.Privileged,
+		NetworkMode:  container.NetworkMode(options.NetworkMode),
+		Tmpfs:        options.Tmpfs,
 	}
 
-	res, err := a.cli.ContainerCreate(context.Background(), &config, &hostConfig, nil, nil, options.ContainerName)
+	for _, v := range options.VolumeMounts {
+		hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+			Type:     mount.TypeVolume,
+			Source:   v.Name,
+			Target:   v.Target,
+			ReadOnly: v.ReadOnly,
+		})
+	}
+
+	res, err := a.cli.ContainerCreate(ctx, &config, &hostConfig, nil, nil, options.ContainerName)
 	if err != nil {
 		return types.CreateContainerResponse{}, err
 	}
@@ -78,47 +97,51 @@ func (a DockerCliAdapter) CreateContainer(options types.CreateContainerOptions)
 	}, nil
 }
 
-func (a DockerCliAdapter) StartContainer(id string) error {
-	return a.cli.ContainerStart(context.Background(), id, dockertypes.ContainerStartOptions{})
+func (a DockerCliAdapter) StartContainer(ctx context.Context, id string) error {
+	return a.cli.ContainerStart(ctx, id, dockertypes.ContainerStartOptions{})
 }
 
-func (a DockerCliAdapter) StopContainer(id string) error {
-	return a.cli.ContainerStop(context.Background(), id, container.StopOptions{})
+func (a DockerCliAdapter) StopContainer(ctx context.Context, id string, options types.StopContainerOptions) error {
+	return a.cli.ContainerStop(ctx, id, container.StopOptions{
+		Timeout: options.Timeout,
+	})
 }
 
-func (a DockerCliAdapter) InfoContainer(id string) (types.InfoContainerResponse, error) {
-	info, err := a.cli.ContainerInspect(context.Background(), id)
+func (a DockerCliAdapter) InfoContainer(ctx context.Context, id string) (types.InfoContainerResponse, error) {
+	info, err := a.cli.ContainerInspect(ctx, id)
 	if err != nil {
 		return types.InfoContainerResponse{}, err
 	}
-	return types.InfoContainerResponse{
-		ID:       info.ID,
-		Name:     info.Name,
-		Platform: info.Platform,
-		Image:    info.Image,
-	}, nil
+	return types.NewInfoContainer(info), nil
 }
 
-func (a DockerCliAdapter) LogsStdoutContainer(id string) (io.ReadCloser, error) {
-	return a.cli.ContainerLogs(context.Background(), id, dockertypes.ContainerLogsOptions{
+func (a DockerCliAdapter) LogsStdoutContainer(ctx context.Context, id string, options types.LogsContainerOptions) (io.ReadCloser, error) {
+	return a.cli.ContainerLogs(ctx, id, dockertypes.ContainerLogsOptions{
 		ShowStdout: true,
-		Timestamps: false,
+		Timestamps: true,
 		Follow:     true,
-		Tail:       "0",
+		Tail:       logsTail(options),
 	})
 }
 
-func (a DockerCliAdapter) LogsStderrContainer(id string) (io.ReadCloser, error) {
-	return a.cli.ContainerLogs(context.Background(), id, dockertypes.ContainerLogsOptions{
+func (a DockerCliAdapter) LogsStderrContainer(ctx context.Context, id string, options types.LogsContainerOptions) (io.ReadCloser, error) {
+	return a.cli.ContainerLogs(ctx, id, dockertypes.ContainerLogsOptions{
 		ShowStderr: true,
-		Timestamps: false,
+		Timestamps: true,
 		Follow:     true,
-		Tail:       "0",
+		Tail:       logsTail(options),
 	})
 }
 
-func (a DockerCliAdapter) WaitContainer(id string, cond types.WaitContainerCondition) error {
-	statusCh, errCh := a.cli.ContainerWait(context.Background(), id, container.WaitCondition(cond))
+func logsTail(options types.LogsContainerOptions) string {
+	if options.Tail == "" {
+		return "0"
+	}
+	return options.Tail
+}
+
+func (a DockerCliAdapter) WaitContainer(ctx context.Context, id string, cond types.WaitContainerCondition) error {
+	statusCh, errCh := a.cli.ContainerWait(ctx, id, container.WaitCondition(cond))
 
 	select {
 	case err := <-errCh:
@@ -131,25 +154,76 @@ func (a DockerCliAdapter) WaitContainer(id string, cond types.WaitContainerCondi
 	return nil
 }
 
-func (a DockerCliAdapter) InfoImage(id string) (types.InfoImageResponse, error) {
-	info, _, err := a.cli.ImageInspectWithRaw(context.Background(), id)
+// ExecContainer runs a command inside the container, and waits until it
+// exits.
+func (a DockerCliAdapter) ExecContainer(ctx context.Context, id string, options types.ExecContainerOptions) (types.ExecContainerResponse, error) {
+	exec, err := a.cli.ContainerExecCreate(ctx, id, dockertypes.ExecConfig{
+		Cmd:          options.Cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
 	if err != nil {
-		return types.InfoImageResponse{}, nil
+		return types.ExecContainerResponse{}, err
 	}
-	return types.InfoImageResponse{
-		ID:           info.ID,
-		Architecture: info.Architecture,
-		OS:           info.Os,
-		Size:         info.Size,
-		Tags:         info.RepoTags,
+
+	res, err := a.cli.ContainerExecAttach(ctx, exec.ID, dockertypes.ExecStartCheck{})
+	if err != nil {
+		return types.ExecContainerResponse{}, err
+	}
+	defer res.Close()
+
+	var stdout, stderr bytes.Buffer
+	_, err = stdcopy.StdCopy(&stdout, &stderr, res.Reader)
+	if err != nil {
+		return types.ExecContainerResponse{}, err
+	}
+
+	inspect, err := a.cli.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return types.ExecContainerResponse{}, err
+	}
+
+	return types.ExecContainerResponse{
+		ExitCode: inspect.ExitCode,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
 	}, nil
 }
 
-func (a DockerCliAdapter) PullImage(options types.PullImageOptions) (io.ReadCloser, error) {
-	return a.cli.ImagePull(context.Background(), options.Image, dockertypes.ImagePullOptions{})
+func (a DockerCliAdapter) ListImages(ctx context.Context) ([]types.Image, error) {
+	res, err := a.cli.ImageList(ctx, dockertypes.ImageListOptions{All: false})
+	if err != nil {
+		return nil, err
+	}
+
+	var images []types.Image
+	for _, i := range res {
+		images = append(images, types.NewImage(i))
+	}
+	return images, nil
+}
+
+func (a DockerCliAdapter) DeleteImage(ctx context.Context, id string) error {
+	_, err := a.cli.ImageRemove(ctx, id, dockertypes.ImageRemoveOptions{
+		Force:         true,
+		PruneChildren: true,
+	})
+	return err
+}
+
+func (a DockerCliAdapter) InfoImage(ctx context.Context, id string) (types.InfoImageResponse, error) {
+	info, _, err := a.cli.ImageInspectWithRaw(ctx, id)
+	if err != nil {
+		return types.InfoImageResponse{}, err
+	}
+	return types.NewInfoImage(info), nil
+}
+
+func (a DockerCliAdapter) PullImage(ctx context.Context, options types.PullImageOptions) (io.ReadCloser, error) {
+	return a.cli.ImagePull(ctx, options.Image, dockertypes.ImagePullOptions{})
 }
 
-func (a DockerCliAdapter) BuildImage(options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error) {
+func (a DockerCliAdapter) BuildImage(ctx context.Context, options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error) {
 	buildOptions := dockertypes.ImageBuildOptions{
 		Dockerfile: options.Dockerfile,
 		Tags:       []string{options.Name},
@@ -163,5 +237,146 @@ func (a DockerCliAdapter) BuildImage(options types.BuildImageOptions) (dockertyp
 		return dockertypes.ImageBuildResponse{}, err
 	}
 
-	return a.cli.ImageBuild(context.Background(), reader, buildOptions)
+	return a.cli.ImageBuild(ctx, reader, buildOptions)
+}
+
+func (a DockerCliAdapter) PruneBuildCache(ctx context.Context) (types.PruneBuildCacheResponse, error) {
+	res, err := a.cli.BuildCachePrune(ctx, dockertypes.BuildCachePruneOptions{All: true})
+	if err != nil {
+		return types.PruneBuildCacheResponse{}, err
+	}
+	return types.PruneBuildCacheResponse{
+		SpaceReclaimed: res.SpaceReclaimed,
+	}, nil
+}
+
+// ListVolumes returns all Docker volumes. The disk usage is used instead of
+// the volume list, because it is the only way to get the size and the number
+// of containers using each volume.
+func (a DockerCliAdapter) ListVolumes(ctx context.Context) ([]types.Volume, error) {
+	usage, err := a.cli.DiskUsage(ctx, dockertypes.DiskUsageOptions{
+		Types: []dockertypes.DiskUsageObject{dockertypes.VolumeObject},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var volumes []types.Volume
+	for _, v := range usage.Volumes {
+		volumes = append(volumes, types.NewVolume(v))
+	}
+	return volumes, nil
+}
+
+func (a DockerCliAdapter) CreateVolume(ctx context.Context, options types.CreateVolumeOptions) (types.Volume, error) {
+	v, err := a.cli.VolumeCreate(ctx, volume.CreateOptions{
+		Name:       options.Name,
+		Driver:     options.Driver,
+		DriverOpts: options.DriverOptions,
+		Labels:     options.Labels,
+	})
+	if err != nil {
+		return types.Volume{}, err
+	}
+	return types.NewVolume(&v), nil
+}
+
+// InspectVolume returns a Docker volume, with its disk usage if it is
+// known by Docker.
+func (a DockerCliAdapter) InspectVolume(ctx context.Context, name string) (types.Volume, error) {
+	v, err := a.cli.VolumeInspect(ctx, name)
+	if err != nil {
+		return types.Volume{}, err
+	}
+
+	volumes, err := a.ListVolumes(ctx)
+	if err != nil {
+		return types.Volume{}, err
+	}
+	for _, vol := range volumes {
+		if vol.Name == v.Name {
+			return vol, nil
+		}
+	}
+	return types.NewVolume(&v), nil
+}
+
+func (a DockerCliAdapter) DeleteVolume(ctx context.Context, name string) error {
+	return a.cli.VolumeRemove(ctx, name, false)
+}
+
+// Events streams the container events until the context is done. The
+// error channel receives an error if the stream is interrupted.
+func (a DockerCliAdapter) Events(ctx context.Context) (<-chan types.DockerEvent, <-chan error) {
+	args := filters.NewArgs(filters.Arg("type", string(events.ContainerEventType)))
+	for _, action := range types.DockerEventActions {
+		args.Add("event", action)
+	}
+
+	messages, errs := a.cli.Events(ctx, dockertypes.EventsOptions{Filters: args})
+
+	out := make(chan types.DockerEvent)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case m := <-messages:
+				e, ok := types.NewDockerEvent(m)
+				if !ok {
+					continue
+				}
+				select {
+				case out <- e:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, errs
+}
+
+func (a DockerCliAdapter) ListNetworks(ctx context.Context) ([]types.Network, error) {
+	res, err := a.cli.NetworkList(ctx, dockertypes.NetworkListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var networks []types.Network
+	for _, n := range res {
+		networks = append(networks, types.NewNetwork(n))
+	}
+	return networks, nil
+}
+
+func (a DockerCliAdapter) CreateNetwork(ctx context.Context, options types.CreateNetworkOptions) (types.CreateNetworkResponse, error) {
+	res, err := a.cli.NetworkCreate(ctx, options.Name, dockertypes.NetworkCreate{
+		CheckDuplicate: true,
+		Driver:         "bridge",
+		Internal:       options.Internal,
+		Labels:         options.Labels,
+	})
+	if err != nil {
+		return types.CreateNetworkResponse{}, err
+	}
+	return types.CreateNetworkResponse{
+		ID:       res.ID,
+		Warnings: res.Warning,
+	}, nil
+}
+
+func (a DockerCliAdapter) DeleteNetwork(ctx context.Context, id string) error {
+	return a.cli.NetworkRemove(ctx, id)
+}
+
+func (a DockerCliAdapter) ConnectNetwork(ctx context.Context, id string, options types.ConnectNetworkOptions) error {
+	return a.cli.NetworkConnect(ctx, id, options.Container, &network.EndpointSettings{
+		Aliases: options.Aliases,
+	})
+}
+
+func (a DockerCliAdapter) DisconnectNetwork(ctx context.Context, id string, options types.DisconnectNetworkOptions) error {
+	return a.cli.NetworkDisconnect(ctx, id, options.Container, false)
 }
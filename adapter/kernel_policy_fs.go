@@ -0,0 +1,102 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+// defaultKernelPolicyPath is outside of the storage of Vertex, which is
+// owned by the unprivileged user running Vertex.
+const defaultKernelPolicyPath = "/etc/vertex/kernel-policy.json"
+
+var ErrKernelPolicyInsecure = errors.New("the kernel policy can be edited by other users than root")
+
+// KernelPolicyFSAdapter reads the kernel policy from a file that only root
+// can edit. The file is read on every access, so that the changes are
+// applied without restarting the kernel. If the file or one of its parent
+// directories can be edited by another user, the default policy is used.
+type KernelPolicyFSAdapter struct {
+	policyPath string
+	checkOwner func(p string) error
+}
+
+type KernelPolicyFSAdapterParams struct {
+	policyPath string
+	checkOwner func(p string) error
+}
+
+func NewKernelPolicyFSAdapter(params *KernelPolicyFSAdapterParams) port.KernelPolicyAdapter {
+	if params == nil {
+		params = &KernelPolicyFSAdapterParams{}
+	}
+	if params.policyPath == "" {
+		params.policyPath = defaultKernelPolicyPath
+	}
+	if params.checkOwner == nil {
+		params.checkOwner = checkRootOwned
+	}
+
+	a := &KernelPolicyFSAdapter{
+		policyPath: params.policyPath,
+		checkOwner: params.checkOwner,
+	}
+
+	err := a.writeDefault()
+	if err != nil {
+		log.Error(err,
+			vlog.String("message", "failed to create the default kernel policy"),
+			vlog.String("path", a.policyPath),
+		)
+	}
+
+	return a
+}
+
+func (a *KernelPolicyFSAdapter) Get() (types.KernelPolicy, error) {
+	data, err := os.ReadFile(a.policyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return types.DefaultKernelPolicy(), nil
+	} else if err != nil {
+		return types.KernelPolicy{}, err
+	}
+
+	err = a.checkOwner(a.policyPath)
+	if err != nil {
+		log.Warn("the kernel policy is ignored, the default policy is used",
+			vlog.String("path", a.policyPath),
+			vlog.String("error", err.Error()),
+		)
+		return types.DefaultKernelPolicy(), nil
+	}
+
+	var policy types.KernelPolicy
+	err = json.Unmarshal(data, &policy)
+	return policy, err
+}
+
+// writeDefault creates the policy file with the default policy, so that it
+// can be edited by the admins.
+func (a *KernelPolicyFSAdapter) writeDefault() error {
+	_, err := os.Stat(a.policyPath)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	err = os.MkdirAll(path.Dir(a.policyPath), 0755)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(types.DefaultKernelPolicy(), "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.policyPath, data, 0644)
+}
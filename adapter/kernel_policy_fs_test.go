@@ -0,0 +1,64 @@
+package adapter
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/core/types"
+)
+
+type KernelPolicyFSAdapterTestSuite struct {
+	suite.Suite
+
+	adapter *KernelPolicyFSAdapter
+}
+
+func TestKernelPolicyFSAdapterTestSuite(t *testing.T) {
+	suite.Run(t, new(KernelPolicyFSAdapterTestSuite))
+}
+
+func (suite *KernelPolicyFSAdapterTestSuite) SetupTest() {
+	suite.adapter = NewKernelPolicyFSAdapter(&KernelPolicyFSAdapterParams{
+		policyPath: path.Join(suite.T().TempDir(), "kernel", "policy.json"),
+		checkOwner: func(p string) error { return nil },
+	}).(*KernelPolicyFSAdapter)
+}
+
+func (suite *KernelPolicyFSAdapterTestSuite) TestGetDefault() {
+	_, err := os.Stat(suite.adapter.policyPath)
+	suite.NoError(err)
+
+	policy, err := suite.adapter.Get()
+	suite.NoError(err)
+	suite.Equal(types.DefaultKernelPolicy(), policy)
+}
+
+func (suite *KernelPolicyFSAdapterTestSuite) TestGetEdited() {
+	err := os.WriteFile(suite.adapter.policyPath, []byte(`{"allow_privileged": true}`), 0644)
+	suite.Require().NoError(err)
+
+	policy, err := suite.adapter.Get()
+	suite.NoError(err)
+	suite.True(policy.AllowPrivileged)
+	suite.Empty(policy.AllowedCapabilities)
+}
+
+func (suite *KernelPolicyFSAdapterTestSuite) TestGetInsecure() {
+	err := os.WriteFile(suite.adapter.policyPath, []byte(`{"allow_privileged": true}`), 0644)
+	suite.Require().NoError(err)
+
+	if runtime.GOOS == "windows" {
+		suite.T().Skip("the owner is not checked on Windows")
+	}
+
+	// The temporary directory is not owned by root, or is writable by all
+	// the users.
+	suite.adapter.checkOwner = checkRootOwned
+
+	policy, err := suite.adapter.Get()
+	suite.NoError(err)
+	suite.Equal(types.DefaultKernelPolicy(), policy)
+}
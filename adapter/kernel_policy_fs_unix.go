@@ -0,0 +1,40 @@
+//go:build !windows
+
+package adapter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// checkRootOwned returns ErrKernelPolicyInsecure if the file or one of its
+// parent directories is not owned by root, or is writable by the group or
+// by the other users.
+func checkRootOwned(p string) error {
+	p, err := filepath.Abs(p)
+	if err != nil {
+		return err
+	}
+
+	for {
+		info, err := os.Lstat(p)
+		if err != nil {
+			return err
+		}
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok || stat.Uid != 0 {
+			return fmt.Errorf("%w: %s is not owned by root", ErrKernelPolicyInsecure, p)
+		}
+		if info.Mode().Perm()&0022 != 0 {
+			return fmt.Errorf("%w: %s is writable by other users", ErrKernelPolicyInsecure, p)
+		}
+
+		parent := filepath.Dir(p)
+		if parent == p {
+			return nil
+		}
+		p = parent
+	}
+}
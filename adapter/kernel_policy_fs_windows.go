@@ -0,0 +1,9 @@
+//go:build windows
+
+package adapter
+
+// checkRootOwned does nothing on Windows, where the kernel doesn't drop its
+// privileges.
+func checkRootOwned(p string) error {
+	return nil
+}
@@ -0,0 +1,138 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vlog"
+)
+
+type RoleFSAdapter struct {
+	rolesPath string
+
+	roles map[string]types.Role
+	mutex sync.RWMutex
+}
+
+type RoleFSAdapterParams struct {
+	authDir string
+}
+
+func NewRoleFSAdapter(params *RoleFSAdapterParams) port.RoleAdapter {
+	if params == nil {
+		params = &RoleFSAdapterParams{}
+	}
+	if params.authDir == "" {
+		params.authDir = path.Join(storage.Path, "auth")
+	}
+
+	err := os.MkdirAll(params.authDir, os.ModePerm)
+	if err != nil && !os.IsExist(err) {
+		log.Error(err,
+			vlog.String("message", "failed to create directory"),
+			vlog.String("path", params.authDir),
+		)
+		os.Exit(1)
+	}
+
+	a := &RoleFSAdapter{
+		rolesPath: path.Join(params.authDir, "roles.json"),
+		roles:     map[string]types.Role{},
+	}
+
+	err = a.read()
+	if err != nil {
+		log.Error(err)
+	}
+
+	return a
+}
+
+func (a *RoleFSAdapter) GetAll() ([]types.Role, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	roles := make([]types.Role, 0, len(a.roles))
+	for _, r := range a.roles {
+		roles = append(roles, r)
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+	return roles, nil
+}
+
+func (a *RoleFSAdapter) Get(name string) (types.Role, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	r, ok := a.roles[name]
+	if !ok {
+		return types.Role{}, types.ErrRoleNotFound
+	}
+	return r, nil
+}
+
+func (a *RoleFSAdapter) Set(role types.Role) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.roles[role.Name] = role
+	return a.write()
+}
+
+func (a *RoleFSAdapter) Delete(name string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if _, ok := a.roles[name]; !ok {
+		return types.ErrRoleNotFound
+	}
+
+	delete(a.roles, name)
+	return a.write()
+}
+
+func (a *RoleFSAdapter) read() error {
+	data, err := os.ReadFile(a.rolesPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var roles []types.Role
+	err = json.Unmarshal(data, &roles)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range roles {
+		a.roles[r.Name] = r
+	}
+	return nil
+}
+
+func (a *RoleFSAdapter) write() error {
+	roles := make([]types.Role, 0, len(a.roles))
+	for _, r := range a.roles {
+		roles = append(roles, r)
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+
+	data, err := json.MarshalIndent(roles, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.rolesPath, data, 0600)
+}
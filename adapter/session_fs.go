@@ -0,0 +1,145 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vlog"
+)
+
+type SessionFSAdapter struct {
+	sessionsPath string
+
+	sessions map[string]types.Session
+	mutex    sync.RWMutex
+}
+
+type SessionFSAdapterParams struct {
+	authDir string
+}
+
+func NewSessionFSAdapter(params *SessionFSAdapterParams) port.SessionAdapter {
+	if params == nil {
+		params = &SessionFSAdapterParams{}
+	}
+	if params.authDir == "" {
+		params.authDir = path.Join(storage.Path, "auth")
+	}
+
+	err := os.MkdirAll(params.authDir, os.ModePerm)
+	if err != nil && !os.IsExist(err) {
+		log.Error(err,
+			vlog.String("message", "failed to create directory"),
+			vlog.String("path", params.authDir),
+		)
+		os.Exit(1)
+	}
+
+	a := &SessionFSAdapter{
+		sessionsPath: path.Join(params.authDir, "sessions.json"),
+		sessions:     map[string]types.Session{},
+	}
+
+	err = a.read()
+	if err != nil {
+		log.Error(err)
+	}
+
+	return a
+}
+
+func (a *SessionFSAdapter) Get(tokenHash string) (types.Session, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	s, ok := a.sessions[tokenHash]
+	if !ok {
+		return types.Session{}, types.ErrSessionNotFound
+	}
+	return s, nil
+}
+
+func (a *SessionFSAdapter) Create(session types.Session) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.sessions[session.TokenHash] = session
+	return a.write()
+}
+
+func (a *SessionFSAdapter) Delete(tokenHash string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if _, ok := a.sessions[tokenHash]; !ok {
+		return types.ErrSessionNotFound
+	}
+
+	delete(a.sessions, tokenHash)
+	return a.write()
+}
+
+func (a *SessionFSAdapter) DeleteUser(userID uuid.UUID) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for hash, s := range a.sessions {
+		if s.UserID == userID {
+			delete(a.sessions, hash)
+		}
+	}
+	return a.write()
+}
+
+func (a *SessionFSAdapter) DeleteExpired() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for hash, s := range a.sessions {
+		if s.Expired() {
+			delete(a.sessions, hash)
+		}
+	}
+	return a.write()
+}
+
+func (a *SessionFSAdapter) read() error {
+	data, err := os.ReadFile(a.sessionsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var sessions []types.Session
+	err = json.Unmarshal(data, &sessions)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		a.sessions[s.TokenHash] = s
+	}
+	return nil
+}
+
+func (a *SessionFSAdapter) write() error {
+	sessions := make([]types.Session, 0, len(a.sessions))
+	for _, s := range a.sessions {
+		sessions = append(sessions, s)
+	}
+
+	data, err := json.MarshalIndent(sessions, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.sessionsPath, data, 0600)
+}
@@ -3,9 +3,9 @@ package adapter
 import (
 	"context"
 	"github.com/carlmjohnson/requests"
-	"github.com/vertex-center/vertex/config"
 	"github.com/vertex-center/vertex/core/port"
 	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
 )
 
 type SshKernelApiAdapter struct {
@@ -14,9 +14,7 @@ type SshKernelApiAdapter struct {
 
 func NewSshKernelApiAdapter() port.SshAdapter {
 	return &SshKernelApiAdapter{
-		config: func(rb *requests.Builder) {
-			rb.BaseURL(config.Current.KernelURL())
-		},
+		config: api.KernelConfig,
 	}
 }
 
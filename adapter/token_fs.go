@@ -0,0 +1,176 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vlog"
+)
+
+type TokenFSAdapter struct {
+	tokensPath string
+
+	tokens map[uuid.UUID]types.Token
+	mutex  sync.RWMutex
+}
+
+type TokenFSAdapterParams struct {
+	authDir string
+}
+
+// tokenFile is the representation of a token on the disk, which unlike the
+// API representation, contains the hash.
+type tokenFile struct {
+	types.Token
+	Hash string `json:"hash"`
+}
+
+func NewTokenFSAdapter(params *TokenFSAdapterParams) port.TokenAdapter {
+	if params == nil {
+		params = &TokenFSAdapterParams{}
+	}
+	if params.authDir == "" {
+		params.authDir = path.Join(storage.Path, "auth")
+	}
+
+	err := os.MkdirAll(params.authDir, os.ModePerm)
+	if err != nil && !os.IsExist(err) {
+		log.Error(err,
+			vlog.String("message", "failed to create directory"),
+			vlog.String("path", params.authDir),
+		)
+		os.Exit(1)
+	}
+
+	a := &TokenFSAdapter{
+		tokensPath: path.Join(params.authDir, "tokens.json"),
+		tokens:     map[uuid.UUID]types.Token{},
+	}
+
+	err = a.read()
+	if err != nil {
+		log.Error(err)
+	}
+
+	return a
+}
+
+func (a *TokenFSAdapter) GetAll() ([]types.Token, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	return a.sorted(), nil
+}
+
+func (a *TokenFSAdapter) Get(id uuid.UUID) (types.Token, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	t, ok := a.tokens[id]
+	if !ok {
+		return types.Token{}, types.ErrTokenNotFound
+	}
+	return t, nil
+}
+
+func (a *TokenFSAdapter) GetByHash(hash string) (types.Token, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	for _, t := range a.tokens {
+		if t.Hash == hash {
+			return t, nil
+		}
+	}
+	return types.Token{}, types.ErrTokenNotFound
+}
+
+func (a *TokenFSAdapter) Create(token types.Token) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.tokens[token.ID] = token
+	return a.write()
+}
+
+func (a *TokenFSAdapter) Update(token types.Token) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if _, ok := a.tokens[token.ID]; !ok {
+		return types.ErrTokenNotFound
+	}
+
+	a.tokens[token.ID] = token
+	return a.write()
+}
+
+func (a *TokenFSAdapter) Delete(id uuid.UUID) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if _, ok := a.tokens[id]; !ok {
+		return types.ErrTokenNotFound
+	}
+
+	delete(a.tokens, id)
+	return a.write()
+}
+
+func (a *TokenFSAdapter) sorted() []types.Token {
+	tokens := make([]types.Token, 0, len(a.tokens))
+	for _, t := range a.tokens {
+		tokens = append(tokens, t)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+	})
+	return tokens
+}
+
+func (a *TokenFSAdapter) read() error {
+	data, err := os.ReadFile(a.tokensPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var tokens []tokenFile
+	err = json.Unmarshal(data, &tokens)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tokens {
+		token := t.Token
+		token.Hash = t.Hash
+		a.tokens[token.ID] = token
+	}
+	return nil
+}
+
+func (a *TokenFSAdapter) write() error {
+	tokens := make([]tokenFile, 0, len(a.tokens))
+	for _, t := range a.sorted() {
+		tokens = append(tokens, tokenFile{
+			Token: t,
+			Hash:  t.Hash,
+		})
+	}
+
+	data, err := json.MarshalIndent(tokens, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.tokensPath, data, 0600)
+}
@@ -0,0 +1,184 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vlog"
+)
+
+type UserFSAdapter struct {
+	usersPath string
+
+	users map[uuid.UUID]types.User
+	mutex sync.RWMutex
+}
+
+type UserFSAdapterParams struct {
+	authDir string
+}
+
+// userFile is the representation of a user on the disk, which unlike the
+// API representation, contains the password hash.
+type userFile struct {
+	types.User
+	PasswordHash string `json:"password_hash"`
+}
+
+func NewUserFSAdapter(params *UserFSAdapterParams) port.UserAdapter {
+	if params == nil {
+		params = &UserFSAdapterParams{}
+	}
+	if params.authDir == "" {
+		params.authDir = path.Join(storage.Path, "auth")
+	}
+
+	err := os.MkdirAll(params.authDir, os.ModePerm)
+	if err != nil && !os.IsExist(err) {
+		log.Error(err,
+			vlog.String("message", "failed to create directory"),
+			vlog.String("path", params.authDir),
+		)
+		os.Exit(1)
+	}
+
+	a := &UserFSAdapter{
+		usersPath: path.Join(params.authDir, "users.json"),
+		users:     map[uuid.UUID]types.User{},
+	}
+
+	err = a.read()
+	if err != nil {
+		log.Error(err)
+	}
+
+	return a
+}
+
+func (a *UserFSAdapter) GetAll() ([]types.User, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	users := make([]types.User, 0, len(a.users))
+	for _, u := range a.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].CreatedAt.Before(users[j].CreatedAt)
+	})
+	return users, nil
+}
+
+func (a *UserFSAdapter) Get(id uuid.UUID) (types.User, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	u, ok := a.users[id]
+	if !ok {
+		return types.User{}, types.ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (a *UserFSAdapter) GetByUsername(username string) (types.User, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	for _, u := range a.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return types.User{}, types.ErrUserNotFound
+}
+
+func (a *UserFSAdapter) Create(user types.User) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for _, u := range a.users {
+		if u.ID == user.ID || u.Username == user.Username {
+			return types.ErrUserAlreadyExists
+		}
+	}
+
+	a.users[user.ID] = user
+	return a.write()
+}
+
+func (a *UserFSAdapter) Update(user types.User) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if _, ok := a.users[user.ID]; !ok {
+		return types.ErrUserNotFound
+	}
+
+	a.users[user.ID] = user
+	return a.write()
+}
+
+func (a *UserFSAdapter) Delete(id uuid.UUID) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if _, ok := a.users[id]; !ok {
+		return types.ErrUserNotFound
+	}
+
+	delete(a.users, id)
+	return a.write()
+}
+
+func (a *UserFSAdapter) read() error {
+	data, err := os.ReadFile(a.usersPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var users []userFile
+	err = json.Unmarshal(data, &users)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range users {
+		user := u.User
+		user.PasswordHash = u.PasswordHash
+		a.users[user.ID] = user
+	}
+	return nil
+}
+
+func (a *UserFSAdapter) write() error {
+	users := make([]userFile, 0, len(a.users))
+	for _, u := range a.users {
+		users = append(users, userFile{
+			User:         u,
+			PasswordHash: u.PasswordHash,
+		})
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].CreatedAt.Before(users[j].CreatedAt)
+	})
+
+	data, err := json.MarshalIndent(users, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	// The file contains the password hashes, so it must only be readable
+	// by the Vertex user.
+	return os.WriteFile(a.usersPath, data, 0600)
+}
@@ -0,0 +1,71 @@
+package adapter
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/core/types"
+)
+
+type UserFSAdapterTestSuite struct {
+	suite.Suite
+
+	adapter *UserFSAdapter
+}
+
+func TestUserFSAdapterTestSuite(t *testing.T) {
+	suite.Run(t, new(UserFSAdapterTestSuite))
+}
+
+func (suite *UserFSAdapterTestSuite) SetupTest() {
+	suite.adapter = NewUserFSAdapter(&UserFSAdapterParams{
+		authDir: suite.T().TempDir(),
+	}).(*UserFSAdapter)
+}
+
+func (suite *UserFSAdapterTestSuite) TestCreateRead() {
+	user := types.User{
+		ID:           uuid.New(),
+		Username:     "alice",
+		CreatedAt:    time.Now(),
+		PasswordHash: "hash",
+	}
+
+	err := suite.adapter.Create(user)
+	suite.NoError(err)
+
+	err = suite.adapter.Create(types.User{ID: uuid.New(), Username: "alice"})
+	suite.ErrorIs(err, types.ErrUserAlreadyExists)
+
+	info, err := os.Stat(suite.adapter.usersPath)
+	suite.NoError(err)
+	suite.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	// The password hash must be kept on the disk.
+	suite.adapter.users = map[uuid.UUID]types.User{}
+	err = suite.adapter.read()
+	suite.NoError(err)
+
+	u, err := suite.adapter.GetByUsername("alice")
+	suite.NoError(err)
+	suite.Equal(user.ID, u.ID)
+	suite.Equal("hash", u.PasswordHash)
+}
+
+func (suite *UserFSAdapterTestSuite) TestDelete() {
+	id := uuid.New()
+	err := suite.adapter.Create(types.User{ID: id, Username: "alice"})
+	suite.NoError(err)
+
+	err = suite.adapter.Delete(id)
+	suite.NoError(err)
+
+	_, err = suite.adapter.Get(id)
+	suite.ErrorIs(err, types.ErrUserNotFound)
+
+	err = suite.adapter.Delete(id)
+	suite.ErrorIs(err, types.ErrUserNotFound)
+}
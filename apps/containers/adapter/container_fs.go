@@ -17,6 +17,18 @@ type ContainerFilePath string
 
 var (
 	ErrContainerNotFound = errors.New("container not found")
+
+	// ErrContainerNotRunning is returned when attaching to a Docker
+	// container that is not running.
+	ErrContainerNotRunning = errors.New("container not running")
+
+	// ErrKernelPolicyViolation is returned when the kernel refuses to create
+	// a container because of its policy.
+	ErrKernelPolicyViolation = errors.New("kernel policy violation")
+
+	// ErrVolumeCopyFailed is returned when the content of a volume could
+	// not be copied to another container.
+	ErrVolumeCopyFailed = errors.New("failed to copy the volume")
 )
 
 type ContainerFSAdapter struct {
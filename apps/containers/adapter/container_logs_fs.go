@@ -1,12 +1,18 @@
 package adapter
 
 import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/vertex-center/vertex/apps/containers/core/port"
 	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"io"
 	"os"
 	"path"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,7 +23,20 @@ import (
 	"github.com/vertex-center/vlog"
 )
 
-const bufferSize = 50
+const (
+	bufferSize = 50
+
+	defaultLogsMaxAge  = 30 * 24 * time.Hour
+	defaultLogsMaxSize = 100 * 1024 * 1024
+
+	logsFilePrefix = "logs_"
+	logsFileExt    = ".jsonl"
+	logsGzipExt    = ".gz"
+
+	// logsLegacyFileExt is the extension of the raw text files written by
+	// previous versions. They are only kept until the retention removes them.
+	logsLegacyFileExt = ".txt"
+)
 
 var (
 	ErrLoggerNotFound = errors.New("container logger not found")
@@ -31,7 +50,12 @@ type ContainerLogger struct {
 	currentLine int
 	scheduler   *gocron.Scheduler
 
-	dir string
+	// mutex protects the buffer and the log files.
+	mutex sync.RWMutex
+
+	dir     string
+	maxAge  time.Duration
+	maxSize int64
 }
 
 type ContainerLogsFSAdapter struct {
@@ -39,10 +63,28 @@ type ContainerLogsFSAdapter struct {
 	loggersMutex sync.RWMutex
 
 	containersPath string
+	maxAge         time.Duration
+	maxSize        int64
 }
 
 type ContainerLogsFSAdapterParams struct {
 	ContainersPath string
+
+	// MaxAge is the duration after which log files are deleted.
+	MaxAge time.Duration
+
+	// MaxSize is the maximum size in bytes of the log files of a container.
+	// The oldest files are deleted first when this size is exceeded.
+	MaxSize int64
+}
+
+// logFile is a log file of a container, for a given day.
+type logFile struct {
+	path       string
+	date       time.Time
+	size       int64
+	compressed bool
+	legacy     bool
 }
 
 func NewContainerLogsFSAdapter(params *ContainerLogsFSAdapterParams) port.ContainerLogsAdapter {
@@ -53,12 +95,20 @@ func NewContainerLogsFSAdapter(params *ContainerLogsFSAdapterParams) port.Contai
 	if params.ContainersPath == "" {
 		params.ContainersPath = path.Join(storage.Path, "apps", "vx-containers")
 	}
+	if params.MaxAge == 0 {
+		params.MaxAge = defaultLogsMaxAge
+	}
+	if params.MaxSize == 0 {
+		params.MaxSize = defaultLogsMaxSize
+	}
 
 	return &ContainerLogsFSAdapter{
 		loggers:      map[uuid.UUID]*ContainerLogger{},
 		loggersMutex: sync.RWMutex{},
 
 		containersPath: params.ContainersPath,
+		maxAge:         params.MaxAge,
+		maxSize:        params.MaxSize,
 	}
 }
 
@@ -71,20 +121,35 @@ func (a *ContainerLogsFSAdapter) Register(uuid uuid.UUID) error {
 	}
 
 	l := ContainerLogger{
-		uuid:   uuid,
-		buffer: []containerstypes.LogLine{},
-		dir:    dir,
+		uuid:    uuid,
+		buffer:  []containerstypes.LogLine{},
+		dir:     dir,
+		maxAge:  a.maxAge,
+		maxSize: a.maxSize,
 	}
 
 	a.loggersMutex.Lock()
 	defer a.loggersMutex.Unlock()
 	a.loggers[uuid] = &l
 
+	l.currentLine, err = l.lastId()
+	if err != nil {
+		log.Warn("failed to read the last log id",
+			vlog.String("uuid", uuid.String()),
+			vlog.String("error", err.Error()),
+		)
+	}
+
 	err = l.Open()
 	if err != nil {
 		return err
 	}
 
+	err = l.cleanup()
+	if err != nil {
+		log.Error(err)
+	}
+
 	return l.startCron()
 }
 
@@ -99,7 +164,9 @@ func (a *ContainerLogsFSAdapter) Unregister(uuid uuid.UUID) error {
 		return err
 	}
 
+	l.mutex.Lock()
 	err = l.Close()
+	l.mutex.Unlock()
 	if err != nil {
 		return err
 	}
@@ -116,13 +183,27 @@ func (a *ContainerLogsFSAdapter) Push(uuid uuid.UUID, line containerstypes.LogLi
 		log.Error(err)
 		return
 	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
 	l.currentLine += 1
+	line.Id = l.currentLine
+	if line.Time.IsZero() {
+		line.Time = time.Now()
+	}
 	l.buffer = append(l.buffer, line)
 	if len(l.buffer) > bufferSize {
 		l.buffer = l.buffer[1:]
 	}
 
-	_, err = fmt.Fprintf(l.file, "%s\n", line.Message.String())
+	// Download progress lines are only useful while downloading,
+	// so they are not persisted.
+	if line.Kind == containerstypes.LogKindDownloads {
+		return
+	}
+
+	err = json.NewEncoder(l.file).Encode(containerstypes.NewLogRecord(line))
 	if err != nil {
 		log.Error(err)
 	}
@@ -133,6 +214,10 @@ func (a *ContainerLogsFSAdapter) Pop(uuid uuid.UUID) (containerstypes.LogLine, e
 	if err != nil {
 		return containerstypes.LogLine{}, err
 	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
 	if len(l.buffer) == 0 {
 		return containerstypes.LogLine{}, containerstypes.ErrBufferEmpty
 	}
@@ -146,7 +231,21 @@ func (a *ContainerLogsFSAdapter) LoadBuffer(uuid uuid.UUID) ([]containerstypes.L
 	if err != nil {
 		return nil, err
 	}
-	return l.buffer, nil
+
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	buffer := make([]containerstypes.LogLine, len(l.buffer))
+	copy(buffer, l.buffer)
+	return buffer, nil
+}
+
+func (a *ContainerLogsFSAdapter) Query(uuid uuid.UUID, query containerstypes.LogQuery) (containerstypes.LogPage, error) {
+	l, err := a.getLogger(uuid)
+	if err != nil {
+		return containerstypes.LogPage{}, err
+	}
+	return l.Query(query)
 }
 
 func (a *ContainerLogsFSAdapter) UnregisterAll() error {
@@ -185,7 +284,7 @@ func (a *ContainerLogsFSAdapter) dir(uuid uuid.UUID) string {
 }
 
 func (l *ContainerLogger) Open() error {
-	filename := fmt.Sprintf("logs_%s.txt", time.Now().Format(time.DateOnly))
+	filename := fmt.Sprintf("%s%s%s", logsFilePrefix, time.Now().Format(time.DateOnly), logsFileExt)
 	filepath := path.Join(l.dir, filename)
 
 	file, err := os.OpenFile(filepath, os.O_RDWR|os.O_CREATE|os.O_APPEND, os.ModePerm)
@@ -210,12 +309,12 @@ func (l *ContainerLogger) Close() error {
 func (l *ContainerLogger) startCron() error {
 	l.scheduler = gocron.NewScheduler(time.Local)
 	_, err := l.scheduler.Every(1).Day().At("00:00").Do(func() {
-		err := l.Close()
+		err := l.rotate()
 		if err != nil {
 			log.Error(err)
 			return
 		}
-		err = l.Open()
+		err = l.cleanup()
 		if err != nil {
 			log.Error(err)
 		}
@@ -232,3 +331,266 @@ func (l *ContainerLogger) stopCron() error {
 	l.scheduler.Stop()
 	return nil
 }
+
+// Query returns the persisted records matching the query, from the newest
+// to the oldest.
+func (l *ContainerLogger) Query(query containerstypes.LogQuery) (containerstypes.LogPage, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	page := containerstypes.LogPage{
+		Records: []containerstypes.LogRecord{},
+	}
+
+	files, err := l.files()
+	if err != nil {
+		return page, err
+	}
+
+	for _, f := range files {
+		if f.legacy {
+			continue
+		}
+		// Each file contains the records of one day.
+		if query.Since != nil && f.date.AddDate(0, 0, 1).Before(*query.Since) {
+			break
+		}
+		if query.Until != nil && f.date.After(*query.Until) {
+			continue
+		}
+
+		records, err := readLogFile(f)
+		if err != nil {
+			return page, err
+		}
+
+		for i := len(records) - 1; i >= 0; i-- {
+			if !query.Match(records[i]) {
+				continue
+			}
+			if len(page.Records) == query.Limit {
+				cursor := page.Records[len(page.Records)-1].Id
+				page.NextCursor = &cursor
+				return page, nil
+			}
+			page.Records = append(page.Records, records[i])
+		}
+	}
+
+	return page, nil
+}
+
+func (l *ContainerLogger) rotate() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	err := l.Close()
+	if err != nil {
+		return err
+	}
+	return l.Open()
+}
+
+// cleanup compresses the log files of the previous days, and deletes the
+// files exceeding the maximum age or the maximum size.
+func (l *ContainerLogger) cleanup() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	files, err := l.files()
+	if err != nil {
+		return err
+	}
+
+	var current string
+	if l.file != nil {
+		current = l.file.Name()
+	}
+
+	var kept []logFile
+	for _, f := range files {
+		if f.path == current {
+			kept = append(kept, f)
+			continue
+		}
+
+		if time.Since(f.date) > l.maxAge {
+			err := os.Remove(f.path)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !f.compressed && !f.legacy {
+			f, err = compressLogFile(f)
+			if err != nil {
+				return err
+			}
+		}
+		kept = append(kept, f)
+	}
+
+	var size int64
+	for _, f := range kept {
+		size += f.size
+	}
+
+	// Files are sorted from the newest to the oldest.
+	for i := len(kept) - 1; i >= 0 && size > l.maxSize; i-- {
+		if kept[i].path == current {
+			continue
+		}
+		err := os.Remove(kept[i].path)
+		if err != nil {
+			return err
+		}
+		size -= kept[i].size
+	}
+
+	return nil
+}
+
+// lastId returns the id of the last persisted record, so that ids keep
+// increasing across restarts.
+func (l *ContainerLogger) lastId() (int, error) {
+	files, err := l.files()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, f := range files {
+		if f.legacy {
+			continue
+		}
+		records, err := readLogFile(f)
+		if err != nil {
+			return 0, err
+		}
+		if len(records) > 0 {
+			return records[len(records)-1].Id, nil
+		}
+	}
+	return 0, nil
+}
+
+// files returns the log files of the container, from the newest to the oldest.
+func (l *ContainerLogger) files() ([]logFile, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []logFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, logsFilePrefix) {
+			continue
+		}
+
+		f := logFile{
+			path: path.Join(l.dir, name),
+		}
+
+		name = strings.TrimPrefix(name, logsFilePrefix)
+		if strings.HasSuffix(name, logsGzipExt) {
+			f.compressed = true
+			name = strings.TrimSuffix(name, logsGzipExt)
+		}
+		if strings.HasSuffix(name, logsLegacyFileExt) {
+			f.legacy = true
+			name = strings.TrimSuffix(name, logsLegacyFileExt)
+		} else if strings.HasSuffix(name, logsFileExt) {
+			name = strings.TrimSuffix(name, logsFileExt)
+		} else {
+			continue
+		}
+
+		f.date, err = time.ParseInLocation(time.DateOnly, name, time.Local)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		f.size = info.Size()
+
+		files = append(files, f)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].date.After(files[j].date)
+	})
+
+	return files, nil
+}
+
+func readLogFile(f logFile) ([]containerstypes.LogRecord, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if f.compressed {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var records []containerstypes.LogRecord
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record containerstypes.LogRecord
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			// The line can be partially written, or corrupted.
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+func compressLogFile(f logFile) (logFile, error) {
+	compressed := f
+	compressed.path = f.path + logsGzipExt
+	compressed.compressed = true
+
+	src, err := os.Open(f.path)
+	if err != nil {
+		return f, err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(compressed.path)
+	if err != nil {
+		return f, err
+	}
+	defer dst.Close()
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if err != nil {
+		return f, err
+	}
+	err = gz.Close()
+	if err != nil {
+		return f, err
+	}
+
+	info, err := dst.Stat()
+	if err != nil {
+		return f, err
+	}
+	compressed.size = info.Size()
+
+	return compressed, os.Remove(f.path)
+}
@@ -1,9 +1,12 @@
 package adapter
 
 import (
+	"fmt"
 	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
 	"os"
+	"path"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
@@ -70,6 +73,47 @@ func (suite *ContainerLoggerTestSuite) TestCron() {
 	suite.False(suite.logger.scheduler.IsRunning())
 }
 
+func (suite *ContainerLoggerTestSuite) TestCleanup() {
+	suite.logger.maxAge = 7 * 24 * time.Hour
+	suite.logger.maxSize = 1024 * 1024
+
+	day := func(days int) string {
+		return time.Now().AddDate(0, 0, -days).Format(time.DateOnly)
+	}
+
+	write := func(name string, size int) {
+		err := os.WriteFile(path.Join(suite.logger.dir, name), make([]byte, size), os.ModePerm)
+		suite.NoError(err)
+	}
+
+	write("logs_"+day(1)+".jsonl", 10)
+	write("logs_"+day(2)+".txt", 10)
+	write("logs_"+day(30)+".jsonl.gz", 10)
+
+	err := suite.logger.Open()
+	suite.NoError(err)
+	defer suite.logger.Close()
+
+	err = suite.logger.cleanup()
+	suite.NoError(err)
+
+	suite.FileExists(suite.logger.file.Name())
+	suite.FileExists(path.Join(suite.logger.dir, "logs_"+day(1)+".jsonl.gz"))
+	suite.NoFileExists(path.Join(suite.logger.dir, "logs_"+day(1)+".jsonl"))
+	suite.FileExists(path.Join(suite.logger.dir, "logs_"+day(2)+".txt"))
+	suite.NoFileExists(path.Join(suite.logger.dir, "logs_"+day(30)+".jsonl.gz"))
+
+	// Exceed the maximum size
+	suite.logger.maxSize = 100
+	write("logs_"+day(3)+".txt", 200)
+
+	err = suite.logger.cleanup()
+	suite.NoError(err)
+
+	suite.NoFileExists(path.Join(suite.logger.dir, "logs_"+day(3)+".txt"))
+	suite.FileExists(suite.logger.file.Name())
+}
+
 type ContainerLogsFSAdapterTestSuite struct {
 	suite.Suite
 
@@ -203,3 +247,79 @@ func (suite *ContainerLogsFSAdapterTestSuite) TestPop() {
 	suite.NoError(err)
 	suite.Len(l.buffer, 0)
 }
+
+func (suite *ContainerLogsFSAdapterTestSuite) TestQuery() {
+	instID := uuid.New()
+
+	err := suite.adapter.Register(instID)
+	suite.NoError(err)
+	defer func() {
+		err := suite.adapter.Unregister(instID)
+		suite.NoError(err)
+	}()
+
+	for i := 0; i < 5; i++ {
+		suite.adapter.Push(instID, containerstypes.LogLine{
+			Kind:    containerstypes.LogKindOut,
+			Message: containerstypes.NewLogLineMessageString(fmt.Sprintf("out %d", i)),
+		})
+	}
+	suite.adapter.Push(instID, containerstypes.LogLine{
+		Kind:    containerstypes.LogKindErr,
+		Message: containerstypes.NewLogLineMessageString("Something FAILED"),
+	})
+
+	// Filter by text
+	page, err := suite.adapter.Query(instID, containerstypes.LogQuery{Query: "failed", Limit: 10})
+	suite.NoError(err)
+	suite.Len(page.Records, 1)
+	suite.Equal(containerstypes.LogStreamStderr, page.Records[0].Stream)
+	suite.Equal(6, page.Records[0].Id)
+	suite.Nil(page.NextCursor)
+
+	// Paginate
+	query := containerstypes.LogQuery{
+		Kinds: []string{containerstypes.LogKindOut},
+		Limit: 3,
+	}
+	page, err = suite.adapter.Query(instID, query)
+	suite.NoError(err)
+	suite.Len(page.Records, 3)
+	suite.Equal("out 4", page.Records[0].Message)
+	suite.NotNil(page.NextCursor)
+
+	query.Cursor = page.NextCursor
+	page, err = suite.adapter.Query(instID, query)
+	suite.NoError(err)
+	suite.Len(page.Records, 2)
+	suite.Equal("out 1", page.Records[0].Message)
+	suite.Equal("out 0", page.Records[1].Message)
+	suite.Nil(page.NextCursor)
+}
+
+func (suite *ContainerLogsFSAdapterTestSuite) TestLastId() {
+	instID := uuid.New()
+
+	err := suite.adapter.Register(instID)
+	suite.NoError(err)
+
+	suite.adapter.Push(instID, containerstypes.LogLine{
+		Kind:    containerstypes.LogKindOut,
+		Message: containerstypes.NewLogLineMessageString("test"),
+	})
+
+	err = suite.adapter.Unregister(instID)
+	suite.NoError(err)
+
+	// The ids must continue after a restart
+	err = suite.adapter.Register(instID)
+	suite.NoError(err)
+	defer func() {
+		err := suite.adapter.Unregister(instID)
+		suite.NoError(err)
+	}()
+
+	l, err := suite.adapter.getLogger(instID)
+	suite.NoError(err)
+	suite.Equal(1, l.currentLine)
+}
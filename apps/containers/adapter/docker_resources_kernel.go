@@ -0,0 +1,85 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/vertex-center/vertex/config"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
+)
+
+type DockerResourcesKernelAdapter struct{}
+
+func NewDockerResourcesKernelAdapter() DockerResourcesKernelAdapter {
+	return DockerResourcesKernelAdapter{}
+}
+
+func (a DockerResourcesKernelAdapter) ListContainers() ([]types.Container, error) {
+	ctx, cancel := withKernelTimeout(context.Background())
+	defer cancel()
+
+	var containers []types.Container
+	err := api.KernelRequest().
+		Path("/api/docker/containers").
+		ToJSON(&containers).
+		Fetch(ctx)
+	return containers, err
+}
+
+func (a DockerResourcesKernelAdapter) ListImages() ([]types.Image, error) {
+	ctx, cancel := withKernelTimeout(context.Background())
+	defer cancel()
+
+	var images []types.Image
+	err := api.KernelRequest().
+		Path("/api/docker/images").
+		ToJSON(&images).
+		Fetch(ctx)
+	return images, err
+}
+
+func (a DockerResourcesKernelAdapter) DeleteImage(id string) error {
+	ctx, cancel := withKernelTimeout(context.Background())
+	defer cancel()
+
+	return api.KernelRequest().
+		Pathf("/api/docker/image/%s", id).
+		Delete().
+		Fetch(ctx)
+}
+
+func (a DockerResourcesKernelAdapter) ListVolumes() ([]types.Volume, error) {
+	ctx, cancel := withKernelTimeout(context.Background())
+	defer cancel()
+
+	var volumes []types.Volume
+	err := api.KernelRequest().
+		Path("/api/docker/volumes").
+		ToJSON(&volumes).
+		Fetch(ctx)
+	return volumes, err
+}
+
+func (a DockerResourcesKernelAdapter) DeleteVolume(name string) error {
+	ctx, cancel := withKernelTimeout(context.Background())
+	defer cancel()
+
+	return api.KernelRequest().
+		Pathf("/api/docker/volume/%s", name).
+		Delete().
+		Fetch(ctx)
+}
+
+func (a DockerResourcesKernelAdapter) PruneBuildCache() (types.PruneBuildCacheResponse, error) {
+	// Pruning the build cache can take as long as an image build.
+	ctx, cancel := context.WithTimeout(context.Background(), config.Current.Timeouts.Image)
+	defer cancel()
+
+	var res types.PruneBuildCacheResponse
+	err := api.KernelRequest().
+		Path("/api/docker/build-cache/prune").
+		Post().
+		ToJSON(&res).
+		Fetch(ctx)
+	return res, err
+}
@@ -0,0 +1,193 @@
+package adapter
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vlog"
+)
+
+const defaultLogBufferMaxSize = 50 * 1024 * 1024
+
+var (
+	ErrLogBufferFull = errors.New("the log buffer is full")
+)
+
+type LogBufferFSAdapter struct {
+	// mutex protects the buffer files.
+	mutex sync.Mutex
+
+	bufferPath string
+	maxSize    int64
+}
+
+type LogBufferFSAdapterParams struct {
+	BufferPath string
+
+	// MaxSize is the maximum size in bytes of the buffer of each sink.
+	// New entries are dropped when the buffer is full.
+	MaxSize int64
+}
+
+func NewLogBufferFSAdapter(params *LogBufferFSAdapterParams) port.LogBufferAdapter {
+	if params == nil {
+		params = &LogBufferFSAdapterParams{}
+	}
+	if params.BufferPath == "" {
+		params.BufferPath = path.Join(storage.Path, "apps", "vx-containers-logs", "buffer")
+	}
+	if params.MaxSize == 0 {
+		params.MaxSize = defaultLogBufferMaxSize
+	}
+
+	err := os.MkdirAll(params.BufferPath, os.ModePerm)
+	if err != nil && !os.IsExist(err) {
+		log.Error(err,
+			vlog.String("message", "failed to create directory"),
+			vlog.String("path", params.BufferPath),
+		)
+		os.Exit(1)
+	}
+
+	return &LogBufferFSAdapter{
+		bufferPath: params.BufferPath,
+		maxSize:    params.MaxSize,
+	}
+}
+
+func (a *LogBufferFSAdapter) Push(sinkID uuid.UUID, entries []types.LogEntry) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	p := a.path(sinkID)
+
+	info, err := os.Stat(p)
+	if err == nil && info.Size() >= a.maxSize {
+		return ErrLogBufferFull
+	}
+
+	file, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_APPEND, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, entry := range entries {
+		err := encoder.Encode(entry)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *LogBufferFSAdapter) Load(sinkID uuid.UUID, limit int) ([]types.LogEntry, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	file, err := os.Open(a.path(sinkID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []types.LogEntry
+	scanner := newLogBufferScanner(file)
+	for len(entries) < limit && scanner.Scan() {
+		var entry types.LogEntry
+		err := json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Trim removes the first count entries of the buffer, by copying the
+// remaining entries to a new file. The invalid lines are dropped, as Load
+// skips them.
+func (a *LogBufferFSAdapter) Trim(sinkID uuid.UUID, count int) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	p := a.path(sinkID)
+
+	file, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	tmp, err := os.CreateTemp(a.bufferPath, sinkID.String()+"_*.jsonl")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	w := bufio.NewWriter(tmp)
+	scanner := newLogBufferScanner(file)
+	for trimmed := 0; scanner.Scan(); {
+		var entry types.LogEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			continue
+		}
+		if trimmed < count {
+			trimmed++
+			continue
+		}
+		_, err := w.Write(append(scanner.Bytes(), '\n'))
+		if err != nil {
+			return err
+		}
+	}
+	if scanner.Err() != nil {
+		return scanner.Err()
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (a *LogBufferFSAdapter) Clear(sinkID uuid.UUID) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	err := os.Remove(a.path(sinkID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func newLogBufferScanner(file *os.File) *bufio.Scanner {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	return scanner
+}
+
+func (a *LogBufferFSAdapter) path(sinkID uuid.UUID) string {
+	return path.Join(a.bufferPath, sinkID.String()+".jsonl")
+}
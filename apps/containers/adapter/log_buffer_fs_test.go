@@ -0,0 +1,61 @@
+package adapter
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+)
+
+type LogBufferFSAdapterTestSuite struct {
+	suite.Suite
+
+	dir     string
+	adapter *LogBufferFSAdapter
+}
+
+func TestLogBufferFSAdapterTestSuite(t *testing.T) {
+	suite.Run(t, new(LogBufferFSAdapterTestSuite))
+}
+
+func (suite *LogBufferFSAdapterTestSuite) SetupTest() {
+	dir, err := os.MkdirTemp("", "*_log_buffer_test")
+	suite.NoError(err)
+
+	suite.dir = dir
+	suite.adapter = NewLogBufferFSAdapter(&LogBufferFSAdapterParams{
+		BufferPath: dir,
+	}).(*LogBufferFSAdapter)
+}
+
+func (suite *LogBufferFSAdapterTestSuite) TearDownTest() {
+	err := os.RemoveAll(suite.dir)
+	suite.NoError(err)
+}
+
+func (suite *LogBufferFSAdapterTestSuite) TestLoadTrim() {
+	id := uuid.New()
+
+	err := suite.adapter.Push(id, []types.LogEntry{{Message: "1"}, {Message: "2"}, {Message: "3"}})
+	suite.NoError(err)
+
+	entries, err := suite.adapter.Load(id, 2)
+	suite.NoError(err)
+	suite.Equal([]types.LogEntry{{Message: "1"}, {Message: "2"}}, entries)
+
+	err = suite.adapter.Trim(id, 2)
+	suite.NoError(err)
+
+	entries, err = suite.adapter.Load(id, 2)
+	suite.NoError(err)
+	suite.Equal([]types.LogEntry{{Message: "3"}}, entries)
+
+	err = suite.adapter.Trim(id, 2)
+	suite.NoError(err)
+
+	entries, err = suite.adapter.Load(id, 2)
+	suite.NoError(err)
+	suite.Empty(entries)
+}
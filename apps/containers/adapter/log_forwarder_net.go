@@ -0,0 +1,235 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carlmjohnson/requests"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+)
+
+const (
+	logForwarderTimeout = 10 * time.Second
+
+	// syslogFacilityUser is the "user-level messages" facility of RFC5424.
+	syslogFacilityUser = 1
+
+	// syslogEnterpriseID is the private enterprise number used in the
+	// structured data. 32473 is reserved for documentation by RFC5612.
+	syslogEnterpriseID = 32473
+)
+
+type LogForwarderNetAdapter struct {
+	hostname string
+}
+
+func NewLogForwarderNetAdapter() port.LogForwarderAdapter {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	return &LogForwarderNetAdapter{
+		hostname: hostname,
+	}
+}
+
+func (a *LogForwarderNetAdapter) Send(sink types.LogSink, entries []types.LogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	switch sink.Type {
+	case types.LogSinkTypeSyslog:
+		return a.sendSyslog(sink, entries)
+	case types.LogSinkTypeLoki:
+		return a.sendLoki(sink, entries)
+	case types.LogSinkTypeHTTP:
+		return a.sendHTTP(sink, entries)
+	default:
+		return types.ErrLogSinkTypeInvalid
+	}
+}
+
+func (a *LogForwarderNetAdapter) sendSyslog(sink types.LogSink, entries []types.LogEntry) error {
+	u, err := url.Parse(sink.URL)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout(u.Scheme, u.Host, logForwarderTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	err = conn.SetDeadline(time.Now().Add(logForwarderTimeout))
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		msg := a.syslogMessage(entry)
+		if u.Scheme == "tcp" {
+			// Octet counting framing, as described in RFC6587.
+			msg = strconv.Itoa(len(msg)) + " " + msg
+		}
+		_, err := conn.Write([]byte(msg))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syslogMessage formats an entry as an RFC5424 message.
+func (a *LogForwarderNetAdapter) syslogMessage(entry types.LogEntry) string {
+	pri := syslogFacilityUser*8 + syslogSeverity(entry)
+
+	appName := entry.Labels[types.LogLabelServiceID]
+	if appName == "" {
+		appName = "vertex"
+	}
+
+	var sd strings.Builder
+	sd.WriteString(fmt.Sprintf("[vertex@%d", syslogEnterpriseID))
+	for _, key := range sortedKeys(entry.Labels) {
+		sd.WriteString(fmt.Sprintf(" %s=\"%s\"", key, syslogEscape(entry.Labels[key])))
+	}
+	sd.WriteString("]")
+
+	return fmt.Sprintf("<%d>1 %s %s %s - %s %s %s",
+		pri,
+		entry.Time.UTC().Format(time.RFC3339Nano),
+		syslogHeader(a.hostname, 255),
+		syslogHeader(appName, 48),
+		syslogHeader(entry.Kind, 32),
+		sd.String(),
+		entry.Message,
+	)
+}
+
+// lokiPush is the body of the Loki push API.
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (a *LogForwarderNetAdapter) sendLoki(sink types.LogSink, entries []types.LogEntry) error {
+	streams := map[string]*lokiStream{}
+	var keys []string
+
+	for _, entry := range entries {
+		labels := map[string]string{
+			"stream": entry.Stream,
+		}
+		if entry.Level != "" {
+			labels["level"] = entry.Level
+		}
+		for k, v := range entry.Labels {
+			labels[k] = v
+		}
+
+		var key strings.Builder
+		for _, k := range sortedKeys(labels) {
+			key.WriteString(k + "=" + labels[k] + ",")
+		}
+
+		s, ok := streams[key.String()]
+		if !ok {
+			s = &lokiStream{Stream: labels}
+			streams[key.String()] = s
+			keys = append(keys, key.String())
+		}
+		s.Values = append(s.Values, [2]string{
+			strconv.FormatInt(entry.Time.UnixNano(), 10),
+			entry.Message,
+		})
+	}
+
+	body := lokiPush{}
+	for _, key := range keys {
+		body.Streams = append(body.Streams, *streams[key])
+	}
+
+	return a.post(sink, strings.TrimSuffix(sink.URL, "/")+"/loki/api/v1/push", body)
+}
+
+func (a *LogForwarderNetAdapter) sendHTTP(sink types.LogSink, entries []types.LogEntry) error {
+	return a.post(sink, sink.URL, entries)
+}
+
+func (a *LogForwarderNetAdapter) post(sink types.LogSink, u string, body any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), logForwarderTimeout)
+	defer cancel()
+
+	req := requests.URL(u).
+		BodyJSON(body).
+		Post()
+	for k, v := range sink.Headers {
+		req = req.Header(k, v)
+	}
+	return req.Fetch(ctx)
+}
+
+func syslogSeverity(entry types.LogEntry) int {
+	switch entry.Level {
+	case types.LogLevelFatal:
+		return 2
+	case types.LogLevelError:
+		return 3
+	case types.LogLevelWarn:
+		return 4
+	case types.LogLevelInfo:
+		return 6
+	case types.LogLevelDebug, types.LogLevelTrace:
+		return 7
+	}
+	if entry.Stream == types.LogStreamStderr || entry.Kind == types.LogKindVertexErr || entry.Kind == types.LogKindHookErr {
+		return 3
+	}
+	return 6
+}
+
+// syslogHeader formats a header field: printable ASCII only, without spaces,
+// limited to max characters, and "-" if empty.
+func syslogHeader(s string, max int) string {
+	var b bytes.Buffer
+	for _, r := range s {
+		if r > 32 && r < 127 {
+			b.WriteRune(r)
+		}
+		if b.Len() == max {
+			break
+		}
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return b.String()
+}
+
+func syslogEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(s)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,99 @@
+package adapter
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+)
+
+type LogForwarderNetAdapterTestSuite struct {
+	suite.Suite
+
+	adapter *LogForwarderNetAdapter
+	entry   types.LogEntry
+}
+
+func TestLogForwarderNetAdapterTestSuite(t *testing.T) {
+	suite.Run(t, new(LogForwarderNetAdapterTestSuite))
+}
+
+func (suite *LogForwarderNetAdapterTestSuite) SetupTest() {
+	suite.adapter = &LogForwarderNetAdapter{hostname: "host"}
+	suite.entry = types.LogEntry{
+		Time:    time.Date(2023, 10, 15, 9, 30, 0, 0, time.UTC),
+		Kind:    types.LogKindErr,
+		Stream:  types.LogStreamStderr,
+		Message: "connection refused",
+		Labels: map[string]string{
+			types.LogLabelContainerUUID: "8c7a1f0e",
+			types.LogLabelServiceID:     "postgres",
+			types.LogLabelDisplayName:   `My "db"`,
+		},
+	}
+}
+
+func (suite *LogForwarderNetAdapterTestSuite) TestSyslogMessage() {
+	msg := suite.adapter.syslogMessage(suite.entry)
+	suite.Equal(`<11>1 2023-10-15T09:30:00Z host postgres - err [vertex@32473 container_uuid="8c7a1f0e" display_name="My \"db\"" service_id="postgres"] connection refused`, msg)
+}
+
+func (suite *LogForwarderNetAdapterTestSuite) TestSendSyslogUDP() {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+	defer conn.Close()
+
+	err = suite.adapter.Send(types.LogSink{
+		Type: types.LogSinkTypeSyslog,
+		URL:  "udp://" + conn.LocalAddr().String(),
+	}, []types.LogEntry{suite.entry})
+	suite.NoError(err)
+
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	suite.NoError(err)
+	suite.Equal(suite.adapter.syslogMessage(suite.entry), string(buf[:n]))
+}
+
+func (suite *LogForwarderNetAdapterTestSuite) TestSendLoki() {
+	var body lokiPush
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Equal("/loki/api/v1/push", r.URL.Path)
+		suite.Equal("secret", r.Header.Get("X-Scope-OrgID"))
+		suite.NoError(json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := suite.adapter.Send(types.LogSink{
+		Type:    types.LogSinkTypeLoki,
+		URL:     server.URL,
+		Headers: map[string]string{"X-Scope-OrgID": "secret"},
+	}, []types.LogEntry{suite.entry, suite.entry})
+	suite.NoError(err)
+
+	suite.Require().Len(body.Streams, 1)
+	suite.Equal("postgres", body.Streams[0].Stream[types.LogLabelServiceID])
+	suite.Equal(types.LogStreamStderr, body.Streams[0].Stream["stream"])
+	suite.Len(body.Streams[0].Values, 2)
+	suite.Equal("1697362200000000000", body.Streams[0].Values[0][0])
+}
+
+func (suite *LogForwarderNetAdapterTestSuite) TestSendHTTPError() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := suite.adapter.Send(types.LogSink{
+		Type: types.LogSinkTypeHTTP,
+		URL:  server.URL,
+	}, []types.LogEntry{suite.entry})
+	suite.Error(err)
+}
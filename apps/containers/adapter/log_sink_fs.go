@@ -0,0 +1,117 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vlog"
+)
+
+type LogSinkFSAdapter struct {
+	sinks      types.LogSinks
+	sinksMutex sync.RWMutex
+
+	logsPath string
+}
+
+type LogSinkFSAdapterParams struct {
+	LogsPath string
+}
+
+func NewLogSinkFSAdapter(params *LogSinkFSAdapterParams) port.LogSinkAdapter {
+	if params == nil {
+		params = &LogSinkFSAdapterParams{}
+	}
+	if params.LogsPath == "" {
+		params.LogsPath = path.Join(storage.Path, "apps", "vx-containers-logs")
+	}
+
+	err := os.MkdirAll(params.LogsPath, os.ModePerm)
+	if err != nil && !os.IsExist(err) {
+		log.Error(err,
+			vlog.String("message", "failed to create directory"),
+			vlog.String("path", params.LogsPath),
+		)
+		os.Exit(1)
+	}
+
+	a := &LogSinkFSAdapter{
+		sinks:    types.LogSinks{},
+		logsPath: params.LogsPath,
+	}
+
+	err = a.read()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Error(err)
+	}
+
+	return a
+}
+
+func (a *LogSinkFSAdapter) GetAll() types.LogSinks {
+	a.sinksMutex.RLock()
+	defer a.sinksMutex.RUnlock()
+
+	sinks := types.LogSinks{}
+	for id, sink := range a.sinks {
+		sinks[id] = sink
+	}
+	return sinks
+}
+
+func (a *LogSinkFSAdapter) Add(id uuid.UUID, sink types.LogSink) error {
+	func() {
+		a.sinksMutex.Lock()
+		defer a.sinksMutex.Unlock()
+		a.sinks[id] = sink
+	}()
+	return a.write()
+}
+
+func (a *LogSinkFSAdapter) Remove(id uuid.UUID) error {
+	err := func() error {
+		a.sinksMutex.Lock()
+		defer a.sinksMutex.Unlock()
+		if _, ok := a.sinks[id]; !ok {
+			return types.ErrLogSinkNotFound
+		}
+		delete(a.sinks, id)
+		return nil
+	}()
+	if err != nil {
+		return err
+	}
+	return a.write()
+}
+
+func (a *LogSinkFSAdapter) read() error {
+	file, err := os.ReadFile(path.Join(a.logsPath, "sinks.json"))
+	if err != nil {
+		return err
+	}
+
+	a.sinksMutex.Lock()
+	defer a.sinksMutex.Unlock()
+
+	return json.Unmarshal(file, &a.sinks)
+}
+
+func (a *LogSinkFSAdapter) write() error {
+	a.sinksMutex.RLock()
+	defer a.sinksMutex.RUnlock()
+
+	bytes, err := json.MarshalIndent(a.sinks, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path.Join(a.logsPath, "sinks.json"), bytes, os.ModePerm)
+}
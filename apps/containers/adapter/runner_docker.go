@@ -13,11 +13,11 @@ import (
 	"net/http"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/carlmjohnson/requests"
-	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/go-connections/nat"
 	"github.com/google/go-containerregistry/pkg/crane"
@@ -29,52 +29,77 @@ import (
 	"github.com/vertex-center/vlog"
 )
 
-type ContainerRunnerDockerAdapter struct{}
+type ContainerRunnerDockerAdapter struct {
+	// restarts contains, for each replica being restarted, a channel
+	// closed once it is restarted. The entry is removed by the watcher of
+	// the replica when it sees the replica stop.
+	restarts *sync.Map
+}
 
 func NewContainerRunnerFSAdapter() ContainerRunnerDockerAdapter {
-	return ContainerRunnerDockerAdapter{}
+	return ContainerRunnerDockerAdapter{
+		restarts: &sync.Map{},
+	}
 }
 
-func (a ContainerRunnerDockerAdapter) Delete(inst *containerstypes.Container) error {
-	id, err := a.getContainerID(*inst)
+func (a ContainerRunnerDockerAdapter) Delete(ctx context.Context, inst *containerstypes.Container) error {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
+	err := a.deleteReplicas(ctx, *inst, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := a.getContainerID(ctx, *inst)
 	if err != nil {
 		return err
 	}
 
 	apiError := router.Error{}
-	err = requests.URL(config.Current.KernelURL()).
+	err = api.KernelRequest().
 		Pathf("/api/docker/container/%s", id).
 		Delete().
 		ErrorJSON(&apiError).
-		Fetch(context.Background())
+		Fetch(ctx)
 
 	if apiError.Code == api.ErrContainerNotFound {
 		return ErrContainerNotFound
+	} else if err != nil {
+		return err
 	}
-	return err
+
+	a.deleteNetwork(ctx, *inst)
+	a.deleteStackNetwork(ctx, *inst)
+	return nil
 }
 
-func (a ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, setStatus func(status string)) (io.ReadCloser, io.ReadCloser, error) {
+func (a ContainerRunnerDockerAdapter) Start(ctx context.Context, inst *containerstypes.Container, setStatus func(status string)) (io.ReadCloser, io.ReadCloser, error) {
 	rErr, wErr := io.Pipe()
 	rOut, wOut := io.Pipe()
 
 	go func() {
+		defer wOut.Close()
+		defer wErr.Close()
+
 		imageName := inst.DockerImageVertexName()
 
 		setStatus(containerstypes.ContainerStatusBuilding)
 
-		containerPath := a.getPath(*inst)
+		containerPath := a.getPath(ctx, *inst)
 		service := inst.Service
 
 		log.Debug("building image", vlog.String("image", imageName))
 
 		// Build
 		var err error
-		var stdout, stderr io.ReadCloser
+		var stdout io.ReadCloser
+		imageCtx, cancelImage := context.WithTimeout(ctx, config.Current.Timeouts.Image)
+		defer cancelImage()
 		if service.Methods.Docker.Dockerfile != nil {
-			stdout, err = a.buildImageFromDockerfile(containerPath, imageName)
+			stdout, err = a.buildImageFromDockerfile(imageCtx, containerPath, imageName)
 		} else if service.Methods.Docker.Image != nil {
-			stdout, err = a.buildImageFromName(inst.GetImageNameWithTag())
+			stdout, err = a.buildImageFromName(imageCtx, inst.GetImageNameWithTag())
 		} else {
 			err = errors.New("no Docker methods found")
 		}
@@ -151,190 +176,197 @@ func (a ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, set
 		log.Info("waiting for image to be built", vlog.String("uuid", inst.UUID.String()))
 
 		wg.Wait()
+		cancelImage()
 
 		log.Info("image built", vlog.String("uuid", inst.UUID.String()))
 
-		// Create
-		id, err := a.getContainerID(*inst)
-		if errors.Is(err, ErrContainerNotFound) {
-			containerName := inst.DockerContainerName()
-
-			log.Info("container doesn't exists, create it.",
-				vlog.String("container_name", containerName),
-			)
-
-			options := types.CreateContainerOptions{
-				ContainerName: containerName,
-				ExposedPorts:  nat.PortSet{},
-				PortBindings:  nat.PortMap{},
-				Binds:         []string{},
-				Env:           []string{},
-				CapAdd:        []string{},
-			}
-
-			// exposedPorts and portBindings
-			if service.Methods.Docker.Ports != nil {
-				var all []string
-
-				for in, out := range *service.Methods.Docker.Ports {
-					for _, e := range service.Env {
-						if e.Type == "port" && e.Default == out {
-							out = inst.Env[e.Name]
-							all = append(all, out+":"+in)
-							break
-						}
-					}
-				}
-
-				options.ExposedPorts, options.PortBindings, err = nat.ParsePortSpecs(all)
-				if err != nil {
-					return
-				}
-			}
-
-			// binds
-			if service.Methods.Docker.Volumes != nil {
-				for source, target := range *service.Methods.Docker.Volumes {
-					if !strings.HasPrefix(source, "/") {
-						source, err = filepath.Abs(path.Join(containerPath, "volumes", source))
-					}
-					if err != nil {
-						return
-					}
-					options.Binds = append(options.Binds, source+":"+target)
-				}
-			}
-
-			// env
-			if service.Methods.Docker.Environment != nil {
-				for in, out := range *service.Methods.Docker.Environment {
-					value := inst.Env[out]
-					options.Env = append(options.Env, in+"="+value)
-				}
-			}
-
-			// capAdd
-			if service.Methods.Docker.Capabilities != nil {
-				options.CapAdd = *service.Methods.Docker.Capabilities
-			}
+		// Network
+		err = a.createNetwork(ctx, *inst)
+		if err != nil {
+			log.Error(err)
+			setStatus(containerstypes.ContainerStatusError)
+			return
+		}
 
-			// sysctls
-			if service.Methods.Docker.Sysctls != nil {
-				options.Sysctls = *service.Methods.Docker.Sysctls
-			}
+		// Volumes
+		err = a.createVolumes(ctx, *inst)
+		if err != nil {
+			log.Error(err)
+			setStatus(containerstypes.ContainerStatusError)
+			return
+		}
 
-			// cmd
-			if service.Methods.Docker.Cmd != nil {
-				options.Cmd = strings.Split(*service.Methods.Docker.Cmd, " ")
-			}
+		// The replicas removed from the settings are deleted.
+		replicas := inst.GetReplicas()
+		err = a.deleteReplicas(ctx, *inst, replicas)
+		if err != nil {
+			log.Error(err)
+			setStatus(containerstypes.ContainerStatusError)
+			return
+		}
 
-			if service.Methods.Docker.Dockerfile != nil {
-				options.ImageName = inst.DockerImageVertexName()
-				id, err = a.createContainer(options)
-			} else if service.Methods.Docker.Image != nil {
-				options.ImageName = inst.GetImageNameWithTag()
-				id, err = a.createContainer(options)
+		// Create
+		ids := map[int]string{}
+		for replica := 0; replica < replicas; replica++ {
+			ids[replica], err = a.getReplicaID(ctx, *inst, replica)
+			if errors.Is(err, ErrContainerNotFound) {
+				ids[replica], err = a.createReplica(ctx, *inst, containerPath, replica)
 			}
 			if err != nil {
+				log.Error(err)
+				setStatus(containerstypes.ContainerStatusError)
 				return
 			}
-		} else if err != nil {
-			return
 		}
 
-		// Start
-		err = requests.URL(config.Current.KernelURL()).
-			Pathf("/api/docker/container/%s/start", id).
-			Post().
-			Fetch(context.Background())
+		err = a.connectNetwork(ctx, *inst)
 		if err != nil {
+			log.Error(err)
 			setStatus(containerstypes.ContainerStatusError)
 			return
 		}
-		setStatus(containerstypes.ContainerStatusRunning)
 
-		stdout, stderr, err = a.readLogs(id)
+		err = a.connectStack(ctx, *inst)
 		if err != nil {
+			log.Error(err)
+			setStatus(containerstypes.ContainerStatusError)
 			return
 		}
 
-		go func() {
-			defer stdout.Close()
-			defer wOut.Close()
-
-			_, err := io.Copy(wOut, stdout)
+		// Start
+		for _, id := range ids {
+			startCtx, cancelStart := withKernelTimeout(ctx)
+			err = api.KernelRequest().
+				Pathf("/api/docker/container/%s/start", id).
+				Post().
+				Fetch(startCtx)
+			cancelStart()
 			if err != nil {
 				log.Error(err)
+				setStatus(containerstypes.ContainerStatusError)
 				return
 			}
-		}()
+		}
+		setStatus(containerstypes.ContainerStatusRunning)
 
-		go func() {
-			defer stderr.Close()
-			defer wErr.Close()
+		a.watchReplicas(ctx, *inst, ids, wOut, wErr, setStatus)
+	}()
+	return rOut, rErr, nil
+}
 
-			_, err := io.Copy(wOut, stdout)
-			if err != nil {
-				log.Error(err)
-				return
-			}
-		}()
+// Attach reads the logs of the replicas that are already running, and waits
+// for them to stop like Start does. It returns ErrContainerNotRunning if no
+// replica is running.
+func (a ContainerRunnerDockerAdapter) Attach(ctx context.Context, inst *containerstypes.Container, setStatus func(status string)) (io.ReadCloser, io.ReadCloser, error) {
+	replicas, err := a.getReplicas(ctx, *inst)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		err = a.WaitCondition(inst, types.WaitContainerCondition(container.WaitConditionNotRunning))
-		if err != nil {
-			log.Error(err)
-			setStatus(containerstypes.ContainerStatusError)
-		} else {
-			setStatus(containerstypes.ContainerStatusOff)
+	ids := map[int]string{}
+	for replica, c := range replicas {
+		if c.State == "running" {
+			ids[replica] = c.ID
 		}
+	}
+	if len(ids) == 0 {
+		return nil, nil, ErrContainerNotRunning
+	}
+
+	rErr, wErr := io.Pipe()
+	rOut, wOut := io.Pipe()
+
+	go func() {
+		defer wOut.Close()
+		defer wErr.Close()
+		a.watchReplicas(ctx, *inst, ids, wOut, wErr, setStatus)
 	}()
 
 	return rOut, rErr, nil
 }
 
-func (a ContainerRunnerDockerAdapter) Stop(inst *containerstypes.Container) error {
-	id, err := a.getContainerID(*inst)
+// Stop stops the Docker containers of all the replicas. The containers are
+// given the stop timeout of their service, or the default one, to stop
+// gracefully before they are killed.
+func (a ContainerRunnerDockerAdapter) Stop(ctx context.Context, inst *containerstypes.Container) error {
+	replicas, err := a.getReplicas(ctx, *inst)
 	if err != nil {
 		return err
 	}
+	if len(replicas) == 0 {
+		return ErrContainerNotFound
+	}
 
-	return requests.URL(config.Current.KernelURL()).
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+		errs  []error
+	)
+	for _, c := range replicas {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			err := a.stopReplica(ctx, *inst, id)
+			if err != nil {
+				mutex.Lock()
+				errs = append(errs, err)
+				mutex.Unlock()
+			}
+		}(c.ID)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (a ContainerRunnerDockerAdapter) stopReplica(ctx context.Context, inst containerstypes.Container, id string) error {
+	timeout := int(config.Current.Timeouts.Stop.Seconds())
+	if inst.Service.Methods.Docker != nil && inst.Service.Methods.Docker.StopTimeout != nil {
+		timeout = *inst.Service.Methods.Docker.StopTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.Current.Timeouts.Kernel+time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	return api.KernelRequest().
 		Pathf("/api/docker/container/%s/stop", id).
+		Param("timeout", strconv.Itoa(timeout)).
 		Post().
-		Fetch(context.Background())
+		Fetch(ctx)
 }
 
-func (a ContainerRunnerDockerAdapter) Info(inst containerstypes.Container) (map[string]any, error) {
-	id, err := a.getContainerID(inst)
-	if err != nil {
-		return nil, err
+func (a ContainerRunnerDockerAdapter) Info(ctx context.Context, inst containerstypes.Container) (containerstypes.ContainerInfo, error) {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
+	info := containerstypes.ContainerInfo{
+		Version: types.InfoVersion,
 	}
 
-	var info types.InfoContainerResponse
-	err = requests.URL(config.Current.KernelURL()).
-		Pathf("/api/docker/container/%s/info", id).
-		ToJSON(&info).
-		Fetch(context.Background())
+	id, err := a.getContainerID(ctx, inst)
 	if err != nil {
-		return nil, err
+		return info, err
 	}
 
-	var imageInfo types.InfoImageResponse
-	err = requests.URL(config.Current.KernelURL()).
-		Pathf("/api/docker/image/%s/info", info.Image).
-		ToJSON(&imageInfo).
-		Fetch(context.Background())
+	err = api.KernelRequest().
+		Pathf("/api/docker/container/%s/info", id).
+		ToJSON(&info.Container).
+		Fetch(ctx)
 	if err != nil {
-		return nil, err
+		return info, err
 	}
 
-	return map[string]any{
-		"container": info,
-		"image":     imageInfo,
-	}, nil
+	err = api.KernelRequest().
+		Pathf("/api/docker/image/%s/info", info.Container.Image).
+		ToJSON(&info.Image).
+		Fetch(ctx)
+	return info, err
 }
 
-func (a ContainerRunnerDockerAdapter) CheckForUpdates(inst *containerstypes.Container) error {
+func (a ContainerRunnerDockerAdapter) CheckForUpdates(ctx context.Context, inst *containerstypes.Container) error {
+	ctx, cancel := context.WithTimeout(ctx, config.Current.Timeouts.Image)
+	defer cancel()
+
 	service := inst.Service
 
 	if service.Methods.Docker.Image == nil {
@@ -344,24 +376,24 @@ func (a ContainerRunnerDockerAdapter) CheckForUpdates(inst *containerstypes.Cont
 
 	imageName := inst.GetImageNameWithTag()
 
-	res, err := a.pullImage(imageName)
+	res, err := a.pullImage(ctx, imageName)
 	if err != nil {
 		return err
 	}
 	defer res.Close()
 
 	var imageInfo types.InfoImageResponse
-	err = requests.URL(config.Current.KernelURL()).
+	err = api.KernelRequest().
 		Pathf("/api/docker/%s/info", imageName).
 		ToJSON(&imageInfo).
-		Fetch(context.Background())
+		Fetch(ctx)
 	if err != nil {
 		return err
 	}
 
 	latestImageID := imageInfo.ID
 
-	currentImageID, err := a.getImageID(*inst)
+	currentImageID, err := a.getImageID(ctx, *inst)
 	if err != nil {
 		return err
 	}
@@ -384,39 +416,63 @@ func (a ContainerRunnerDockerAdapter) CheckForUpdates(inst *containerstypes.Cont
 	return nil
 }
 
-func (a ContainerRunnerDockerAdapter) GetAllVersions(inst containerstypes.Container) ([]string, error) {
-	if inst.Service.Methods.Docker == nil {
-		return nil, errors.New("no Docker methods found")
+func (a ContainerRunnerDockerAdapter) GetAllVersions(ctx context.Context, inst containerstypes.Container) ([]string, error) {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
+	if inst.Service.Methods.Docker == nil || inst.Service.Methods.Docker.Image == nil {
+		return nil, errors.New("no Docker image found")
 	}
 	image := *inst.Service.Methods.Docker.Image
 	log.Debug("querying all versions of image",
 		vlog.String("image", image),
 	)
-	return crane.ListTags(image)
+	return crane.ListTags(image, crane.WithContext(ctx))
+}
+
+func (a ContainerRunnerDockerAdapter) GetImageTag(ctx context.Context, inst containerstypes.Container) (string, error) {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
+	c, err := a.getContainer(ctx, inst)
+	if err != nil {
+		return "", err
+	}
+
+	// The image name can contain a registry port, like "host:5000/image:tag".
+	image := c.Image[strings.LastIndex(c.Image, "/")+1:]
+	_, tag, found := strings.Cut(image, ":")
+	if !found {
+		return "latest", nil
+	}
+	return tag, nil
 }
 
-func (a ContainerRunnerDockerAdapter) HasUpdateAvailable(inst containerstypes.Container) (bool, error) {
+func (a ContainerRunnerDockerAdapter) HasUpdateAvailable(ctx context.Context, inst containerstypes.Container) (bool, error) {
 	//TODO implement me
 	return false, nil
 }
 
-func (a ContainerRunnerDockerAdapter) WaitCondition(inst *containerstypes.Container, cond types.WaitContainerCondition) error {
-	id, err := a.getContainerID(*inst)
+func (a ContainerRunnerDockerAdapter) WaitCondition(ctx context.Context, inst *containerstypes.Container, cond types.WaitContainerCondition) error {
+	id, err := a.getContainerID(ctx, *inst)
 	if err != nil {
 		return err
 	}
 
-	return requests.URL(config.Current.KernelURL()).
+	return api.KernelRequest().
 		Pathf("/api/docker/container/%s/wait/%s", id, cond).
-		Fetch(context.Background())
+		Fetch(ctx)
 }
 
-func (a ContainerRunnerDockerAdapter) getContainer(inst containerstypes.Container) (types.Container, error) {
+func (a ContainerRunnerDockerAdapter) getContainer(ctx context.Context, inst containerstypes.Container) (types.Container, error) {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
 	var containers []types.Container
-	err := requests.URL(config.Current.KernelURL()).
+	err := api.KernelRequest().
 		Path("/api/docker/containers").
 		ToJSON(&containers).
-		Fetch(context.Background())
+		Fetch(ctx)
 	if err != nil {
 		return types.Container{}, err
 	}
@@ -437,35 +493,35 @@ func (a ContainerRunnerDockerAdapter) getContainer(inst containerstypes.Containe
 	return *dockerContainer, nil
 }
 
-func (a ContainerRunnerDockerAdapter) getContainerID(inst containerstypes.Container) (string, error) {
-	c, err := a.getContainer(inst)
+func (a ContainerRunnerDockerAdapter) getContainerID(ctx context.Context, inst containerstypes.Container) (string, error) {
+	c, err := a.getContainer(ctx, inst)
 	if err != nil {
 		return "", err
 	}
 	return c.ID, nil
 }
 
-func (a ContainerRunnerDockerAdapter) getImageID(inst containerstypes.Container) (string, error) {
-	c, err := a.getContainer(inst)
+func (a ContainerRunnerDockerAdapter) getImageID(ctx context.Context, inst containerstypes.Container) (string, error) {
+	c, err := a.getContainer(ctx, inst)
 	if err != nil {
 		return "", err
 	}
 	return c.ImageID, nil
 }
 
-func (a ContainerRunnerDockerAdapter) pullImage(imageName string) (io.ReadCloser, error) {
+func (a ContainerRunnerDockerAdapter) pullImage(ctx context.Context, imageName string) (io.ReadCloser, error) {
 	options := types.PullImageOptions{Image: imageName}
 
-	req, err := requests.URL(config.Current.KernelURL()).
+	req, err := api.KernelRequest().
 		Path("/api/docker/image/pull").
 		Post().
 		BodyJSON(options).
-		Request(context.Background())
+		Request(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := api.KernelClient().Do(req)
 	if err != nil {
 		return nil, err
 	} else if res.StatusCode >= 200 && res.StatusCode < 300 {
@@ -474,46 +530,170 @@ func (a ContainerRunnerDockerAdapter) pullImage(imageName string) (io.ReadCloser
 	return nil, errors.New("failed to pull image")
 }
 
-func (a ContainerRunnerDockerAdapter) buildImageFromName(imageName string) (io.ReadCloser, error) {
-	res, err := a.pullImage(imageName)
+func (a ContainerRunnerDockerAdapter) buildImageFromName(ctx context.Context, imageName string) (io.ReadCloser, error) {
+	res, err := a.pullImage(ctx, imageName)
 	if err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
-func (a ContainerRunnerDockerAdapter) buildImageFromDockerfile(containerPath string, imageName string) (io.ReadCloser, error) {
+func (a ContainerRunnerDockerAdapter) buildImageFromDockerfile(ctx context.Context, containerPath string, imageName string) (io.ReadCloser, error) {
 	options := types.BuildImageOptions{
 		Dir:        containerPath,
 		Name:       imageName,
 		Dockerfile: "Dockerfile",
 	}
 
-	req, err := requests.URL(config.Current.KernelURL()).
+	req, err := api.KernelRequest().
 		Pathf("/api/docker/image/build").
 		Post().
 		BodyJSON(options).
-		Request(context.Background())
+		Request(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := api.KernelClient().Do(req)
 	if err != nil {
-		log.Error(err)
+		return nil, err
+	} else if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return res.Body, nil
 	}
-	return res.Body, nil
+	res.Body.Close()
+	return nil, fmt.Errorf("failed to build image: %s", res.Status)
 }
 
-func (a ContainerRunnerDockerAdapter) createContainer(options types.CreateContainerOptions) (string, error) {
+// dockerMounts adds the binds and the named volumes of the container to the
+// options. The relative binds are in the directory of the container.
+func dockerMounts(inst containerstypes.Container, containerPath string, options *types.CreateContainerOptions) error {
+	docker := inst.Service.Methods.Docker
+
+	if docker.Volumes != nil {
+		for source, target := range *docker.Volumes {
+			if !strings.HasPrefix(source, "/") {
+				var err error
+				source, err = filepath.Abs(path.Join(containerPath, "volumes", source))
+				if err != nil {
+					return err
+				}
+			}
+			options.Binds = append(options.Binds, source+":"+target)
+		}
+	}
+
+	if docker.NamedVolumes != nil {
+		for name, v := range *docker.NamedVolumes {
+			options.VolumeMounts = append(options.VolumeMounts, types.VolumeMount{
+				Name:     inst.DockerVolumeName(name),
+				Target:   v.Target,
+				ReadOnly: v.ReadOnly,
+			})
+		}
+	}
+	return nil
+}
+
+// createReplica creates the Docker container of a replica. The replicas
+// publish the ports of the container with an offset, see ReplicaPort.
+func (a ContainerRunnerDockerAdapter) createReplica(ctx context.Context, inst containerstypes.Container, containerPath string, replica int) (string, error) {
+	service := inst.Service
+	containerName := inst.DockerReplicaName(replica)
+
+	log.Info("container doesn't exists, create it.",
+		vlog.String("container_name", containerName),
+	)
+
+	options := types.CreateContainerOptions{
+		ContainerName: containerName,
+		ExposedPorts:  nat.PortSet{},
+		PortBindings:  nat.PortMap{},
+		Binds:         []string{},
+		Env:           []string{},
+		CapAdd:        []string{},
+		NetworkMode:   inst.DockerNetworkName(),
+	}
+
+	var err error
+
+	// exposedPorts and portBindings
+	if service.Methods.Docker.Ports != nil {
+		var all []string
+
+		for in, out := range *service.Methods.Docker.Ports {
+			for _, e := range service.Env {
+				if e.Type == "port" && e.Default == out {
+					out = containerstypes.ReplicaPort(inst.Env[e.Name], replica)
+					all = append(all, out+":"+in)
+					break
+				}
+			}
+		}
+
+		options.ExposedPorts, options.PortBindings, err = nat.ParsePortSpecs(all)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// binds and named volumes
+	err = dockerMounts(inst, containerPath, &options)
+	if err != nil {
+		return "", err
+	}
+
+	// tmpfs
+	if service.Methods.Docker.Tmpfs != nil {
+		options.Tmpfs = map[string]string{}
+		for target, t := range *service.Methods.Docker.Tmpfs {
+			options.Tmpfs[target] = t.Options()
+		}
+	}
+
+	// env
+	options.Env = dockerEnv(inst)
+
+	// capAdd
+	if service.Methods.Docker.Capabilities != nil {
+		options.CapAdd = *service.Methods.Docker.Capabilities
+	}
+
+	// sysctls
+	if service.Methods.Docker.Sysctls != nil {
+		options.Sysctls = *service.Methods.Docker.Sysctls
+	}
+
+	// cmd
+	if service.Methods.Docker.Cmd != nil {
+		options.Cmd = strings.Split(*service.Methods.Docker.Cmd, " ")
+	}
+
+	if service.Methods.Docker.Dockerfile != nil {
+		options.ImageName = inst.DockerImageVertexName()
+	} else if service.Methods.Docker.Image != nil {
+		options.ImageName = inst.GetImageNameWithTag()
+	} else {
+		return "", errors.New("no Docker methods found")
+	}
+	return a.createContainer(ctx, options)
+}
+
+func (a ContainerRunnerDockerAdapter) createContainer(ctx context.Context, options types.CreateContainerOptions) (string, error) {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
 	var res types.CreateContainerResponse
-	err := requests.URL(config.Current.KernelURL()).
+	apiError := router.Error{}
+	err := api.KernelRequest().
 		Pathf("/api/docker/container").
 		Post().
 		BodyJSON(options).
 		ToJSON(&res).
-		Fetch(context.Background())
-	if err != nil {
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	if apiError.Code == api.ErrKernelPolicyViolation {
+		return "", fmt.Errorf("%w: %s", ErrKernelPolicyViolation, apiError.PublicMessage)
+	} else if err != nil {
 		return "", err
 	}
 
@@ -525,18 +705,18 @@ func (a ContainerRunnerDockerAdapter) createContainer(options types.CreateContai
 	return res.ID, err
 }
 
-func (a ContainerRunnerDockerAdapter) readLogs(containerID string) (stdout io.ReadCloser, stderr io.ReadCloser, err error) {
+func (a ContainerRunnerDockerAdapter) readLogs(ctx context.Context, containerID string) (stdout io.ReadCloser, stderr io.ReadCloser, err error) {
 	var reqStdout, reqStderr *http.Request
-	reqStdout, err = requests.URL(config.Current.KernelURL()).
+	reqStdout, err = api.KernelRequest().
 		Pathf("/api/docker/container/%s/logs/stdout", containerID).
-		Request(context.Background())
+		Request(ctx)
 	if err != nil {
 		return
 	}
 
-	reqStderr, err = requests.URL(config.Current.KernelURL()).
+	reqStderr, err = api.KernelRequest().
 		Pathf("/api/docker/container/%s/logs/stderr", containerID).
-		Request(context.Background())
+		Request(ctx)
 	if err != nil {
 		return
 	}
@@ -545,7 +725,9 @@ func (a ContainerRunnerDockerAdapter) readLogs(containerID string) (stdout io.Re
 	rErr, wErr := io.Pipe()
 
 	go func() {
-		res, err := http.DefaultClient.Do(reqStdout)
+		defer wOut.Close()
+
+		res, err := api.KernelClient().Do(reqStdout)
 		if err != nil {
 			return
 		}
@@ -558,7 +740,9 @@ func (a ContainerRunnerDockerAdapter) readLogs(containerID string) (stdout io.Re
 	}()
 
 	go func() {
-		res, err := http.DefaultClient.Do(reqStderr)
+		defer wErr.Close()
+
+		res, err := api.KernelClient().Do(reqStderr)
 		if err != nil {
 			return
 		}
@@ -573,16 +757,36 @@ func (a ContainerRunnerDockerAdapter) readLogs(containerID string) (stdout io.Re
 	return rOut, rErr, nil
 }
 
-func (a ContainerRunnerDockerAdapter) getPath(inst containerstypes.Container) string {
+// dockerEnv returns the environment variables of the Docker container.
+func dockerEnv(inst containerstypes.Container) []string {
+	env := []string{}
+	if inst.Service.Methods.Docker.Environment != nil {
+		for in, out := range *inst.Service.Methods.Docker.Environment {
+			env = append(env, in+"="+inst.Env[out])
+		}
+	}
+	return env
+}
+
+// withKernelTimeout returns a context canceled after the timeout of the
+// short requests sent to the kernel.
+func withKernelTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, config.Current.Timeouts.Kernel)
+}
+
+func (a ContainerRunnerDockerAdapter) getPath(ctx context.Context, inst containerstypes.Container) string {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
 	base := storage.Path
 
 	// If Vertex is running itself inside Docker, the containers are stored in the Vertex container volume.
 	if vdocker.RunningInDocker() {
 		var containers []types.Container
-		err := requests.URL(config.Current.KernelURL()).
+		err := api.KernelRequest().
 			Path("/api/docker/containers").
 			ToJSON(&containers).
-			Fetch(context.Background())
+			Fetch(ctx)
 		if err != nil {
 			log.Error(err)
 		} else {
@@ -0,0 +1,77 @@
+package adapter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
+	"github.com/vertex-center/vertex/pkg/log"
+)
+
+var ErrEventStreamClosed = errors.New("the event stream was closed")
+
+func (a ContainerRunnerDockerAdapter) WatchEvents(ctx context.Context, handle func(e types.DockerEvent)) error {
+	req, err := api.KernelRequest().
+		Path("/api/docker/events").
+		Request(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := api.KernelClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to watch events: %s", res.Status)
+	}
+
+	return readDockerEvents(res.Body, handle)
+}
+
+// readDockerEvents reads the server-sent events of the kernel, and calls
+// handle for each Docker event. The open event is skipped.
+func readDockerEvents(r io.Reader, handle func(e types.DockerEvent)) error {
+	scanner := bufio.NewScanner(r)
+
+	var name string
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if v, ok := strings.CutPrefix(line, "event:"); ok {
+			name = strings.TrimSpace(v)
+			continue
+		} else if v, ok := strings.CutPrefix(line, "data:"); ok {
+			data.WriteString(strings.TrimPrefix(v, " "))
+			continue
+		} else if line != "" {
+			continue
+		}
+
+		if name != "" && name != "open" && data.Len() > 0 {
+			var e types.DockerEvent
+			err := json.Unmarshal([]byte(data.String()), &e)
+			if err != nil {
+				log.Error(err)
+			} else {
+				handle(e)
+			}
+		}
+		name = ""
+		data.Reset()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return ErrEventStreamClosed
+}
@@ -0,0 +1,35 @@
+package adapter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/core/types"
+)
+
+type DockerEventsTestSuite struct {
+	suite.Suite
+}
+
+func TestDockerEventsTestSuite(t *testing.T) {
+	suite.Run(t, new(DockerEventsTestSuite))
+}
+
+func (suite *DockerEventsTestSuite) TestReadDockerEvents() {
+	stream := "event:open\ndata:<nil>\n\n" +
+		"event:die\ndata:{\"action\":\"die\",\"container_name\":\"VERTEX_CONTAINER_1\",\"exit_code\":1}\n\n" +
+		"event:health_status\ndata: {\"action\":\"health_status\",\"health\":\"healthy\"}\n\n"
+
+	var events []types.DockerEvent
+	err := readDockerEvents(strings.NewReader(stream), func(e types.DockerEvent) {
+		events = append(events, e)
+	})
+
+	suite.ErrorIs(err, ErrEventStreamClosed)
+	suite.Require().Len(events, 2)
+	suite.Equal(types.DockerEventDie, events[0].Action)
+	suite.Equal("VERTEX_CONTAINER_1", events[0].ContainerName)
+	suite.Equal(1, *events[0].ExitCode)
+	suite.Equal("healthy", events[1].Health)
+}
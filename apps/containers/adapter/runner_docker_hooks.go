@@ -0,0 +1,248 @@
+package adapter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/google/uuid"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/config"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+// RunHook runs a lifecycle hook of the container, and calls output for each
+// line it writes. It returns ErrHookFailed if the hook exits with a non-zero
+// code, or if the HTTP call doesn't succeed.
+func (a ContainerRunnerDockerAdapter) RunHook(ctx context.Context, inst *containerstypes.Container, hook containerstypes.Hook, output func(line string)) error {
+	switch {
+	case hook.Exec != nil:
+		return a.runHookExec(ctx, *inst, *hook.Exec, output)
+	case hook.Container != nil:
+		return a.runHookContainer(ctx, *inst, *hook.Container, output)
+	case hook.HTTP != nil:
+		return a.runHookHTTP(ctx, *inst, *hook.HTTP, output)
+	}
+	return containerstypes.ErrInvalidHook
+}
+
+func (a ContainerRunnerDockerAdapter) runHookExec(ctx context.Context, inst containerstypes.Container, exec containerstypes.HookExec, output func(line string)) error {
+	id, err := a.getContainerID(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	var res types.ExecContainerResponse
+	err = api.KernelRequest().
+		Pathf("/api/docker/container/%s/exec", id).
+		Post().
+		BodyJSON(types.ExecContainerOptions{Cmd: exec.Cmd}).
+		ToJSON(&res).
+		Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	writeLines(strings.NewReader(res.Stdout), output)
+	writeLines(strings.NewReader(res.Stderr), output)
+
+	if res.ExitCode != 0 {
+		return fmt.Errorf("%w: exit code %d", containerstypes.ErrHookFailed, res.ExitCode)
+	}
+	return nil
+}
+
+// runHookContainer runs a helper container in the network of the container,
+// with its binds and its named volumes, waits until it exits, and deletes it.
+func (a ContainerRunnerDockerAdapter) runHookContainer(ctx context.Context, inst containerstypes.Container, hook containerstypes.HookContainer, output func(line string)) error {
+	image := hook.Image
+	if image == "" && inst.Service.Methods.Docker.Dockerfile != nil {
+		image = inst.DockerImageVertexName()
+	} else if image == "" {
+		image = inst.GetImageNameWithTag()
+	}
+
+	err := a.createNetwork(ctx, inst)
+	if err != nil {
+		return err
+	}
+	err = a.connectDatabases(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	// The pre-start hooks run before the volumes are created by the start.
+	err = a.createVolumes(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	options := types.CreateContainerOptions{
+		ImageName:     image,
+		ContainerName: fmt.Sprintf("%s_HOOK_%s", inst.DockerContainerName(), uuid.New().String()[:8]),
+		Env:           dockerEnv(inst),
+		Cmd:           hook.Cmd,
+		NetworkMode:   inst.DockerNetworkName(),
+	}
+	err = dockerMounts(inst, a.getPath(ctx, inst), &options)
+	if err != nil {
+		return err
+	}
+
+	exitCode, err := a.runHelperContainer(ctx, inst, options, output)
+	if err != nil {
+		return err
+	}
+
+	if exitCode != 0 {
+		return fmt.Errorf("%w: exit code %d", containerstypes.ErrHookFailed, exitCode)
+	}
+	return nil
+}
+
+// runHelperContainer runs a one-shot container, waits until it exits, and
+// deletes it. It calls output for each line written by the container, and
+// returns its exit code.
+func (a ContainerRunnerDockerAdapter) runHelperContainer(ctx context.Context, inst containerstypes.Container, options types.CreateContainerOptions, output func(line string)) (int, error) {
+	if options.ImageName != inst.DockerImageVertexName() {
+		res, err := a.pullImage(ctx, options.ImageName)
+		if err != nil {
+			return 0, err
+		}
+		_, err = io.Copy(io.Discard, res)
+		res.Close()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	id, err := a.createContainer(ctx, options)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		// The context may be done, but the helper container must be deleted.
+		ctx, cancel := withKernelTimeout(context.Background())
+		defer cancel()
+		err := api.KernelRequest().
+			Pathf("/api/docker/container/%s", id).
+			Delete().
+			Fetch(ctx)
+		if err != nil {
+			log.Warn("failed to delete the helper container",
+				vlog.String("uuid", inst.UUID.String()),
+				vlog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	err = api.KernelRequest().
+		Pathf("/api/docker/container/%s/start", id).
+		Post().
+		Fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	err = api.KernelRequest().
+		Pathf("/api/docker/container/%s/wait/%s", id, container.WaitConditionNotRunning).
+		Fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, stream := range []string{"stdout", "stderr"} {
+		req, err := api.KernelRequest().
+			Pathf("/api/docker/container/%s/logs/%s", id, stream).
+			Param("tail", "all").
+			Request(ctx)
+		if err != nil {
+			return 0, err
+		}
+		logs, err := api.KernelClient().Do(req)
+		if err != nil {
+			return 0, err
+		}
+		writeLines(logs.Body, func(line string) {
+			_, line = containerstypes.ParseLogTimestamp(line)
+			output(line)
+		})
+		logs.Body.Close()
+	}
+
+	var info types.InfoContainerResponse
+	err = api.KernelRequest().
+		Pathf("/api/docker/container/%s/info", id).
+		ToJSON(&info).
+		Fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return info.State.ExitCode, nil
+}
+
+// runHookHTTP sends a request to the port of the container published on
+// the host. If the container has replicas, the request is sent to each of
+// them, the same way the proxy resolves the targets of a redirect.
+func (a ContainerRunnerDockerAdapter) runHookHTTP(ctx context.Context, inst containerstypes.Container, hook containerstypes.HookHTTP, output func(line string)) error {
+	method := hook.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	target := &url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(config.Current.Host, inst.Env[hook.Port]),
+		Path:   "/" + strings.TrimPrefix(hook.Path, "/"),
+	}
+	targets := inst.ReplicaTargets(target)
+	if targets == nil {
+		targets = []*url.URL{target}
+	}
+
+	for _, target := range targets {
+		err := sendHookRequest(ctx, method, target, output)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendHookRequest(ctx context.Context, method string, target *url.URL, output func(line string)) error {
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	output(fmt.Sprintf("%s %s: %s", method, target, res.Status))
+	writeLines(res.Body, output)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s", containerstypes.ErrHookFailed, res.Status)
+	}
+	return nil
+}
+
+func writeLines(r io.Reader, output func(line string)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		output(scanner.Text())
+	}
+}
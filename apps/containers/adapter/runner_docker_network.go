@@ -0,0 +1,168 @@
+package adapter
+
+import (
+	"context"
+
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/router"
+	"github.com/vertex-center/vlog"
+)
+
+// createNetwork creates the private network of the container, if it
+// doesn't exist yet.
+func (a ContainerRunnerDockerAdapter) createNetwork(ctx context.Context, inst containerstypes.Container) error {
+	return a.createDockerNetwork(ctx, types.CreateNetworkOptions{
+		Name: inst.DockerNetworkName(),
+		Labels: map[string]string{
+			"vertex.container": inst.UUID.String(),
+		},
+	})
+}
+
+func (a ContainerRunnerDockerAdapter) createDockerNetwork(ctx context.Context, options types.CreateNetworkOptions) error {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
+	apiError := router.Error{}
+	err := api.KernelRequest().
+		Path("/api/docker/network").
+		Post().
+		BodyJSON(options).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	if apiError.Code == api.ErrNetworkAlreadyExists {
+		return nil
+	}
+	return err
+}
+
+// connectStack connects the replicas of the container to the network of
+// its stack, where the other members reach it by its member name.
+func (a ContainerRunnerDockerAdapter) connectStack(ctx context.Context, inst containerstypes.Container) error {
+	if inst.Stack == nil {
+		return nil
+	}
+
+	err := a.createDockerNetwork(ctx, types.CreateNetworkOptions{
+		Name: inst.DockerStackNetworkName(),
+		Labels: map[string]string{
+			"vertex.stack": inst.Stack.UUID.String(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for replica := 0; replica < inst.GetReplicas(); replica++ {
+		err := a.connectContainer(ctx, inst.DockerStackNetworkName(), inst.DockerReplicaName(replica), inst.Stack.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteStackNetwork deletes the network of the stack of the container.
+// Docker refuses to delete it while other members are connected, so it is
+// only deleted with the last member of the stack.
+func (a ContainerRunnerDockerAdapter) deleteStackNetwork(ctx context.Context, inst containerstypes.Container) {
+	if inst.Stack == nil {
+		return
+	}
+
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
+	_ = api.KernelRequest().
+		Pathf("/api/docker/network/%s", inst.DockerStackNetworkName()).
+		Delete().
+		Fetch(ctx)
+}
+
+// connectNetwork connects the container and its databases to its private
+// network. The containers created before the private networks are
+// connected too.
+func (a ContainerRunnerDockerAdapter) connectNetwork(ctx context.Context, inst containerstypes.Container) error {
+	err := a.connectContainer(ctx, inst.DockerNetworkName(), inst.DockerContainerName(), inst.DockerNetworkAlias())
+	if err != nil {
+		return err
+	}
+	return a.connectDatabases(ctx, inst)
+}
+
+// connectDatabases connects the databases of the container to its private
+// network.
+func (a ContainerRunnerDockerAdapter) connectDatabases(ctx context.Context, inst containerstypes.Container) error {
+	for _, dbUUID := range inst.Databases {
+		db := containerstypes.Container{UUID: dbUUID}
+		err := a.connectContainer(ctx, inst.DockerNetworkName(), db.DockerContainerName(), db.DockerNetworkAlias())
+		if err == ErrContainerNotFound {
+			log.Warn("the database is not created yet, so it cannot be connected to the container network",
+				vlog.String("uuid", inst.UUID.String()),
+				vlog.String("database_uuid", dbUUID.String()),
+			)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a ContainerRunnerDockerAdapter) connectContainer(ctx context.Context, network string, container string, alias string) error {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
+	apiError := router.Error{}
+	err := api.KernelRequest().
+		Pathf("/api/docker/network/%s/connect", network).
+		Post().
+		BodyJSON(types.ConnectNetworkOptions{
+			Container: container,
+			Aliases:   []string{alias},
+		}).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+
+	switch apiError.Code {
+	case api.ErrNetworkAlreadyConnected:
+		return nil
+	case api.ErrNetworkNotFound:
+		return ErrContainerNotFound
+	}
+	return err
+}
+
+// deleteNetwork disconnects the databases from the private network of the
+// container, and deletes it. Failures are only logged, because the network
+// is not needed anymore.
+func (a ContainerRunnerDockerAdapter) deleteNetwork(ctx context.Context, inst containerstypes.Container) {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
+	for _, dbUUID := range inst.Databases {
+		db := containerstypes.Container{UUID: dbUUID}
+		_ = api.KernelRequest().
+			Pathf("/api/docker/network/%s/disconnect", inst.DockerNetworkName()).
+			Post().
+			BodyJSON(types.DisconnectNetworkOptions{
+				Container: db.DockerContainerName(),
+			}).
+			Fetch(ctx)
+	}
+
+	apiError := router.Error{}
+	err := api.KernelRequest().
+		Pathf("/api/docker/network/%s", inst.DockerNetworkName()).
+		Delete().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	if err != nil && apiError.Code != api.ErrNetworkNotFound {
+		log.Warn("failed to delete the container network",
+			vlog.String("uuid", inst.UUID.String()),
+			vlog.String("error", err.Error()),
+		)
+	}
+}
@@ -0,0 +1,227 @@
+package adapter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/docker/docker/api/types/container"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+// RollingRestart restarts the replicas of the container one after the
+// other, so that the other replicas keep serving while one is restarting.
+// The status of the container is not changed.
+func (a ContainerRunnerDockerAdapter) RollingRestart(ctx context.Context, inst *containerstypes.Container) error {
+	replicas, err := a.getReplicas(ctx, *inst)
+	if err != nil {
+		return err
+	}
+
+	var indexes []int
+	for replica, c := range replicas {
+		if c.State == "running" {
+			indexes = append(indexes, replica)
+		}
+	}
+	if len(indexes) == 0 {
+		return ErrContainerNotRunning
+	}
+	sort.Ints(indexes)
+
+	for _, replica := range indexes {
+		log.Info("restarting replica",
+			vlog.String("uuid", inst.UUID.String()),
+			vlog.Int("replica", replica),
+		)
+
+		err := a.restartReplica(ctx, *inst, replicas[replica].ID)
+		if err != nil {
+			return fmt.Errorf("replica %d: %w", replica, err)
+		}
+	}
+	return nil
+}
+
+func (a ContainerRunnerDockerAdapter) restartReplica(ctx context.Context, inst containerstypes.Container, id string) error {
+	// The watcher of the replica waits for this channel instead of
+	// considering the replica as stopped. The watcher removes the entry
+	// itself, so that it can't miss it if the restart is faster than
+	// the watcher.
+	restarted := make(chan struct{})
+	a.restarts.Store(id, restarted)
+	defer close(restarted)
+
+	err := a.stopReplica(ctx, inst, id)
+	if err != nil {
+		// The replica was not stopped, so the watcher will not remove
+		// the entry.
+		a.restarts.Delete(id)
+		return err
+	}
+
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
+	return api.KernelRequest().
+		Pathf("/api/docker/container/%s/start", id).
+		Post().
+		Fetch(ctx)
+}
+
+// getReplicas returns the Docker containers of the replicas of the
+// container, by replica.
+func (a ContainerRunnerDockerAdapter) getReplicas(ctx context.Context, inst containerstypes.Container) (map[int]types.Container, error) {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
+	var containers []types.Container
+	err := api.KernelRequest().
+		Path("/api/docker/containers").
+		ToJSON(&containers).
+		Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := map[int]types.Container{}
+	for _, c := range containers {
+		if len(c.Names) == 0 {
+			continue
+		}
+		id, replica, ok := containerstypes.ParseDockerReplicaName(c.Names[0])
+		if ok && id == inst.UUID {
+			replicas[replica] = c
+		}
+	}
+	return replicas, nil
+}
+
+func (a ContainerRunnerDockerAdapter) getReplicaID(ctx context.Context, inst containerstypes.Container, replica int) (string, error) {
+	replicas, err := a.getReplicas(ctx, inst)
+	if err != nil {
+		return "", err
+	}
+	c, ok := replicas[replica]
+	if !ok {
+		return "", ErrContainerNotFound
+	}
+	return c.ID, nil
+}
+
+// deleteReplicas deletes the Docker containers of the replicas starting
+// from the replica from.
+func (a ContainerRunnerDockerAdapter) deleteReplicas(ctx context.Context, inst containerstypes.Container, from int) error {
+	replicas, err := a.getReplicas(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	for replica, c := range replicas {
+		if replica < from {
+			continue
+		}
+
+		log.Info("deleting replica",
+			vlog.String("uuid", inst.UUID.String()),
+			vlog.Int("replica", replica),
+		)
+
+		err := api.KernelRequest().
+			Pathf("/api/docker/container/%s", c.ID).
+			Delete().
+			Fetch(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchReplicas writes the logs of the replicas in wOut and wErr, until
+// all of them are stopped. The status is then set to off, or to error if
+// one of the replicas could not be watched.
+func (a ContainerRunnerDockerAdapter) watchReplicas(ctx context.Context, inst containerstypes.Container, ids map[int]string, wOut io.Writer, wErr io.Writer, setStatus func(status string)) {
+	var (
+		wg     sync.WaitGroup
+		failed atomic.Bool
+	)
+
+	for replica, id := range ids {
+		wg.Add(1)
+		go func(replica int, id string) {
+			defer wg.Done()
+			err := a.followReplica(ctx, replica, id, wOut, wErr)
+			if err != nil {
+				log.Error(err,
+					vlog.String("uuid", inst.UUID.String()),
+					vlog.Int("replica", replica),
+				)
+				failed.Store(true)
+			}
+		}(replica, id)
+	}
+	wg.Wait()
+
+	if failed.Load() {
+		setStatus(containerstypes.ContainerStatusError)
+	} else {
+		setStatus(containerstypes.ContainerStatusOff)
+	}
+}
+
+// followReplica writes the logs of a replica until it stops. A replica
+// stopped by a rolling restart is followed again once it is restarted.
+func (a ContainerRunnerDockerAdapter) followReplica(ctx context.Context, replica int, id string, wOut io.Writer, wErr io.Writer) error {
+	for {
+		stdout, stderr, err := a.readLogs(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			copyReplicaLogs(replica, stdout, wOut)
+		}()
+		go func() {
+			defer wg.Done()
+			copyReplicaLogs(replica, stderr, wErr)
+		}()
+
+		err = api.KernelRequest().
+			Pathf("/api/docker/container/%s/wait/%s", id, container.WaitConditionNotRunning).
+			Fetch(ctx)
+		if err != nil {
+			return err
+		}
+		wg.Wait()
+
+		restarted, ok := a.restarts.LoadAndDelete(id)
+		if !ok {
+			return nil
+		}
+		<-restarted.(chan struct{})
+	}
+}
+
+func copyReplicaLogs(replica int, r io.ReadCloser, w io.Writer) {
+	defer r.Close()
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		_, err := fmt.Fprintln(w, containerstypes.FormatLogReplica(replica, scanner.Text()))
+		if err != nil {
+			return
+		}
+	}
+}
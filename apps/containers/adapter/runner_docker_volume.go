@@ -0,0 +1,251 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
+	"github.com/vertex-center/vertex/pkg/router"
+	"github.com/vertex-center/vertex/pkg/storage"
+)
+
+// createVolumes creates the named volumes of the container. Docker returns
+// the existing volume if it was already created.
+func (a ContainerRunnerDockerAdapter) createVolumes(ctx context.Context, inst containerstypes.Container) error {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
+	docker := inst.Service.Methods.Docker
+	if docker == nil || docker.NamedVolumes == nil {
+		return nil
+	}
+
+	for name, v := range *docker.NamedVolumes {
+		options := types.CreateVolumeOptions{
+			Name:          inst.DockerVolumeName(name),
+			DriverOptions: v.DriverOptions,
+			Labels: map[string]string{
+				"vertex.container": inst.UUID.String(),
+			},
+		}
+		if v.Driver != nil {
+			options.Driver = *v.Driver
+		}
+
+		apiError := router.Error{}
+		err := api.KernelRequest().
+			Path("/api/docker/volume").
+			Post().
+			BodyJSON(options).
+			ErrorJSON(&apiError).
+			Fetch(ctx)
+		if apiError.Code == api.ErrKernelPolicyViolation {
+			return fmt.Errorf("%w: %s", ErrKernelPolicyViolation, apiError.PublicMessage)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// volumeCopyImage is the image of the helper containers copying volumes.
+const volumeCopyImage = "alpine:latest"
+
+// CopyVolumes copies the content of the volumes of a container into the
+// volumes of another container of the same service. The volumes with an
+// absolute path are shared by both containers, so they are not copied.
+func (a ContainerRunnerDockerAdapter) CopyVolumes(ctx context.Context, from *containerstypes.Container, to *containerstypes.Container) error {
+	docker := from.Service.Methods.Docker
+	if docker == nil {
+		return nil
+	}
+
+	if docker.Volumes != nil {
+		fromPath := a.getPath(ctx, *from)
+		toPath := a.getPath(ctx, *to)
+		for source := range *docker.Volumes {
+			if strings.HasPrefix(source, "/") {
+				continue
+			}
+			src, err := filepath.Abs(path.Join(fromPath, "volumes", source))
+			if err != nil {
+				return err
+			}
+			dst, err := filepath.Abs(path.Join(toPath, "volumes", source))
+			if err != nil {
+				return err
+			}
+			err = a.copyVolume(ctx, *to, types.CreateContainerOptions{
+				Binds: []string{src + ":/from:ro", dst + ":/to"},
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", source, err)
+			}
+		}
+	}
+
+	if docker.NamedVolumes != nil {
+		err := a.createVolumes(ctx, *to)
+		if err != nil {
+			return err
+		}
+		for name := range *docker.NamedVolumes {
+			err := a.copyVolume(ctx, *to, types.CreateContainerOptions{
+				VolumeMounts: []types.VolumeMount{
+					{Name: from.DockerVolumeName(name), Target: "/from", ReadOnly: true},
+					{Name: to.DockerVolumeName(name), Target: "/to"},
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// copyVolume copies the content mounted in /from into /to, with a helper
+// container.
+func (a ContainerRunnerDockerAdapter) copyVolume(ctx context.Context, inst containerstypes.Container, options types.CreateContainerOptions) error {
+	options.ImageName = volumeCopyImage
+	options.ContainerName = fmt.Sprintf("%s_COPY_%s", inst.DockerContainerName(), uuid.New().String()[:8])
+	options.Cmd = []string{"cp", "-a", "/from/.", "/to/"}
+
+	var output []string
+	exitCode, err := a.runHelperContainer(ctx, inst, options, func(line string) {
+		output = append(output, line)
+	})
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("%w: %s", ErrVolumeCopyFailed, strings.Join(output, "\n"))
+	}
+	return nil
+}
+
+// DeleteVolumes deletes the named volumes of the container. The volumes
+// that don't exist are ignored.
+func (a ContainerRunnerDockerAdapter) DeleteVolumes(ctx context.Context, inst *containerstypes.Container) error {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
+	docker := inst.Service.Methods.Docker
+	if docker == nil || docker.NamedVolumes == nil {
+		return nil
+	}
+
+	for name := range *docker.NamedVolumes {
+		apiError := router.Error{}
+		err := api.KernelRequest().
+			Pathf("/api/docker/volume/%s", inst.DockerVolumeName(name)).
+			Delete().
+			ErrorJSON(&apiError).
+			Fetch(ctx)
+		if err != nil && apiError.Code != api.ErrVolumeNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetVolumes returns the volumes mounted in the container, with their disk
+// usage when it is known.
+func (a ContainerRunnerDockerAdapter) GetVolumes(ctx context.Context, inst containerstypes.Container) ([]containerstypes.ContainerVolume, error) {
+	ctx, cancel := withKernelTimeout(ctx)
+	defer cancel()
+
+	volumes := []containerstypes.ContainerVolume{}
+
+	docker := inst.Service.Methods.Docker
+	if docker == nil {
+		return volumes, nil
+	}
+
+	if docker.Volumes != nil {
+		for source, target := range *docker.Volumes {
+			target, readOnly := strings.CutSuffix(target, ":ro")
+			volume := containerstypes.ContainerVolume{
+				Type:     containerstypes.ContainerVolumeTypeBind,
+				Source:   source,
+				Target:   target,
+				ReadOnly: readOnly,
+				Size:     -1,
+			}
+
+			// Only the volumes stored with the container can be measured,
+			// because the other paths may not be readable by Vertex.
+			if !strings.HasPrefix(source, "/") {
+				p := path.Join(storage.Path, "apps", "vx-containers", inst.UUID.String(), "volumes", source)
+				if size, err := dirSize(p); err == nil {
+					volume.Size = size
+				}
+			}
+			volumes = append(volumes, volume)
+		}
+	}
+
+	if docker.NamedVolumes != nil {
+		for name, v := range *docker.NamedVolumes {
+			volume := containerstypes.ContainerVolume{
+				Type:     containerstypes.ContainerVolumeTypeVolume,
+				Name:     name,
+				Source:   inst.DockerVolumeName(name),
+				Target:   v.Target,
+				ReadOnly: v.ReadOnly,
+				Size:     -1,
+			}
+
+			var res types.Volume
+			apiError := router.Error{}
+			err := api.KernelRequest().
+				Pathf("/api/docker/volume/%s", volume.Source).
+				ToJSON(&res).
+				ErrorJSON(&apiError).
+				Fetch(ctx)
+			if err == nil {
+				volume.Size = res.Size
+			} else if apiError.Code != api.ErrVolumeNotFound {
+				return nil, err
+			}
+			volumes = append(volumes, volume)
+		}
+	}
+
+	if docker.Tmpfs != nil {
+		for target := range *docker.Tmpfs {
+			volumes = append(volumes, containerstypes.ContainerVolume{
+				Type:   containerstypes.ContainerVolumeTypeTmpfs,
+				Target: target,
+				Size:   -1,
+			})
+		}
+	}
+
+	return volumes, nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -110,6 +110,15 @@ func (a *ServiceFSAdapter) Reload() error {
 			return err
 		}
 
+		// A service with invalid hooks would only fail when the hooks run.
+		if service.Hooks != nil {
+			err = service.Hooks.Validate()
+			if err != nil {
+				log.Error(fmt.Errorf("service %s ignored: %w", dir.Name(), err))
+				continue
+			}
+		}
+
 		a.services = append(a.services, service)
 	}
 
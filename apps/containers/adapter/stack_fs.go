@@ -0,0 +1,128 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vlog"
+)
+
+type StackFSAdapter struct {
+	stacks      types.Stacks
+	stacksMutex sync.RWMutex
+
+	stacksPath string
+}
+
+type StackFSAdapterParams struct {
+	StacksPath string
+}
+
+func NewStackFSAdapter(params *StackFSAdapterParams) port.StackAdapter {
+	if params == nil {
+		params = &StackFSAdapterParams{}
+	}
+	if params.StacksPath == "" {
+		params.StacksPath = path.Join(storage.Path, "apps", "vx-containers-stacks")
+	}
+
+	err := os.MkdirAll(params.StacksPath, os.ModePerm)
+	if err != nil && !os.IsExist(err) {
+		log.Error(err,
+			vlog.String("message", "failed to create directory"),
+			vlog.String("path", params.StacksPath),
+		)
+		os.Exit(1)
+	}
+
+	a := &StackFSAdapter{
+		stacks:     types.Stacks{},
+		stacksPath: params.StacksPath,
+	}
+
+	err = a.read()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Error(err)
+	}
+
+	return a
+}
+
+func (a *StackFSAdapter) GetAll() types.Stacks {
+	a.stacksMutex.RLock()
+	defer a.stacksMutex.RUnlock()
+
+	stacks := types.Stacks{}
+	for id, stack := range a.stacks {
+		stacks[id] = stack
+	}
+	return stacks
+}
+
+func (a *StackFSAdapter) Get(id uuid.UUID) (types.Stack, error) {
+	a.stacksMutex.RLock()
+	defer a.stacksMutex.RUnlock()
+
+	stack, ok := a.stacks[id]
+	if !ok {
+		return types.Stack{}, types.ErrStackNotFound
+	}
+	return stack, nil
+}
+
+func (a *StackFSAdapter) Save(stack types.Stack) error {
+	func() {
+		a.stacksMutex.Lock()
+		defer a.stacksMutex.Unlock()
+		a.stacks[stack.UUID] = stack
+	}()
+	return a.write()
+}
+
+func (a *StackFSAdapter) Delete(id uuid.UUID) error {
+	err := func() error {
+		a.stacksMutex.Lock()
+		defer a.stacksMutex.Unlock()
+		if _, ok := a.stacks[id]; !ok {
+			return types.ErrStackNotFound
+		}
+		delete(a.stacks, id)
+		return nil
+	}()
+	if err != nil {
+		return err
+	}
+	return a.write()
+}
+
+func (a *StackFSAdapter) read() error {
+	file, err := os.ReadFile(path.Join(a.stacksPath, "stacks.json"))
+	if err != nil {
+		return err
+	}
+
+	a.stacksMutex.Lock()
+	defer a.stacksMutex.Unlock()
+
+	return json.Unmarshal(file, &a.stacks)
+}
+
+func (a *StackFSAdapter) write() error {
+	a.stacksMutex.RLock()
+	defer a.stacksMutex.RUnlock()
+
+	bytes, err := json.MarshalIndent(a.stacks, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path.Join(a.stacksPath, "stacks.json"), bytes, os.ModePerm)
+}
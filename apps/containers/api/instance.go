@@ -31,6 +31,19 @@ func DeleteContainer(ctx context.Context, uuid uuid.UUID) *api.Error {
 	return api.HandleError(err, apiError)
 }
 
+func CloneContainer(ctx context.Context, uuid uuid.UUID, options types2.CloneContainerOptions) (*types2.Container, *api.Error) {
+	var clone types2.Container
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./container/%s/clone", uuid).
+		Post().
+		BodyJSON(&options).
+		ToJSON(&clone).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return &clone, api.HandleError(err, apiError)
+}
+
 func PatchContainer(ctx context.Context, uuid uuid.UUID, settings types2.ContainerSettings) *api.Error {
 	var apiError api.Error
 	err := api.AppRequest(containers.AppRoute).
@@ -62,6 +75,16 @@ func StopContainer(ctx context.Context, uuid uuid.UUID) *api.Error {
 	return api.HandleError(err, apiError)
 }
 
+func RollingRestartContainer(ctx context.Context, uuid uuid.UUID) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./container/%s/rolling-restart", uuid).
+		Post().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
 func PatchContainerEnvironment(ctx context.Context, uuid uuid.UUID, env map[string]string) *api.Error {
 	var apiError api.Error
 	err := api.AppRequest(containers.AppRoute).
@@ -73,8 +96,8 @@ func PatchContainerEnvironment(ctx context.Context, uuid uuid.UUID, env map[stri
 	return api.HandleError(err, apiError)
 }
 
-func GetDocker(ctx context.Context, uuid uuid.UUID) (map[string]any, *api.Error) {
-	var info map[string]any
+func GetDocker(ctx context.Context, uuid uuid.UUID) (types2.ContainerInfo, *api.Error) {
+	var info types2.ContainerInfo
 	var apiError api.Error
 	err := api.AppRequest(containers.AppRoute).
 		Pathf("./container/%s/docker", uuid).
@@ -2,14 +2,13 @@ package containersapi
 
 import (
 	"context"
-	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers"
 	"github.com/vertex-center/vertex/apps/containers/core/types"
 	"github.com/vertex-center/vertex/core/types/api"
 )
 
-func GetContainers(ctx context.Context) (map[uuid.UUID]*types.Container, *api.Error) {
-	var insts map[uuid.UUID]*types.Container
+func GetContainers(ctx context.Context) ([]*types.Container, *api.Error) {
+	var insts []*types.Container
 	var apiError api.Error
 	err := api.AppRequest(containers.AppRoute).
 		Path("./containers").
@@ -29,3 +28,16 @@ func CheckForUpdates(ctx context.Context) ([]types.Container, *api.Error) {
 		Fetch(ctx)
 	return insts, api.HandleError(err, apiError)
 }
+
+func Bulk(ctx context.Context, request types.BulkRequest) ([]types.BulkResult, *api.Error) {
+	var results []types.BulkResult
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Path("./containers/bulk").
+		Post().
+		BodyJSON(&request).
+		ToJSON(&results).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return results, api.HandleError(err, apiError)
+}
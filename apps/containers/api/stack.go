@@ -0,0 +1,81 @@
+package containersapi
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
+)
+
+func GetStacks(ctx context.Context) ([]types.Stack, *api.Error) {
+	var stacks []types.Stack
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Path("./stacks").
+		ToJSON(&stacks).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return stacks, api.HandleError(err, apiError)
+}
+
+func GetStack(ctx context.Context, uuid uuid.UUID) (*types.Stack, *api.Error) {
+	var stack types.Stack
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./stack/%s", uuid).
+		ToJSON(&stack).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return &stack, api.HandleError(err, apiError)
+}
+
+func InstallStack(ctx context.Context, definition types.StackDefinition) (*types.Stack, *api.Error) {
+	var stack types.Stack
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Path("./stack").
+		Post().
+		BodyJSON(&definition).
+		ToJSON(&stack).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return &stack, api.HandleError(err, apiError)
+}
+
+func DeleteStack(ctx context.Context, uuid uuid.UUID, volumes bool) *api.Error {
+	var apiError api.Error
+	req := api.AppRequest(containers.AppRoute).
+		Pathf("./stack/%s", uuid).
+		Delete()
+	if volumes {
+		req = req.Param("volumes", "true")
+	}
+	err := req.ErrorJSON(&apiError).Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
+func StartStack(ctx context.Context, uuid uuid.UUID) ([]types.BulkResult, *api.Error) {
+	return runStackAction(ctx, uuid, "start")
+}
+
+func StopStack(ctx context.Context, uuid uuid.UUID) ([]types.BulkResult, *api.Error) {
+	return runStackAction(ctx, uuid, "stop")
+}
+
+func UpdateStack(ctx context.Context, uuid uuid.UUID) ([]types.BulkResult, *api.Error) {
+	return runStackAction(ctx, uuid, "update")
+}
+
+func runStackAction(ctx context.Context, uuid uuid.UUID, action string) ([]types.BulkResult, *api.Error) {
+	var results []types.BulkResult
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./stack/%s/%s", uuid, action).
+		Post().
+		ToJSON(&results).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return results, api.HandleError(err, apiError)
+}
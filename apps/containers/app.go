@@ -1,10 +1,17 @@
 package containers
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers/adapter"
 	"github.com/vertex-center/vertex/apps/containers/core/port"
 	"github.com/vertex-center/vertex/apps/containers/core/service"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
 	"github.com/vertex-center/vertex/apps/containers/handler"
+	vtypes "github.com/vertex-center/vertex/core/types"
 	apptypes "github.com/vertex-center/vertex/core/types/app"
 	"github.com/vertex-center/vertex/pkg/router"
 )
@@ -15,19 +22,28 @@ const (
 
 var (
 	containerAdapter         port.ContainerAdapter
+	dockerResourcesAdapter   port.DockerResourcesAdapter
+	logBufferAdapter         port.LogBufferAdapter
+	logForwarderAdapter      port.LogForwarderAdapter
+	logSinkAdapter           port.LogSinkAdapter
 	containerEnvAdapter      port.ContainerEnvAdapter
 	containerLogsAdapter     port.ContainerLogsAdapter
 	containerRunnerAdapter   port.ContainerRunnerAdapter
 	containerServiceAdapter  port.ContainerServiceAdapter
 	containerSettingsAdapter port.ContainerSettingsAdapter
+	stackAdapter             port.StackAdapter
 
-	containerService         port.ContainerService
-	containerEnvService      port.ContainerEnvService
-	containerLogsService     port.ContainerLogsService
-	containerRunnerService   port.ContainerRunnerService
-	containerServiceService  port.ContainerServiceService
-	containerSettingsService port.ContainerSettingsService
-	serviceService           port.ServiceService
+	containerService          port.ContainerService
+	containerEnvService       port.ContainerEnvService
+	containerLogsService      port.ContainerLogsService
+	containerRunnerService    port.ContainerRunnerService
+	containerSchedulerService port.ContainerSchedulerService
+	containerServiceService   port.ContainerServiceService
+	containerSettingsService  port.ContainerSettingsService
+	garbageCollectorService   port.GarbageCollectorService
+	logForwarderService       port.LogForwarderService
+	serviceService            port.ServiceService
+	stackService              port.StackService
 )
 
 type App struct {
@@ -47,6 +63,11 @@ func (a *App) Initialize(app *apptypes.App) error {
 	containerRunnerAdapter = adapter.NewContainerRunnerFSAdapter()
 	containerServiceAdapter = adapter.NewContainerServiceFSAdapter(nil)
 	containerSettingsAdapter = adapter.NewContainerSettingsFSAdapter(nil)
+	dockerResourcesAdapter = adapter.NewDockerResourcesKernelAdapter()
+	logBufferAdapter = adapter.NewLogBufferFSAdapter(nil)
+	logForwarderAdapter = adapter.NewLogForwarderNetAdapter()
+	logSinkAdapter = adapter.NewLogSinkFSAdapter(nil)
+	stackAdapter = adapter.NewStackFSAdapter(nil)
 
 	containerEnvService = service.NewContainerEnvService(containerEnvAdapter)
 	containerLogsService = service.NewContainerLogsService(app.Context(), containerLogsAdapter)
@@ -61,7 +82,32 @@ func (a *App) Initialize(app *apptypes.App) error {
 		ContainerEnvService:      containerEnvService,
 		ContainerSettingsService: containerSettingsService,
 	})
+	containerSchedulerService = service.NewContainerSchedulerService(service.ContainerSchedulerServiceParams{
+		Ctx:                    app.Context(),
+		ContainerService:       containerService,
+		ContainerRunnerService: containerRunnerService,
+	})
+	garbageCollectorService = service.NewGarbageCollectorService(service.GarbageCollectorServiceParams{
+		Ctx:              app.Context(),
+		Adapter:          dockerResourcesAdapter,
+		ContainerService: containerService,
+	})
+	logForwarderService = service.NewLogForwarderService(service.LogForwarderServiceParams{
+		Ctx:              app.Context(),
+		SinkAdapter:      logSinkAdapter,
+		ForwarderAdapter: logForwarderAdapter,
+		BufferAdapter:    logBufferAdapter,
+		ContainerService: containerService,
+	})
 	serviceService = service.NewServiceService()
+	stackService = service.NewStackService(service.StackServiceParams{
+		Ctx:                      app.Context(),
+		Adapter:                  stackAdapter,
+		ContainerService:         containerService,
+		ContainerEnvService:      containerEnvService,
+		ContainerSettingsService: containerSettingsService,
+		ServiceService:           serviceService,
+	})
 	service.NewMetricsService(app.Context())
 
 	app.Register(apptypes.Meta{
@@ -71,50 +117,126 @@ func (a *App) Initialize(app *apptypes.App) error {
 		Icon:        "deployed_code",
 	})
 
+	// The routes using Require without a scope are not about existing
+	// containers, like the garbage collection, the log sinks or the
+	// installation of a service or a stack. They are denied to the roles
+	// limited to some tags.
 	app.RegisterRoutes(AppRoute, func(r *router.Group) {
 		containerHandler := handler.NewContainerHandler(handler.ContainerHandlerParams{
-			Ctx:                      app.Context(),
-			ContainerService:         containerService,
-			ContainerSettingsService: containerSettingsService,
-			ContainerRunnerService:   containerRunnerService,
-			ContainerEnvService:      containerEnvService,
-			ContainerServiceService:  containerServiceService,
-			ContainerLogsService:     containerLogsService,
-			ServiceService:           serviceService,
+			Ctx:                       app.Context(),
+			ContainerService:          containerService,
+			ContainerSettingsService:  containerSettingsService,
+			ContainerRunnerService:    containerRunnerService,
+			ContainerEnvService:       containerEnvService,
+			ContainerServiceService:   containerServiceService,
+			ContainerLogsService:      containerLogsService,
+			ContainerSchedulerService: containerSchedulerService,
+			ServiceService:            serviceService,
 		})
 		container := r.Group("/container/:container_uuid")
-		container.GET("", containerHandler.Get)
-		container.DELETE("", containerHandler.Delete)
-		container.PATCH("", containerHandler.Patch)
-		container.POST("/start", containerHandler.Start)
-		container.POST("/stop", containerHandler.Stop)
-		container.PATCH("/environment", containerHandler.PatchEnvironment)
-		container.GET("/events", apptypes.HeadersSSE, containerHandler.Events)
-		container.GET("/docker", containerHandler.GetDocker)
-		container.POST("/docker/recreate", containerHandler.RecreateDocker)
-		container.GET("/logs", containerHandler.GetLogs)
-		container.POST("/update/service", containerHandler.UpdateService)
-		container.GET("/versions", containerHandler.GetVersions)
-		container.GET("/wait", containerHandler.Wait)
-
-		containersHandler := handler.NewContainersHandler(app.Context(), containerService)
+		container.GET("", app.Require(vtypes.PermissionContainersRead, containerTags), containerHandler.Get)
+		container.DELETE("", app.Require(vtypes.PermissionContainersWrite, containerTags), containerHandler.Delete)
+		container.PATCH("", app.Require(vtypes.PermissionContainersWrite, containerTags), containerHandler.Patch)
+		container.POST("/clone", app.Require(vtypes.PermissionContainersWrite, containerTags), containerHandler.Clone)
+		container.POST("/start", app.Require(vtypes.PermissionContainersStart, containerTags), containerHandler.Start)
+		container.POST("/stop", app.Require(vtypes.PermissionContainersStart, containerTags), containerHandler.Stop)
+		container.POST("/rolling-restart", app.Require(vtypes.PermissionContainersStart, containerTags), containerHandler.RollingRestart)
+		container.PATCH("/environment", app.Require(vtypes.PermissionContainersEnvWrite, containerTags), containerHandler.PatchEnvironment)
+		container.GET("/events", app.Require(vtypes.PermissionContainersRead, containerTags), apptypes.HeadersSSE, containerHandler.Events)
+		container.GET("/docker", app.Require(vtypes.PermissionContainersRead, containerTags), containerHandler.GetDocker)
+		container.POST("/docker/recreate", app.Require(vtypes.PermissionContainersStart, containerTags), containerHandler.RecreateDocker)
+		container.GET("/logs", app.Require(vtypes.PermissionContainersRead, containerTags), containerHandler.GetLogs)
+		container.POST("/update/service", app.Require(vtypes.PermissionContainersWrite, containerTags), containerHandler.UpdateService)
+		container.GET("/versions", app.Require(vtypes.PermissionContainersRead, containerTags), containerHandler.GetVersions)
+		container.GET("/volumes", app.Require(vtypes.PermissionContainersRead, containerTags), containerHandler.GetVolumes)
+		container.GET("/wait", app.Require(vtypes.PermissionContainersRead, containerTags), containerHandler.Wait)
+
+		containersHandler := handler.NewContainersHandler(app.Context(), containerService, garbageCollectorService)
 		containers := r.Group("/containers")
-		containers.GET("", containersHandler.Get)
-		containers.GET("/tags", containersHandler.GetTags)
-		containers.GET("/search", containersHandler.Search)
-		containers.GET("/checkupdates", containersHandler.CheckForUpdates)
-		containers.GET("/events", apptypes.HeadersSSE, containersHandler.Events)
+		containers.GET("", app.RequireFiltered(vtypes.PermissionContainersRead), containersHandler.Get)
+		containers.GET("/tags", app.RequireFiltered(vtypes.PermissionContainersRead), containersHandler.GetTags)
+		containers.GET("/search", app.RequireFiltered(vtypes.PermissionContainersRead), containersHandler.Search)
+		containers.GET("/checkupdates", app.Require(vtypes.PermissionContainersRead, nil), containersHandler.CheckForUpdates)
+		containers.GET("/events", app.RequireFiltered(vtypes.PermissionContainersRead), apptypes.HeadersSSE, containersHandler.Events)
+		containers.POST("/gc", app.Require(vtypes.PermissionContainersWrite, nil), containersHandler.CollectGarbage)
+		containers.POST("/bulk", a.requireBulkPermission, containersHandler.Bulk)
+
+		logSinksHandler := handler.NewLogSinksHandler(logForwarderService)
+		r.GET("/log-sinks", app.Require(vtypes.PermissionContainersRead, nil), logSinksHandler.Get)
+		r.POST("/log-sink", app.Require(vtypes.PermissionContainersWrite, nil), logSinksHandler.Add)
+		r.DELETE("/log-sink/:sink_uuid", app.Require(vtypes.PermissionContainersWrite, nil), logSinksHandler.Remove)
+
+		stacksHandler := handler.NewStacksHandler(app.Context(), stackService)
+		stacks := r.Group("/stacks")
+		stacks.GET("", app.RequireFiltered(vtypes.PermissionContainersRead), stacksHandler.Get)
+		stacks.GET("/events", app.RequireFiltered(vtypes.PermissionContainersRead), apptypes.HeadersSSE, stacksHandler.Events)
+		r.POST("/stack", app.Require(vtypes.PermissionContainersWrite, nil), stacksHandler.Install)
+
+		stackHandler := handler.NewStackHandler(stackService)
+		stack := r.Group("/stack/:stack_uuid")
+		stack.GET("", app.Require(vtypes.PermissionContainersRead, stackTags), stackHandler.Get)
+		stack.DELETE("", app.Require(vtypes.PermissionContainersWrite, stackTags), stackHandler.Delete)
+		stack.POST("/start", app.Require(vtypes.PermissionContainersStart, stackTags), stackHandler.Start)
+		stack.POST("/stop", app.Require(vtypes.PermissionContainersStart, stackTags), stackHandler.Stop)
+		stack.POST("/update", app.Require(vtypes.PermissionContainersWrite, stackTags), stackHandler.Update)
 
 		serviceHandler := handler.NewServiceHandler(serviceService, containerService)
 		serv := r.Group("/service/:service_id")
-		serv.GET("", serviceHandler.Get)
-		serv.POST("/install", serviceHandler.Install)
+		serv.GET("", app.Require(vtypes.PermissionContainersRead, nil), serviceHandler.Get)
+		serv.POST("/install", app.Require(vtypes.PermissionContainersWrite, nil), serviceHandler.Install)
 
 		servicesHandler := handler.NewServicesHandler(serviceService)
 		services := r.Group("/services")
-		services.GET("", servicesHandler.Get)
+		services.GET("", app.Require(vtypes.PermissionContainersRead, nil), servicesHandler.Get)
 		services.Static("/icons", "./live/services/icons")
 	})
 
 	return nil
 }
+
+// containerTags returns the tags of the container targeted by the request,
+// to check the permissions of the roles limited to some tags.
+func containerTags(c *router.Context) ([]string, error) {
+	id, err := uuid.Parse(c.Param("container_uuid"))
+	if err != nil {
+		return nil, err
+	}
+	inst, err := containerService.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return inst.ContainerSettings.Tags, nil
+}
+
+// stackTags returns the tags shared by all the members of the stack targeted
+// by the request, so that the roles limited to some tags can only manage the
+// stacks whose members they can all manage.
+func stackTags(c *router.Context) ([]string, error) {
+	id, err := uuid.Parse(c.Param("stack_uuid"))
+	if err != nil {
+		return nil, err
+	}
+	return stackService.Tags(id)
+}
+
+// requireBulkPermission requires the permission of the action of a bulk
+// request, like RequireFiltered, because the actions don't require the same
+// permission.
+func (a *App) requireBulkPermission(c *router.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           router.ErrFailedToParseBody,
+			PublicMessage:  "Failed to read the request body.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	// The body is validated by the handler.
+	var request types.BulkRequest
+	_ = json.Unmarshal(body, &request)
+
+	a.RequireFiltered(request.Permission())(c)
+}
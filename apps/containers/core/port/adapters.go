@@ -1,6 +1,7 @@
 package port
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers/core/types"
 	types2 "github.com/vertex-center/vertex/core/types"
@@ -39,18 +40,89 @@ type ContainerLogsAdapter interface {
 
 	// LoadBuffer will load the latest logs kept in memory.
 	LoadBuffer(uuid uuid.UUID) ([]types.LogLine, error)
+
+	// Query searches the logs persisted on disk.
+	Query(uuid uuid.UUID, query types.LogQuery) (types.LogPage, error)
 }
 
 type ContainerRunnerAdapter interface {
-	Delete(inst *types.Container) error
-	Start(inst *types.Container, setStatus func(status string)) (stdout io.ReadCloser, stderr io.ReadCloser, err error)
-	Stop(inst *types.Container) error
-	Info(inst types.Container) (map[string]any, error)
-	WaitCondition(inst *types.Container, cond types2.WaitContainerCondition) error
-
-	CheckForUpdates(inst *types.Container) error
-	HasUpdateAvailable(inst types.Container) (bool, error)
-	GetAllVersions(inst types.Container) ([]string, error)
+	Delete(ctx context.Context, inst *types.Container) error
+	Start(ctx context.Context, inst *types.Container, setStatus func(status string)) (stdout io.ReadCloser, stderr io.ReadCloser, err error)
+	Attach(ctx context.Context, inst *types.Container, setStatus func(status string)) (stdout io.ReadCloser, stderr io.ReadCloser, err error)
+	Stop(ctx context.Context, inst *types.Container) error
+	Info(ctx context.Context, inst types.Container) (types.ContainerInfo, error)
+	WaitCondition(ctx context.Context, inst *types.Container, cond types2.WaitContainerCondition) error
+
+	// RunHook runs a lifecycle hook of the container, and calls output for
+	// each line it writes.
+	RunHook(ctx context.Context, inst *types.Container, hook types.Hook, output func(line string)) error
+
+	// RollingRestart restarts the replicas of the container one after the
+	// other.
+	RollingRestart(ctx context.Context, inst *types.Container) error
+
+	CheckForUpdates(ctx context.Context, inst *types.Container) error
+	HasUpdateAvailable(ctx context.Context, inst types.Container) (bool, error)
+	GetAllVersions(ctx context.Context, inst types.Container) ([]string, error)
+
+	// GetImageTag returns the image tag used by the existing Docker container.
+	// It returns ErrContainerNotFound if the Docker container doesn't exist.
+	GetImageTag(ctx context.Context, inst types.Container) (string, error)
+
+	// GetVolumes returns the volumes mounted in the container.
+	GetVolumes(ctx context.Context, inst types.Container) ([]types.ContainerVolume, error)
+	// DeleteVolumes deletes the named volumes of the container.
+	DeleteVolumes(ctx context.Context, inst *types.Container) error
+	// CopyVolumes copies the content of the volumes of a container into
+	// the volumes of another container of the same service.
+	CopyVolumes(ctx context.Context, from *types.Container, to *types.Container) error
+
+	// WatchEvents calls handle for each Docker container event, until the
+	// context is done or the stream is interrupted.
+	WatchEvents(ctx context.Context, handle func(e types2.DockerEvent)) error
+}
+
+// DockerResourcesAdapter gives access to the Docker resources shared by all
+// containers, like images and volumes.
+type DockerResourcesAdapter interface {
+	ListContainers() ([]types2.Container, error)
+	ListImages() ([]types2.Image, error)
+	DeleteImage(id string) error
+	ListVolumes() ([]types2.Volume, error)
+	DeleteVolume(name string) error
+	PruneBuildCache() (types2.PruneBuildCacheResponse, error)
+}
+
+type LogSinkAdapter interface {
+	GetAll() types.LogSinks
+	Add(id uuid.UUID, sink types.LogSink) error
+	// Remove a log sink. Returns ErrLogSinkNotFound if the sink doesn't exist.
+	Remove(id uuid.UUID) error
+}
+
+type StackAdapter interface {
+	GetAll() types.Stacks
+	// Get returns a stack. Returns ErrStackNotFound if the stack doesn't exist.
+	Get(id uuid.UUID) (types.Stack, error)
+	Save(stack types.Stack) error
+	// Delete a stack. Returns ErrStackNotFound if the stack doesn't exist.
+	Delete(id uuid.UUID) error
+}
+
+type LogForwarderAdapter interface {
+	// Send sends a batch of log entries to the sink.
+	Send(sink types.LogSink, entries []types.LogEntry) error
+}
+
+// LogBufferAdapter keeps the log entries that could not be sent to a sink,
+// to retry later.
+type LogBufferAdapter interface {
+	Push(sinkID uuid.UUID, entries []types.LogEntry) error
+	// Load returns the first entries of the buffer, up to limit.
+	Load(sinkID uuid.UUID, limit int) ([]types.LogEntry, error)
+	// Trim removes the first count entries of the buffer.
+	Trim(sinkID uuid.UUID, count int) error
+	Clear(sinkID uuid.UUID) error
 }
 
 type ServiceAdapter interface {
@@ -6,15 +6,18 @@ type (
 	ContainerHandler interface {
 		Get(c *router.Context)
 		Delete(c *router.Context)
+		Clone(c *router.Context)
 		Patch(c *router.Context)
 		Start(c *router.Context)
 		Stop(c *router.Context)
+		RollingRestart(c *router.Context)
 		PatchEnvironment(c *router.Context)
 		GetDocker(c *router.Context)
 		RecreateDocker(c *router.Context)
 		GetLogs(c *router.Context)
 		UpdateService(c *router.Context)
 		GetVersions(c *router.Context)
+		GetVolumes(c *router.Context)
 		Wait(c *router.Context)
 		Events(c *router.Context)
 	}
@@ -24,7 +27,29 @@ type (
 		GetTags(c *router.Context)
 		Search(c *router.Context)
 		CheckForUpdates(c *router.Context)
+		Bulk(c *router.Context)
 		Events(c *router.Context)
+		CollectGarbage(c *router.Context)
+	}
+
+	LogSinksHandler interface {
+		Get(c *router.Context)
+		Add(c *router.Context)
+		Remove(c *router.Context)
+	}
+
+	StacksHandler interface {
+		Get(c *router.Context)
+		Install(c *router.Context)
+		Events(c *router.Context)
+	}
+
+	StackHandler interface {
+		Get(c *router.Context)
+		Delete(c *router.Context)
+		Start(c *router.Context)
+		Stop(c *router.Context)
+		Update(c *router.Context)
 	}
 
 	ServiceHandler interface {
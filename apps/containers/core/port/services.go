@@ -1,6 +1,8 @@
 package port
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers/core/types"
 	vtypes "github.com/vertex-center/vertex/core/types"
@@ -11,16 +13,19 @@ type (
 		Get(uuid uuid.UUID) (*types.Container, error)
 		GetAll() map[uuid.UUID]*types.Container
 		GetTags() []string
-		Search(query types.ContainerSearchQuery) map[uuid.UUID]*types.Container
+		Search(query types.ContainerSearchQuery) ([]*types.Container, int)
 		Exists(uuid uuid.UUID) bool
-		Delete(inst *types.Container) error
+		Delete(ctx context.Context, inst *types.Container, options types.DeleteContainerOptions) error
 		StartAll()
 		StopAll()
 		LoadAll()
 		DeleteAll()
 		Install(service types.Service, method string) (*types.Container, error)
-		CheckForUpdates() (map[uuid.UUID]*types.Container, error)
+		Clone(ctx context.Context, inst *types.Container, options types.CloneContainerOptions) (*types.Container, error)
+		CheckForUpdates(ctx context.Context) (map[uuid.UUID]*types.Container, error)
+		Bulk(ctx context.Context, containers []*types.Container, request types.BulkRequest) ([]types.BulkResult, error)
 		SetDatabases(inst *types.Container, databases map[string]uuid.UUID) error
+		SetReplicas(inst *types.Container, replicas int) error
 	}
 
 	ContainerEnvService interface {
@@ -30,18 +35,26 @@ type (
 
 	ContainerLogsService interface {
 		GetLatestLogs(uuid uuid.UUID) ([]types.LogLine, error)
+		Query(uuid uuid.UUID, query types.LogQuery) (types.LogPage, error)
 	}
 
 	ContainerRunnerService interface {
 		Install(uuid uuid.UUID, service types.Service) error
-		Delete(inst *types.Container) error
+		Delete(ctx context.Context, inst *types.Container) error
 		Start(inst *types.Container) error
-		Stop(inst *types.Container) error
-		GetDockerContainerInfo(inst types.Container) (map[string]any, error)
-		GetAllVersions(inst *types.Container, useCache bool) ([]string, error)
-		CheckForUpdates(inst *types.Container) error
-		RecreateContainer(inst *types.Container) error
-		WaitCondition(inst *types.Container, condition vtypes.WaitContainerCondition) error
+		Attach(inst *types.Container) error
+		Stop(ctx context.Context, inst *types.Container) error
+		GetDockerContainerInfo(ctx context.Context, inst types.Container) (types.ContainerInfo, error)
+		GetAllVersions(ctx context.Context, inst *types.Container, useCache bool) ([]string, error)
+		CheckForUpdates(ctx context.Context, inst *types.Container) error
+		RecreateContainer(ctx context.Context, inst *types.Container) error
+		RollingRestart(ctx context.Context, inst *types.Container) error
+		WaitCondition(ctx context.Context, inst *types.Container, condition vtypes.WaitContainerCondition) error
+		GetVolumes(ctx context.Context, inst *types.Container) ([]types.ContainerVolume, error)
+		DeleteVolumes(ctx context.Context, inst *types.Container) error
+		CopyVolumes(ctx context.Context, from *types.Container, to *types.Container) error
+		WatchEvents(ctx context.Context, handle func(e vtypes.DockerEvent)) error
+		HandleDockerEvent(inst *types.Container, e vtypes.DockerEvent)
 	}
 
 	ContainerServiceService interface {
@@ -58,11 +71,40 @@ type (
 		SetDisplayName(inst *types.Container, value string) error
 		SetDatabases(inst *types.Container, databases map[string]uuid.UUID) error
 		SetVersion(inst *types.Container, value string) error
+		SetVersionChannel(inst *types.Container, channel string) error
 		SetTags(inst *types.Container, tags []string) error
+		SetSchedule(inst *types.Container, schedule types.ContainerSchedule) error
+		SetHooks(inst *types.Container, hooks types.Hooks) error
+		SetReplicas(inst *types.Container, replicas int) error
+	}
+
+	ContainerSchedulerService interface {
+		Schedule(inst *types.Container) error
+		Unschedule(id uuid.UUID) error
+	}
+
+	GarbageCollectorService interface {
+		Collect(options types.GarbageCollectOptions) (types.GarbageCollectReport, error)
+	}
+
+	LogForwarderService interface {
+		GetSinks() types.LogSinks
+		AddSink(sink types.LogSink) (uuid.UUID, error)
+		RemoveSink(id uuid.UUID) error
 	}
 
 	MetricsService interface{}
 
+	StackService interface {
+		GetAll() []types.Stack
+		Get(id uuid.UUID) (types.Stack, error)
+		// Tags returns the tags shared by all the members of the stack.
+		Tags(id uuid.UUID) ([]string, error)
+		Install(ctx context.Context, definition types.StackDefinition) (types.Stack, error)
+		Run(ctx context.Context, id uuid.UUID, action string) ([]types.BulkResult, error)
+		Delete(ctx context.Context, id uuid.UUID, volumes bool) error
+	}
+
 	ServiceService interface {
 		GetAll() []types.Service
 		GetById(id string) (types.Service, error)
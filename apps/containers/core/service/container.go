@@ -1,8 +1,10 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/vertex-center/vertex/apps/containers/core/port"
 	"github.com/vertex-center/vertex/apps/containers/core/types"
@@ -10,7 +12,6 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers/adapter"
-	"github.com/vertex-center/vertex/config"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/net"
 )
@@ -20,6 +21,7 @@ var (
 	ErrContainerAlreadyRunning    = errors.New("the container is already running")
 	ErrContainerNotRunning        = errors.New("the container is not running")
 	ErrInstallMethodDoesNotExists = errors.New("this install method doesn't exist for this service")
+	ErrNoVersionMatching          = errors.New("no version matches the version constraint")
 )
 
 type ContainerService struct {
@@ -35,6 +37,9 @@ type ContainerService struct {
 
 	containers      map[uuid.UUID]*types.Container
 	containersMutex *sync.RWMutex
+
+	// stopWatchingEvents stops the reconciliation from the Docker events.
+	stopWatchingEvents context.CancelFunc
 }
 
 type ContainerServiceParams struct {
@@ -111,28 +116,21 @@ func (s *ContainerService) GetTags() []string {
 	return tags
 }
 
-// Search returns all containers that match the query.
-func (s *ContainerService) Search(query types.ContainerSearchQuery) map[uuid.UUID]*types.Container {
-	containers := map[uuid.UUID]*types.Container{}
+// Search returns the page of containers that match the query, sorted as
+// requested, and the total number of matching containers.
+func (s *ContainerService) Search(query types.ContainerSearchQuery) ([]*types.Container, int) {
+	containers := []*types.Container{}
 
 	s.containersMutex.RLock()
-	defer s.containersMutex.RUnlock()
-
 	for _, inst := range s.containers {
-		if query.Features != nil {
-			if !inst.HasFeatureIn(*query.Features) {
-				continue
-			}
+		if query.Match(inst) {
+			containers = append(containers, inst)
 		}
-		if query.Tags != nil {
-			if !inst.HasTagIn(*query.Tags) {
-				continue
-			}
-		}
-		containers[inst.UUID] = inst
 	}
+	s.containersMutex.RUnlock()
 
-	return containers
+	query.SortContainers(containers)
+	return query.Paginate(containers), len(containers)
 }
 
 func (s *ContainerService) Exists(uuid uuid.UUID) bool {
@@ -142,20 +140,28 @@ func (s *ContainerService) Exists(uuid uuid.UUID) bool {
 	return s.containers[uuid] != nil
 }
 
-// Delete deletes an container by its UUID.
+// Delete deletes an container by its UUID. The named volumes are deleted
+// too if options.Volumes is set.
 // If the container is still running, it returns ErrContainerStillRunning.
-func (s *ContainerService) Delete(inst *types.Container) error {
+func (s *ContainerService) Delete(ctx context.Context, inst *types.Container, options types.DeleteContainerOptions) error {
 	serviceID := inst.Service.ID
 
 	if inst.IsRunning() {
 		return types.ErrContainerStillRunning
 	}
 
-	err := s.containerRunnerService.Delete(inst)
+	err := s.containerRunnerService.Delete(ctx, inst)
 	if err != nil && !errors.Is(err, adapter.ErrContainerNotFound) {
 		return err
 	}
 
+	if options.Volumes {
+		err = s.containerRunnerService.DeleteVolumes(ctx, inst)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = s.containerAdapter.Delete(inst.UUID)
 	if err != nil {
 		return err
@@ -179,10 +185,13 @@ func (s *ContainerService) StartAll() {
 	defer s.containersMutex.RUnlock()
 
 	var ids []uuid.UUID
+	now := time.Now()
 
 	for _, inst := range s.containers {
 		// vertex containers autostart are managed by the startup service.
-		if inst.LaunchOnStartup() && !inst.HasTag("vertex") {
+		// The containers reattached by LoadAll are already running.
+		// The scheduled containers are only started within their window.
+		if inst.LaunchOnStartup() && !inst.HasTag("vertex") && !inst.IsRunning() && inst.IsInScheduleWindow(now) {
 			ids = append(ids, inst.UUID)
 		}
 	}
@@ -222,7 +231,7 @@ func (s *ContainerService) StopAll() {
 	defer s.containersMutex.RUnlock()
 
 	for _, inst := range s.containers {
-		err := s.containerRunnerService.Stop(inst)
+		err := s.containerRunnerService.Stop(context.Background(), inst)
 		if err != nil {
 			log.Error(err)
 		}
@@ -245,6 +254,8 @@ func (s *ContainerService) LoadAll() {
 			continue
 		}
 		loaded += 1
+
+		s.attach(id)
 	}
 
 	s.ctx.DispatchEvent(types.EventContainersLoaded{
@@ -252,10 +263,27 @@ func (s *ContainerService) LoadAll() {
 	})
 }
 
+// attach reattaches the container if its Docker container is still
+// running, so that restarting Vertex doesn't restart the containers.
+func (s *ContainerService) attach(id uuid.UUID) {
+	inst, err := s.Get(id)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	err = s.containerRunnerService.Attach(inst)
+	if errors.Is(err, adapter.ErrContainerNotRunning) || errors.Is(err, adapter.ErrContainerNotFound) {
+		return
+	} else if err != nil {
+		log.Error(err)
+	}
+}
+
 func (s *ContainerService) DeleteAll() {
 	all := s.GetAll()
 	for _, inst := range all {
-		err := s.Delete(inst)
+		err := s.Delete(context.Background(), inst, types.DeleteContainerOptions{Volumes: true})
 		if err != nil {
 			log.Error(err)
 		}
@@ -263,6 +291,13 @@ func (s *ContainerService) DeleteAll() {
 }
 
 func (s *ContainerService) Install(service types.Service, method string) (*types.Container, error) {
+	if service.Hooks != nil {
+		err := service.Hooks.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	id := uuid.New()
 	err := s.containerAdapter.Create(id)
 	if err != nil {
@@ -312,9 +347,9 @@ func (s *ContainerService) Install(service types.Service, method string) (*types
 	return inst, nil
 }
 
-func (s *ContainerService) CheckForUpdates() (map[uuid.UUID]*types.Container, error) {
+func (s *ContainerService) CheckForUpdates(ctx context.Context) (map[uuid.UUID]*types.Container, error) {
 	for _, inst := range s.GetAll() {
-		err := s.containerRunnerService.CheckForUpdates(inst)
+		err := s.containerRunnerService.CheckForUpdates(ctx, inst)
 		if err != nil {
 			return s.GetAll(), err
 		}
@@ -370,7 +405,18 @@ func (s *ContainerService) SetDatabases(inst *types.Container, databases map[str
 	return s.remapDatabaseEnv(inst)
 }
 
-// remapDatabaseEnv remaps the environment variables of an container.
+// SetReplicas sets the number of replicas of the container. It returns
+// ErrReplicaPortsUsed if a port published by a replica is already used.
+func (s *ContainerService) SetReplicas(inst *types.Container, replicas int) error {
+	err := s.checkReplicaPorts(inst, replicas)
+	if err != nil {
+		return err
+	}
+	return s.containerSettingsService.SetReplicas(inst, replicas)
+}
+
+// remapDatabaseEnv remaps the environment variables of an container, to
+// point to its databases.
 func (s *ContainerService) remapDatabaseEnv(inst *types.Container) error {
 	for databaseID, databaseContainerUUID := range inst.Databases {
 		db, err := s.Get(databaseContainerUUID)
@@ -378,13 +424,13 @@ func (s *ContainerService) remapDatabaseEnv(inst *types.Container) error {
 			return err
 		}
 
-		host := config.Current.Host
-
 		dbEnvNames := (*db.Service.Features.Databases)[0]
 		iEnvNames := inst.Service.Databases[databaseID].Names
 
-		inst.Env[iEnvNames.Host] = host
-		inst.Env[iEnvNames.Port] = db.Env[dbEnvNames.Port]
+		// The database is reached through the private network of the
+		// container, so its port doesn't need to be published.
+		inst.Env[iEnvNames.Host] = db.DockerNetworkAlias()
+		inst.Env[iEnvNames.Port] = db.InternalPort(dbEnvNames.Port)
 		if dbEnvNames.Username != nil {
 			inst.Env[iEnvNames.Username] = db.Env[*dbEnvNames.Username]
 		}
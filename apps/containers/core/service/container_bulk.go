@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/adapter"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/vsemver"
+	"github.com/vertex-center/vlog"
+)
+
+// bulkConcurrency is the maximum number of containers handled at the same
+// time by a bulk action.
+const bulkConcurrency = 4
+
+var (
+	ErrContainerFailedToStart = errors.New("the container failed to start")
+	ErrDependencyFailed       = errors.New("a container it depends on failed")
+)
+
+// Bulk applies an action to the containers, and returns the result for each
+// of them. The containers are handled by waves, so that a container is
+// started after the containers it depends on, and stopped before them. The
+// progress is dispatched with EventContainersBulk.
+func (s *ContainerService) Bulk(ctx context.Context, containers []*types.Container, request types.BulkRequest) ([]types.BulkResult, error) {
+	err := request.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("running bulk action",
+		vlog.String("action", request.Action),
+		vlog.Int("count", len(containers)),
+	)
+
+	waves := bulkWaves(containers)
+	if request.Reversed() {
+		for i, j := 0, len(waves)-1; i < j; i, j = i+1, j-1 {
+			waves[i], waves[j] = waves[j], waves[i]
+		}
+	}
+
+	var (
+		results = make([]types.BulkResult, 0, len(containers))
+		failed  = map[uuid.UUID]bool{}
+		mutex   sync.Mutex
+		sem     = make(chan struct{}, bulkConcurrency)
+	)
+
+	for _, wave := range waves {
+		var wg sync.WaitGroup
+		for _, inst := range wave {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(inst *types.Container) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				var result types.BulkResult
+				if !request.Reversed() && dependsOnFailed(inst, failed, &mutex) {
+					result = bulkResult(inst, false, ErrDependencyFailed)
+				} else {
+					skipped, err := s.bulk(ctx, inst, request)
+					result = bulkResult(inst, skipped, err)
+				}
+
+				mutex.Lock()
+				defer mutex.Unlock()
+				if result.Result == types.BulkResultFailed {
+					failed[inst.UUID] = true
+				}
+				results = append(results, result)
+				s.ctx.DispatchEvent(types.EventContainersBulk{
+					Action: request.Action,
+					Result: result,
+					Done:   len(results),
+					Total:  len(containers),
+					Tags:   inst.Tags,
+				})
+			}(inst)
+		}
+		wg.Wait()
+	}
+
+	return results, nil
+}
+
+// bulk applies the action to one container. It returns true if the action
+// was skipped, because the container was already in the requested state.
+func (s *ContainerService) bulk(ctx context.Context, inst *types.Container, request types.BulkRequest) (bool, error) {
+	switch request.Action {
+	case types.BulkActionStart:
+		if inst.IsRunning() {
+			return true, nil
+		}
+		return false, s.startAndWait(ctx, inst)
+	case types.BulkActionStop:
+		if !inst.IsRunning() {
+			return true, nil
+		}
+		return false, s.containerRunnerService.Stop(ctx, inst)
+	case types.BulkActionRestart:
+		if inst.IsRunning() {
+			err := s.containerRunnerService.Stop(ctx, inst)
+			if err != nil {
+				return false, err
+			}
+		}
+		return false, s.startAndWait(ctx, inst)
+	case types.BulkActionRecreate:
+		return false, s.containerRunnerService.RecreateContainer(ctx, inst)
+	case types.BulkActionUpdate:
+		return s.update(ctx, inst)
+	case types.BulkActionDelete:
+		return false, s.Delete(ctx, inst, types.DeleteContainerOptions{Volumes: request.Volumes})
+	case types.BulkActionSetLaunchOnStartup:
+		return false, s.containerSettingsService.SetLaunchOnStartup(inst, *request.LaunchOnStartup)
+	}
+	return false, types.ErrBulkActionInvalid
+}
+
+// update updates the container to its latest version, if an update is
+// available. A fixed version is replaced by the latest version, while a
+// version constraint is resolved again when the Docker container is
+// recreated. It returns true if the container is already up-to-date.
+func (s *ContainerService) update(ctx context.Context, inst *types.Container) (bool, error) {
+	err := s.containerRunnerService.CheckForUpdates(ctx, inst)
+	if err != nil {
+		return false, err
+	}
+	if inst.Update == nil {
+		return true, nil
+	}
+
+	if _, err := vsemver.Parse(inst.GetVersion()); err == nil {
+		err = s.containerSettingsService.SetVersion(inst, inst.Update.LatestVersion)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	running := inst.IsRunning()
+	if running {
+		err = s.containerRunnerService.Stop(ctx, inst)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = s.containerRunnerService.Delete(ctx, inst)
+	if err != nil && !errors.Is(err, adapter.ErrContainerNotFound) {
+		return false, err
+	}
+	inst.Update = nil
+
+	if running {
+		return false, s.startAndWait(ctx, inst)
+	}
+	return false, nil
+}
+
+// startAndWait starts the container, and waits until it is running. It
+// returns ErrContainerFailedToStart if the container failed to start.
+func (s *ContainerService) startAndWait(ctx context.Context, inst *types.Container) error {
+	started := make(chan error, 1)
+	notify := func(err error) {
+		select {
+		case started <- err:
+		default:
+		}
+	}
+
+	listener := vtypes.NewTempListener(func(e interface{}) {
+		change, ok := e.(types.EventContainerStatusChange)
+		if !ok || change.ContainerUUID != inst.UUID {
+			return
+		}
+		switch change.Status {
+		case types.ContainerStatusRunning:
+			notify(nil)
+		case types.ContainerStatusError, types.ContainerStatusOff:
+			notify(ErrContainerFailedToStart)
+		}
+	})
+
+	s.ctx.AddListener(listener)
+	defer s.ctx.RemoveListener(listener)
+
+	go func() {
+		err := s.containerRunnerService.Start(inst)
+		if err != nil {
+			notify(err)
+		}
+	}()
+
+	select {
+	case err := <-started:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// bulkWaves groups the containers in waves. A container is placed in a
+// wave after the containers it depends on, which are its databases. The
+// dependencies that are not part of the containers are ignored. The order of
+// the containers is kept within a wave.
+func bulkWaves(containers []*types.Container) [][]*types.Container {
+	byUUID := map[uuid.UUID]*types.Container{}
+	for _, inst := range containers {
+		byUUID[inst.UUID] = inst
+	}
+
+	levels := map[uuid.UUID]int{}
+	visiting := map[uuid.UUID]bool{}
+
+	var level func(id uuid.UUID) int
+	level = func(id uuid.UUID) int {
+		if l, ok := levels[id]; ok {
+			return l
+		}
+		// Dependency cycles are broken where they are found.
+		if visiting[id] {
+			return -1
+		}
+		visiting[id] = true
+
+		l := 0
+		for _, dep := range byUUID[id].Databases {
+			if _, ok := byUUID[dep]; !ok {
+				continue
+			}
+			if d := level(dep) + 1; d > l {
+				l = d
+			}
+		}
+		levels[id] = l
+		return l
+	}
+
+	var waves [][]*types.Container
+	for _, inst := range containers {
+		l := level(inst.UUID)
+		for len(waves) <= l {
+			waves = append(waves, []*types.Container{})
+		}
+		waves[l] = append(waves[l], inst)
+	}
+	return waves
+}
+
+func dependsOnFailed(inst *types.Container, failed map[uuid.UUID]bool, mutex *sync.Mutex) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, dep := range inst.Databases {
+		if failed[dep] {
+			return true
+		}
+	}
+	return false
+}
+
+func bulkResult(inst *types.Container, skipped bool, err error) types.BulkResult {
+	result := types.BulkResult{
+		ContainerUUID: inst.UUID,
+		Result:        types.BulkResultSuccess,
+	}
+	if err != nil {
+		result.Result = types.BulkResultFailed
+		result.Error = err.Error()
+	} else if skipped {
+		result.Result = types.BulkResultSkipped
+	}
+	return result
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+// maxPort is the highest port a container can publish.
+const maxPort = 65535
+
+// Clone creates a new container with the service, the settings and the
+// environment of the container. The ports of the clone are moved to free
+// ports, so that both containers can run at the same time. The volumes can
+// only be copied while the container is stopped, so that the copy is
+// consistent.
+func (s *ContainerService) Clone(ctx context.Context, inst *types.Container, options types.CloneContainerOptions) (*types.Container, error) {
+	err := options.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Volumes {
+		cloned := []*types.Container{inst}
+		if options.CloneDatabases() {
+			for _, id := range inst.Databases {
+				db, err := s.Get(id)
+				if err != nil {
+					return nil, err
+				}
+				cloned = append(cloned, db)
+			}
+		}
+		for _, c := range cloned {
+			if c.IsRunning() || c.IsBusy() {
+				return nil, types.ErrContainerStillRunning
+			}
+		}
+	}
+
+	id := uuid.New()
+	err = s.containerAdapter.Create(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The containers created by the clone, including the clones of its
+	// databases, are deleted if a step fails.
+	created := []*types.Container{{UUID: id, Service: inst.Service}}
+	rollback := func(err error) (*types.Container, error) {
+		log.Error(err, vlog.String("uuid", inst.UUID.String()))
+		for i := len(created) - 1; i >= 0; i-- {
+			err := s.Delete(ctx, created[i], types.DeleteContainerOptions{Volumes: true})
+			if err != nil {
+				log.Error(err, vlog.String("uuid", created[i].UUID.String()))
+			}
+		}
+		return nil, err
+	}
+
+	err = s.containerRunnerService.Install(id, inst.Service)
+	if err != nil {
+		return rollback(err)
+	}
+
+	err = s.containerServiceService.Save(&types.Container{UUID: id}, inst.Service)
+	if err != nil {
+		return rollback(err)
+	}
+
+	err = s.load(id)
+	if err != nil {
+		return rollback(err)
+	}
+
+	clone, err := s.Get(id)
+	if err != nil {
+		return rollback(err)
+	}
+
+	settings := inst.ContainerSettings
+	settings.DisplayName = inst.DisplayName + " (copy)"
+	if options.DisplayName != nil {
+		settings.DisplayName = *options.DisplayName
+	}
+	settings.Tags = append([]string{}, inst.Tags...)
+	if options.Tags != nil {
+		settings.Tags = options.Tags
+	}
+	// The clone is not a member of the stack of the container.
+	settings.Stack = nil
+	settings.Databases = map[string]uuid.UUID{}
+	for name, db := range inst.Databases {
+		settings.Databases[name] = db
+	}
+
+	err = s.containerSettingsService.Save(clone, settings)
+	if err != nil {
+		return rollback(err)
+	}
+
+	env := types.ContainerEnvVariables{}
+	for name, value := range inst.Env {
+		env[name] = value
+	}
+	err = s.reallocatePorts(clone, env)
+	if err != nil {
+		return rollback(err)
+	}
+	if options.RegenerateSecrets {
+		err = regenerateSecrets(clone, env)
+		if err != nil {
+			return rollback(err)
+		}
+	}
+
+	err = s.containerEnvService.Save(clone, env)
+	if err != nil {
+		return rollback(err)
+	}
+
+	if options.CloneDatabases() {
+		for name, dbUUID := range inst.Databases {
+			db, err := s.Get(dbUUID)
+			if err != nil {
+				return rollback(err)
+			}
+			// The copied data of the database still uses the old
+			// credentials, so they are only regenerated for an empty
+			// database.
+			dbClone, err := s.Clone(ctx, db, types.CloneContainerOptions{
+				Tags:              options.Tags,
+				RegenerateSecrets: options.RegenerateSecrets && !options.Volumes,
+				Volumes:           options.Volumes,
+			})
+			if err != nil {
+				return rollback(err)
+			}
+			created = append(created, dbClone)
+			clone.Databases[name] = dbClone.UUID
+		}
+	}
+
+	err = s.SetDatabases(clone, clone.Databases)
+	if err != nil {
+		return rollback(err)
+	}
+
+	if options.Volumes {
+		err = s.containerRunnerService.CopyVolumes(ctx, inst, clone)
+		if err != nil {
+			return rollback(err)
+		}
+	}
+
+	log.Info("container cloned",
+		vlog.String("uuid", inst.UUID.String()),
+		vlog.String("clone_uuid", clone.UUID.String()),
+	)
+
+	s.ctx.DispatchEvent(types.EventContainerCreated{})
+	s.ctx.DispatchEvent(types.EventContainersChange{})
+
+	return clone, nil
+}
+
+// reallocatePorts moves the ports of the environment to the next ports that
+// are not used by the other containers, including their replicas. It
+// returns ErrNoFreePort if there are not enough free ports.
+func (s *ContainerService) reallocatePorts(inst *types.Container, env types.ContainerEnvVariables) error {
+	used := s.usedPorts(inst.UUID)
+
+	replicas := inst.GetReplicas()
+	for _, e := range inst.Service.Env {
+		if e.Type != "port" {
+			continue
+		}
+		p, err := strconv.Atoi(env[e.Name])
+		if err != nil {
+			continue
+		}
+		p, err = nextFreePort(p, replicas, used)
+		if err != nil {
+			return fmt.Errorf("%s: %w", e.Name, err)
+		}
+		for replica := 0; replica < replicas; replica++ {
+			used[p+replica] = true
+		}
+		env[e.Name] = strconv.Itoa(p)
+	}
+	return nil
+}
+
+// checkReplicaPorts returns ErrReplicaPortsUsed if the ports published by
+// the replicas of the container are used by the other containers, or by
+// another port of the container.
+func (s *ContainerService) checkReplicaPorts(inst *types.Container, replicas int) error {
+	if replicas <= 1 {
+		return nil
+	}
+
+	used := s.usedPorts(inst.UUID)
+	for _, e := range inst.Service.Env {
+		if e.Type != "port" {
+			continue
+		}
+		p, err := strconv.Atoi(inst.Env[e.Name])
+		if err != nil {
+			continue
+		}
+		for replica := 0; replica < replicas; replica++ {
+			if used[p+replica] {
+				return fmt.Errorf("%w: %d", types.ErrReplicaPortsUsed, p+replica)
+			}
+			used[p+replica] = true
+		}
+	}
+	return nil
+}
+
+// usedPorts returns the ports published by the containers, including their
+// replicas, except the container with the given uuid.
+func (s *ContainerService) usedPorts(except uuid.UUID) map[int]bool {
+	used := map[int]bool{}
+	for _, c := range s.GetAll() {
+		if c.UUID == except {
+			continue
+		}
+		for _, e := range c.Service.Env {
+			if e.Type != "port" {
+				continue
+			}
+			p, err := strconv.Atoi(c.Env[e.Name])
+			if err != nil {
+				continue
+			}
+			for replica := 0; replica < c.GetReplicas(); replica++ {
+				used[p+replica] = true
+			}
+		}
+	}
+	return used
+}
+
+// nextFreePort returns the first port after port that is followed by count
+// free ports. It returns ErrNoFreePort if the ports would go over maxPort.
+func nextFreePort(port int, count int, used map[int]bool) (int, error) {
+	for p := port + 1; p+count-1 <= maxPort; p++ {
+		free := true
+		for i := 0; i < count; i++ {
+			if used[p+i] {
+				free = false
+				break
+			}
+		}
+		if free {
+			return p, nil
+		}
+	}
+	return 0, types.ErrNoFreePort
+}
+
+// regenerateSecrets replaces the secret values of the environment with
+// random values.
+func regenerateSecrets(inst *types.Container, env types.ContainerEnvVariables) error {
+	for _, e := range inst.Service.Env {
+		if e.Secret == nil || !*e.Secret {
+			continue
+		}
+		secret := make([]byte, 16)
+		_, err := rand.Read(secret)
+		if err != nil {
+			return err
+		}
+		env[e.Name] = hex.EncodeToString(secret)
+	}
+	return nil
+}
@@ -1,13 +1,21 @@
 package service
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
 	vtypes "github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/migration"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vlog"
 )
 
+// dockerEventsRetryDelay is the delay before reopening the Docker events
+// stream when it is interrupted.
+const dockerEventsRetryDelay = 5 * time.Second
+
 func (s *ContainerService) GetUUID() uuid.UUID {
 	return s.uuid
 }
@@ -15,9 +23,13 @@ func (s *ContainerService) GetUUID() uuid.UUID {
 func (s *ContainerService) OnEvent(e interface{}) {
 	switch e := e.(type) {
 	case vtypes.EventServerStart:
+		ctx, cancel := context.WithCancel(context.Background())
+		s.stopWatchingEvents = cancel
+
 		go func() {
 			log.Info("post-migration commands", vlog.Any("commands", e.PostMigrationCommands))
 			s.LoadAll()
+			go s.watchDockerEvents(ctx)
 			s.deleteContainersIfNeeded(e.PostMigrationCommands)
 			s.StartAll()
 			s.ctx.DispatchEvent(vtypes.EventAppReady{
@@ -25,6 +37,9 @@ func (s *ContainerService) OnEvent(e interface{}) {
 			})
 		}()
 	case vtypes.EventServerStop:
+		if s.stopWatchingEvents != nil {
+			s.stopWatchingEvents()
+		}
 		s.StopAll()
 	case vtypes.EventServerHardReset:
 		s.StopAll()
@@ -43,7 +58,7 @@ func (s *ContainerService) deleteContainersIfNeeded(postMigrationCommands []inte
 			log.Info("post-migration", vlog.String("action", "recreating all containers"))
 			containers := s.GetAll()
 			for _, c := range containers {
-				err := s.containerRunnerService.Delete(c)
+				err := s.containerRunnerService.Delete(context.Background(), c)
 				if err != nil {
 					log.Error(err)
 				}
@@ -52,3 +67,39 @@ func (s *ContainerService) deleteContainersIfNeeded(postMigrationCommands []inte
 		}
 	}
 }
+
+// watchDockerEvents reconciles the status of the containers with the Docker
+// events, until the context is done. The stream is reopened if the kernel
+// closes it.
+func (s *ContainerService) watchDockerEvents(ctx context.Context) {
+	for {
+		err := s.containerRunnerService.WatchEvents(ctx, s.onDockerEvent)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warn("docker events stream interrupted",
+			vlog.String("error", err.Error()),
+			vlog.String("retry_in", dockerEventsRetryDelay.String()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(dockerEventsRetryDelay):
+		}
+	}
+}
+
+func (s *ContainerService) onDockerEvent(e vtypes.DockerEvent) {
+	id, ok := types.ParseDockerContainerName(e.ContainerName)
+	if !ok {
+		return
+	}
+
+	inst, err := s.Get(id)
+	if err != nil {
+		return
+	}
+
+	s.containerRunnerService.HandleDockerEvent(inst, e)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	types2 "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/config"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+// runHooks runs the hooks of a step of the lifecycle of the container, in
+// order. It stops at the first failing hook that aborts the step, and
+// returns its error.
+func (s *ContainerRunnerService) runHooks(ctx context.Context, inst *types2.Container, step string) error {
+	for _, hook := range inst.GetHooks(step) {
+		err := s.runHook(ctx, inst, step, hook)
+		if err != nil && hook.Aborts() {
+			return err
+		}
+	}
+	return nil
+}
+
+// runHook runs a hook with its timeout. Its output is written in the logs
+// of the container.
+func (s *ContainerRunnerService) runHook(ctx context.Context, inst *types2.Container, step string, hook types2.Hook) error {
+	name := hook.DisplayName()
+	s.hookLog(inst, types2.LogKindHook, fmt.Sprintf("Running %s hook '%s'...", step, name))
+
+	ctx, cancel := context.WithTimeout(ctx, hook.GetTimeout(config.Current.Timeouts.Hook))
+	defer cancel()
+
+	err := s.adapter.RunHook(ctx, inst, hook, func(line string) {
+		s.hookLog(inst, types2.LogKindHook, line)
+	})
+	if err != nil {
+		s.hookLog(inst, types2.LogKindHookErr, fmt.Sprintf("The %s hook '%s' failed: %s", step, name, err.Error()))
+		log.Warn("hook failed",
+			vlog.String("uuid", inst.UUID.String()),
+			vlog.String("step", step),
+			vlog.String("hook", name),
+			vlog.String("error", err.Error()),
+		)
+		return fmt.Errorf("%s hook '%s': %w", step, name, err)
+	}
+	return nil
+}
+
+// runPostStartHooks runs the post-start hooks once the container is
+// running. The container is stopped if a hook aborts.
+func (s *ContainerRunnerService) runPostStartHooks(inst *types2.Container) {
+	ctx := context.Background()
+	err := s.runHooks(ctx, inst, types2.HookPostStart)
+	if err == nil {
+		return
+	}
+	err = s.Stop(ctx, inst)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+func (s *ContainerRunnerService) hookLog(inst *types2.Container, kind string, message string) {
+	s.ctx.DispatchEvent(types2.EventContainerLog{
+		ContainerUUID: inst.UUID,
+		Kind:          kind,
+		Message:       types2.NewLogLineMessageString(message),
+	})
+}
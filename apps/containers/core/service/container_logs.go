@@ -24,3 +24,12 @@ func NewContainerLogsService(ctx *app.Context, adapter port.ContainerLogsAdapter
 func (s *ContainerLogsService) GetLatestLogs(uuid uuid.UUID) ([]types.LogLine, error) {
 	return s.adapter.LoadBuffer(uuid)
 }
+
+func (s *ContainerLogsService) Query(uuid uuid.UUID, query types.LogQuery) (types.LogPage, error) {
+	if query.Limit <= 0 {
+		query.Limit = types.LogQueryDefaultLimit
+	} else if query.Limit > types.LogQueryMaxLimit {
+		query.Limit = types.LogQueryMaxLimit
+	}
+	return s.adapter.Query(uuid, query)
+}
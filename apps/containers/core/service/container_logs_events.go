@@ -59,13 +59,16 @@ func (s *ContainerLogsService) onLogReceived(e types2.EventContainerLog) {
 			downloads = types2.NewLogLineMessageDownloads(download.DownloadProgress)
 		}
 		s.adapter.Push(e.ContainerUUID, types2.LogLine{
+			Time:    e.Time,
 			Kind:    types2.LogKindDownloads,
 			Message: downloads,
 		})
 	default:
 		s.adapter.Push(e.ContainerUUID, types2.LogLine{
+			Time:    e.Time,
 			Kind:    e.Kind,
 			Message: e.Message,
+			Replica: e.Replica,
 		})
 	}
 }
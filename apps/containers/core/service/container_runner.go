@@ -2,8 +2,11 @@ package service
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"path"
 	"strings"
 	"sync"
@@ -17,6 +20,7 @@ import (
 	"github.com/vertex-center/vertex/apps/containers/adapter"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vertex/pkg/vsemver"
 	"github.com/vertex-center/vlog"
 )
 
@@ -48,8 +52,29 @@ func (s *ContainerRunnerService) Install(uuid uuid.UUID, service types2.Service)
 	return nil
 }
 
-func (s *ContainerRunnerService) Delete(inst *types2.Container) error {
-	return s.adapter.Delete(inst)
+func (s *ContainerRunnerService) Delete(ctx context.Context, inst *types2.Container) error {
+	return s.adapter.Delete(ctx, inst)
+}
+
+func (s *ContainerRunnerService) GetVolumes(ctx context.Context, inst *types2.Container) ([]types2.ContainerVolume, error) {
+	return s.adapter.GetVolumes(ctx, *inst)
+}
+
+// DeleteVolumes deletes the named volumes of a container. The container
+// must be deleted first, because Docker doesn't delete volumes in use.
+func (s *ContainerRunnerService) DeleteVolumes(ctx context.Context, inst *types2.Container) error {
+	log.Info("deleting container volumes", vlog.String("uuid", inst.UUID.String()))
+	return s.adapter.DeleteVolumes(ctx, inst)
+}
+
+// CopyVolumes copies the content of the volumes of a container into the
+// volumes of its clone. The container must not be running.
+func (s *ContainerRunnerService) CopyVolumes(ctx context.Context, from *types2.Container, to *types2.Container) error {
+	log.Info("copying container volumes",
+		vlog.String("from", from.UUID.String()),
+		vlog.String("to", to.UUID.String()),
+	)
+	return s.adapter.CopyVolumes(ctx, from, to)
 }
 
 // Start starts a container by its UUID.
@@ -79,73 +104,41 @@ func (s *ContainerRunnerService) Start(inst *types2.Container) error {
 		return ErrContainerAlreadyRunning
 	}
 
-	setStatus := func(status string) {
-		s.setStatus(inst, status)
-	}
+	// The container outlives the request that started it.
+	ctx := context.Background()
 
-	stdout, stderr, err := s.adapter.Start(inst, setStatus)
+	err := s.resolveVersion(ctx, inst)
 	if err != nil {
+		s.ctx.DispatchEvent(types2.EventContainerLog{
+			ContainerUUID: inst.UUID,
+			Kind:          types2.LogKindVertexErr,
+			Message:       types2.NewLogLineMessageString(err.Error()),
+		})
 		s.setStatus(inst, types2.ContainerStatusError)
 		return err
 	}
 
-	var wg sync.WaitGroup
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-
-		scanner := bufio.NewScanner(stdout)
-		for scanner.Scan() {
-			if scanner.Err() != nil {
-				break
-			}
-
-			if strings.HasPrefix(scanner.Text(), "DOWNLOAD") {
-				msg := strings.TrimPrefix(scanner.Text(), "DOWNLOAD")
-
-				var downloadProgress types2.DownloadProgress
-				err := json.Unmarshal([]byte(msg), &downloadProgress)
-				if err != nil {
-					log.Error(err)
-					continue
-				}
-
-				s.ctx.DispatchEvent(types2.EventContainerLog{
-					ContainerUUID: inst.UUID,
-					Kind:          types2.LogKindDownload,
-					Message:       types2.NewLogLineMessageDownload(&downloadProgress),
-				})
-				continue
-			}
+	err = s.runHooks(ctx, inst, types2.HookPreStart)
+	if err != nil {
+		s.setStatus(inst, types2.ContainerStatusError)
+		return err
+	}
 
-			s.ctx.DispatchEvent(types2.EventContainerLog{
-				ContainerUUID: inst.UUID,
-				Kind:          types2.LogKindOut,
-				Message:       types2.NewLogLineMessageString(scanner.Text()),
-			})
+	setStatus := func(status string) {
+		s.setStatus(inst, status)
+		if status == types2.ContainerStatusRunning {
+			go s.runPostStartHooks(inst)
 		}
-	}()
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
+	}
 
-		scanner := bufio.NewScanner(stderr)
-		for scanner.Scan() {
-			if scanner.Err() != nil {
-				break
-			}
-			s.ctx.DispatchEvent(types2.EventContainerLog{
-				ContainerUUID: inst.UUID,
-				Kind:          types2.LogKindErr,
-				Message:       types2.NewLogLineMessageString(scanner.Text()),
-			})
-		}
-	}()
+	stdout, stderr, err := s.adapter.Start(ctx, inst, setStatus)
+	if err != nil {
+		s.setStatus(inst, types2.ContainerStatusError)
+		return err
+	}
 
 	// Wait for the container until stopped
-	wg.Wait()
+	s.readLogs(inst, stdout, stderr)
 
 	// Log stopped
 	s.ctx.DispatchEvent(types2.EventContainerLog{
@@ -160,10 +153,43 @@ func (s *ContainerRunnerService) Start(inst *types2.Container) error {
 	return nil
 }
 
+// Attach reattaches Vertex to a container that is still running, like
+// after a restart of Vertex. The container is marked as running, and its
+// logs are read again. It returns adapter.ErrContainerNotRunning if the
+// container is not running.
+func (s *ContainerRunnerService) Attach(inst *types2.Container) error {
+	if inst.IsBusy() || inst.IsRunning() {
+		return nil
+	}
+
+	setStatus := func(status string) {
+		s.setStatus(inst, status)
+	}
+
+	stdout, stderr, err := s.adapter.Attach(context.Background(), inst, setStatus)
+	if err != nil {
+		return err
+	}
+
+	log.Info("container reattached",
+		vlog.String("uuid", inst.UUID.String()),
+	)
+
+	s.ctx.DispatchEvent(types2.EventContainerLog{
+		ContainerUUID: inst.UUID,
+		Kind:          types2.LogKindVertexOut,
+		Message:       types2.NewLogLineMessageString("Container reattached."),
+	})
+	s.setStatus(inst, types2.ContainerStatusRunning)
+
+	go s.readLogs(inst, stdout, stderr)
+	return nil
+}
+
 // Stop stops an container by its UUID.
 // If the container does not exist, it returns ErrContainerNotFound.
 // If the container is not running, it returns ErrContainerNotRunning.
-func (s *ContainerRunnerService) Stop(inst *types2.Container) error {
+func (s *ContainerRunnerService) Stop(ctx context.Context, inst *types2.Container) error {
 	if inst.IsBusy() {
 		return nil
 	}
@@ -179,7 +205,13 @@ func (s *ContainerRunnerService) Stop(inst *types2.Container) error {
 
 	s.setStatus(inst, types2.ContainerStatusStopping)
 
-	err := s.adapter.Stop(inst)
+	err := s.runHooks(ctx, inst, types2.HookPreStop)
+	if err != nil {
+		s.setStatus(inst, types2.ContainerStatusRunning)
+		return err
+	}
+
+	err = s.adapter.Stop(ctx, inst)
 	if err == nil {
 		s.ctx.DispatchEvent(types2.EventContainerLog{
 			ContainerUUID: inst.UUID,
@@ -194,41 +226,167 @@ func (s *ContainerRunnerService) Stop(inst *types2.Container) error {
 		s.setStatus(inst, types2.ContainerStatusOff)
 	} else {
 		s.setStatus(inst, types2.ContainerStatusRunning)
+		return err
 	}
 
-	return err
+	return s.runHooks(ctx, inst, types2.HookPostStop)
 }
 
-func (s *ContainerRunnerService) GetDockerContainerInfo(inst types2.Container) (map[string]any, error) {
-	return s.adapter.Info(inst)
+// GetDockerContainerInfo returns the info of the Docker container and of
+// its image. The values of the secret environment variables are masked.
+func (s *ContainerRunnerService) GetDockerContainerInfo(ctx context.Context, inst types2.Container) (types2.ContainerInfo, error) {
+	info, err := s.adapter.Info(ctx, inst)
+	if err != nil {
+		return types2.ContainerInfo{}, err
+	}
+	info.Container.Env = inst.MaskSecretEnv(info.Container.Env)
+	return info, nil
 }
 
-func (s *ContainerRunnerService) GetAllVersions(inst *types2.Container, useCache bool) ([]string, error) {
+// GetAllVersions returns all versions of the container image, sorted from the
+// newest to the oldest. Only the tags matching the service filter and the
+// container channel are returned.
+func (s *ContainerRunnerService) GetAllVersions(ctx context.Context, inst *types2.Container, useCache bool) ([]string, error) {
 	if !useCache || len(inst.CacheVersions) == 0 {
-		versions, err := s.adapter.GetAllVersions(*inst)
+		tags, err := s.adapter.GetAllVersions(ctx, *inst)
+		if err != nil {
+			return nil, err
+		}
+
+		tags, err = inst.Service.Methods.Docker.Versions.FilterTags(tags, inst.GetVersionChannel())
 		if err != nil {
 			return nil, err
 		}
+
+		versions := []string{}
+		for _, v := range vsemver.Sort(tags) {
+			versions = append(versions, v.String())
+		}
 		inst.CacheVersions = versions
 	}
 
 	return inst.CacheVersions, nil
 }
 
-func (s *ContainerRunnerService) CheckForUpdates(inst *types2.Container) error {
-	return s.adapter.CheckForUpdates(inst)
+// CheckForUpdates checks if a newer version of the container image is available.
+// If the container runs a semantic version, the newest matching tag is reported.
+// Otherwise, the image digests are compared.
+func (s *ContainerRunnerService) CheckForUpdates(ctx context.Context, inst *types2.Container) error {
+	current, err := s.currentVersion(ctx, inst)
+	if err != nil {
+		return s.adapter.CheckForUpdates(ctx, inst)
+	}
+
+	versions, err := s.GetAllVersions(ctx, inst, false)
+	if err != nil {
+		return err
+	}
+
+	latest, ok := latestVersion(current, inst.GetVersion(), vsemver.Sort(versions))
+	if !ok || latest.Compare(current) <= 0 {
+		log.Info("already up-to-date",
+			vlog.String("uuid", inst.UUID.String()),
+		)
+		inst.Update = nil
+		return nil
+	}
+
+	log.Info("a new update is available",
+		vlog.String("uuid", inst.UUID.String()),
+		vlog.String("latest", latest.String()),
+	)
+	inst.Update = &types2.ContainerUpdate{
+		CurrentVersion: current.String(),
+		LatestVersion:  latest.String(),
+	}
+	return nil
+}
+
+// latestVersion returns the newest of the sorted versions that can replace
+// the current version. If the version of the container is a constraint, like
+// "15.x", it is the newest version matching it. Otherwise, it is the newest
+// version of the same variant, like "15.5-alpine" for "15.4-alpine". The
+// prereleases are only updates of prereleases.
+func latestVersion(current vsemver.Version, version string, sorted []vsemver.Version) (vsemver.Version, bool) {
+	if c, err := vsemver.ParseConstraint(version); err == nil {
+		return c.Latest(sorted)
+	}
+	for _, v := range sorted {
+		if v.Variant != current.Variant {
+			continue
+		}
+		if v.IsPrerelease() && !current.IsPrerelease() {
+			continue
+		}
+		return v, true
+	}
+	return vsemver.Version{}, false
+}
+
+// currentVersion returns the semantic version of the image currently used by
+// the container. It returns an error if this version is not a semantic version.
+func (s *ContainerRunnerService) currentVersion(ctx context.Context, inst *types2.Container) (vsemver.Version, error) {
+	docker := inst.Service.Methods.Docker
+	if docker == nil || docker.Image == nil {
+		return vsemver.Version{}, vsemver.ErrInvalidVersion
+	}
+
+	tag, err := s.adapter.GetImageTag(ctx, *inst)
+	if errors.Is(err, adapter.ErrContainerNotFound) {
+		tag = inst.GetImageTag()
+	} else if err != nil {
+		return vsemver.Version{}, err
+	}
+	return vsemver.Parse(tag)
+}
+
+// resolveVersion resolves the version constraint of the container, if any. If the
+// Docker container already exists, the tag it uses is kept. Otherwise, the constraint
+// resolves to the newest matching version.
+func (s *ContainerRunnerService) resolveVersion(ctx context.Context, inst *types2.Container) error {
+	c, err := vsemver.ParseConstraint(inst.GetVersion())
+	if err != nil {
+		inst.ResolvedVersion = ""
+		return nil
+	}
+
+	tag, err := s.adapter.GetImageTag(ctx, *inst)
+	if err == nil {
+		inst.ResolvedVersion = tag
+		return nil
+	} else if !errors.Is(err, adapter.ErrContainerNotFound) {
+		return err
+	}
+
+	versions, err := s.GetAllVersions(ctx, inst, false)
+	if err != nil {
+		return err
+	}
+
+	latest, ok := c.Latest(vsemver.Sort(versions))
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoVersionMatching, c)
+	}
+
+	log.Info("version resolved",
+		vlog.String("uuid", inst.UUID.String()),
+		vlog.String("constraint", c.String()),
+		vlog.String("version", latest.String()),
+	)
+	inst.ResolvedVersion = latest.String()
+	return nil
 }
 
 // RecreateContainer recreates a container by its UUID.
-func (s *ContainerRunnerService) RecreateContainer(inst *types2.Container) error {
+func (s *ContainerRunnerService) RecreateContainer(ctx context.Context, inst *types2.Container) error {
 	if inst.IsRunning() {
-		err := s.adapter.Stop(inst)
+		err := s.adapter.Stop(ctx, inst)
 		if err != nil {
 			return err
 		}
 	}
 
-	err := s.adapter.Delete(inst)
+	err := s.adapter.Delete(ctx, inst)
 	if err != nil && !errors.Is(err, adapter.ErrContainerNotFound) {
 		return err
 	}
@@ -244,8 +402,234 @@ func (s *ContainerRunnerService) RecreateContainer(inst *types2.Container) error
 	return nil
 }
 
-func (s *ContainerRunnerService) WaitCondition(inst *types2.Container, cond vtypes.WaitContainerCondition) error {
-	return s.adapter.WaitCondition(inst, cond)
+// RollingRestart restarts the replicas of a running container one after the
+// other, so that it keeps serving requests during the restart.
+func (s *ContainerRunnerService) RollingRestart(ctx context.Context, inst *types2.Container) error {
+	if inst.IsBusy() || !inst.IsRunning() {
+		s.ctx.DispatchEvent(types2.EventContainerLog{
+			ContainerUUID: inst.UUID,
+			Kind:          types2.LogKindVertexErr,
+			Message:       types2.NewLogLineMessageString(ErrContainerNotRunning.Error()),
+		})
+		return ErrContainerNotRunning
+	}
+
+	s.ctx.DispatchEvent(types2.EventContainerLog{
+		ContainerUUID: inst.UUID,
+		Kind:          types2.LogKindVertexOut,
+		Message:       types2.NewLogLineMessageString("Restarting the replicas..."),
+	})
+
+	err := s.adapter.RollingRestart(ctx, inst)
+	if err != nil {
+		s.ctx.DispatchEvent(types2.EventContainerLog{
+			ContainerUUID: inst.UUID,
+			Kind:          types2.LogKindVertexErr,
+			Message:       types2.NewLogLineMessageString(fmt.Sprintf("Rolling restart failed: %s", err.Error())),
+		})
+		return err
+	}
+
+	s.ctx.DispatchEvent(types2.EventContainerLog{
+		ContainerUUID: inst.UUID,
+		Kind:          types2.LogKindVertexOut,
+		Message:       types2.NewLogLineMessageString("Replicas restarted."),
+	})
+
+	log.Info("container restarted",
+		vlog.String("uuid", inst.UUID.String()),
+		vlog.Int("replicas", inst.GetReplicas()),
+	)
+	return nil
+}
+
+func (s *ContainerRunnerService) WaitCondition(ctx context.Context, inst *types2.Container, cond vtypes.WaitContainerCondition) error {
+	return s.adapter.WaitCondition(ctx, inst, cond)
+}
+
+// readLogs dispatches the logs of the container, until both streams are
+// closed.
+func (s *ContainerRunnerService) readLogs(inst *types2.Container, stdout io.Reader, stderr io.Reader) {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			if scanner.Err() != nil {
+				break
+			}
+
+			replica, line := types2.ParseLogReplica(scanner.Text())
+			t, line := types2.ParseLogTimestamp(line)
+
+			if strings.HasPrefix(line, "DOWNLOAD") {
+				msg := strings.TrimPrefix(line, "DOWNLOAD")
+
+				var downloadProgress types2.DownloadProgress
+				err := json.Unmarshal([]byte(msg), &downloadProgress)
+				if err != nil {
+					log.Error(err)
+					continue
+				}
+
+				s.ctx.DispatchEvent(types2.EventContainerLog{
+					ContainerUUID: inst.UUID,
+					Kind:          types2.LogKindDownload,
+					Message:       types2.NewLogLineMessageDownload(&downloadProgress),
+				})
+				continue
+			}
+
+			s.ctx.DispatchEvent(types2.EventContainerLog{
+				ContainerUUID: inst.UUID,
+				Kind:          types2.LogKindOut,
+				Message:       types2.ParseLogLineMessage(line, inst.Service.LogFormat()),
+				Time:          t,
+				Replica:       replica,
+			})
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			if scanner.Err() != nil {
+				break
+			}
+			replica, line := types2.ParseLogReplica(scanner.Text())
+			t, line := types2.ParseLogTimestamp(line)
+			s.ctx.DispatchEvent(types2.EventContainerLog{
+				ContainerUUID: inst.UUID,
+				Kind:          types2.LogKindErr,
+				Message:       types2.ParseLogLineMessage(line, inst.Service.LogFormat()),
+				Time:          t,
+				Replica:       replica,
+			})
+		}
+	}()
+
+	wg.Wait()
+}
+
+func (s *ContainerRunnerService) WatchEvents(ctx context.Context, handle func(e vtypes.DockerEvent)) error {
+	return s.adapter.WatchEvents(ctx, handle)
+}
+
+// HandleDockerEvent reconciles the status of a container with an event of
+// its Docker container, so that the changes made outside of Vertex, like a
+// crash or a docker stop, are visible. The busy containers are ignored,
+// because Vertex is already changing their status.
+func (s *ContainerRunnerService) HandleDockerEvent(inst *types2.Container, e vtypes.DockerEvent) {
+	if inst.GetReplicas() > 1 {
+		s.handleReplicaDockerEvent(inst, e)
+		return
+	}
+
+	switch e.Action {
+	case vtypes.DockerEventStart:
+		if inst.IsBusy() {
+			return
+		}
+		s.setStatus(inst, types2.ContainerStatusRunning)
+	case vtypes.DockerEventDie:
+		// The die event following an oom event must keep the error status.
+		if inst.IsBusy() || !inst.IsRunning() {
+			return
+		}
+		kind := types2.LogKindVertexOut
+		if e.ExitCode != nil && *e.ExitCode != 0 {
+			kind = types2.LogKindVertexErr
+		}
+		msg := "Container exited."
+		if e.ExitCode != nil {
+			msg = fmt.Sprintf("Container exited with code %d.", *e.ExitCode)
+		}
+		s.ctx.DispatchEvent(types2.EventContainerLog{
+			ContainerUUID: inst.UUID,
+			Kind:          kind,
+			Message:       types2.NewLogLineMessageString(msg),
+		})
+		s.setHealth(inst, "")
+		s.setStatus(inst, types2.ContainerStatusOff)
+	case vtypes.DockerEventOOM:
+		s.ctx.DispatchEvent(types2.EventContainerLog{
+			ContainerUUID: inst.UUID,
+			Kind:          types2.LogKindVertexErr,
+			Message:       types2.NewLogLineMessageString("Container killed because it ran out of memory."),
+		})
+		s.setHealth(inst, "")
+		s.setStatus(inst, types2.ContainerStatusError)
+	case vtypes.DockerEventDestroy:
+		if inst.IsBusy() {
+			return
+		}
+		s.setHealth(inst, "")
+		if inst.Status != types2.ContainerStatusError {
+			s.setStatus(inst, types2.ContainerStatusOff)
+		}
+	case vtypes.DockerEventHealthStatus:
+		s.setHealth(inst, e.Health)
+	}
+}
+
+// handleReplicaDockerEvent handles the events of a container with replicas.
+// A replica stopping doesn't change the status, which is set by the adapter
+// once all the replicas are stopped. The health is the one of the first
+// replica.
+func (s *ContainerRunnerService) handleReplicaDockerEvent(inst *types2.Container, e vtypes.DockerEvent) {
+	_, replica, _ := types2.ParseDockerReplicaName(e.ContainerName)
+
+	switch e.Action {
+	case vtypes.DockerEventStart:
+		if inst.IsBusy() {
+			return
+		}
+		s.setStatus(inst, types2.ContainerStatusRunning)
+	case vtypes.DockerEventDie:
+		if inst.IsBusy() || !inst.IsRunning() {
+			return
+		}
+		kind := types2.LogKindVertexOut
+		if e.ExitCode != nil && *e.ExitCode != 0 {
+			kind = types2.LogKindVertexErr
+		}
+		msg := fmt.Sprintf("Replica %d exited.", replica)
+		if e.ExitCode != nil {
+			msg = fmt.Sprintf("Replica %d exited with code %d.", replica, *e.ExitCode)
+		}
+		s.ctx.DispatchEvent(types2.EventContainerLog{
+			ContainerUUID: inst.UUID,
+			Kind:          kind,
+			Message:       types2.NewLogLineMessageString(msg),
+			Replica:       replica,
+		})
+	case vtypes.DockerEventOOM:
+		s.ctx.DispatchEvent(types2.EventContainerLog{
+			ContainerUUID: inst.UUID,
+			Kind:          types2.LogKindVertexErr,
+			Message:       types2.NewLogLineMessageString(fmt.Sprintf("Replica %d killed because it ran out of memory.", replica)),
+			Replica:       replica,
+		})
+	case vtypes.DockerEventHealthStatus:
+		if replica == 0 {
+			s.setHealth(inst, e.Health)
+		}
+	}
+}
+
+func (s *ContainerRunnerService) setHealth(inst *types2.Container, health string) {
+	if inst.Health == health {
+		return
+	}
+
+	inst.Health = health
+	s.ctx.DispatchEvent(types2.EventContainersChange{})
 }
 
 func (s *ContainerRunnerService) setStatus(inst *types2.Container, status string) {
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/apps/containers/adapter"
+	types2 "github.com/vertex-center/vertex/apps/containers/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+	"github.com/vertex-center/vertex/pkg/vsemver"
+)
+
+type ContainerRunnerServiceTestSuite struct {
+	suite.Suite
+
+	service   *ContainerRunnerService
+	adapter   *MockContainerRunnerAdapter
+	container types2.Container
+}
+
+func TestContainerRunnerServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerRunnerServiceTestSuite))
+}
+
+func (suite *ContainerRunnerServiceTestSuite) SetupTest() {
+	suite.adapter = &MockContainerRunnerAdapter{}
+	suite.service = NewContainerRunnerService(app.NewContext(vtypes.NewVertexContext()), suite.adapter).(*ContainerRunnerService)
+	suite.container = types2.Container{
+		UUID:   uuid.New(),
+		Status: types2.ContainerStatusRunning,
+	}
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestAttach() {
+	suite.container.Status = types2.ContainerStatusOff
+	stdout := io.NopCloser(strings.NewReader(""))
+	stderr := io.NopCloser(strings.NewReader(""))
+	suite.adapter.On("Attach", &suite.container, mock.Anything).Return(stdout, stderr, nil)
+
+	err := suite.service.Attach(&suite.container)
+
+	suite.NoError(err)
+	suite.Equal(types2.ContainerStatusRunning, suite.container.Status)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestAttachNotRunning() {
+	suite.container.Status = types2.ContainerStatusOff
+	suite.adapter.On("Attach", &suite.container, mock.Anything).Return(nil, nil, adapter.ErrContainerNotRunning)
+
+	err := suite.service.Attach(&suite.container)
+
+	suite.ErrorIs(err, adapter.ErrContainerNotRunning)
+	suite.Equal(types2.ContainerStatusOff, suite.container.Status)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestGetDockerContainerInfo() {
+	secret := true
+	suite.container.Service.Env = []types2.ServiceEnv{{Name: "TOKEN", Secret: &secret}}
+	info := types2.ContainerInfo{Version: vtypes.InfoVersion}
+	info.Container.Env = []string{"TOKEN=abc"}
+	suite.adapter.On("Info", suite.container).Return(info, nil)
+
+	res, err := suite.service.GetDockerContainerInfo(context.Background(), suite.container)
+
+	suite.NoError(err)
+	suite.Equal(vtypes.InfoVersion, res.Version)
+	suite.Equal([]string{"TOKEN=" + types2.SecretEnvMask}, res.Container.Env)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestStopPreStopHookAborts() {
+	hook := types2.Hook{Exec: &types2.HookExec{Cmd: []string{"flush"}}}
+	suite.container.ContainerSettings.Hooks = &types2.Hooks{PreStop: []types2.Hook{hook}}
+	suite.adapter.On("RunHook", &suite.container, hook, mock.Anything).Return(types2.ErrHookFailed)
+
+	err := suite.service.Stop(context.Background(), &suite.container)
+
+	suite.ErrorIs(err, types2.ErrHookFailed)
+	suite.Equal(types2.ContainerStatusRunning, suite.container.Status)
+	suite.adapter.AssertNotCalled(suite.T(), "Stop", mock.Anything)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestStopPreStopHookContinues() {
+	continueOnFailure := types2.HookOnFailureContinue
+	hook := types2.Hook{
+		Exec:      &types2.HookExec{Cmd: []string{"flush"}},
+		OnFailure: &continueOnFailure,
+	}
+	suite.container.ContainerSettings.Hooks = &types2.Hooks{PreStop: []types2.Hook{hook}}
+	suite.adapter.On("RunHook", &suite.container, hook, mock.Anything).Return(types2.ErrHookFailed)
+	suite.adapter.On("Stop", &suite.container).Return(nil)
+
+	err := suite.service.Stop(context.Background(), &suite.container)
+
+	suite.NoError(err)
+	suite.Equal(types2.ContainerStatusOff, suite.container.Status)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestHandleDockerEventDie() {
+	code := 137
+	suite.service.HandleDockerEvent(&suite.container, vtypes.DockerEvent{Action: vtypes.DockerEventDie, ExitCode: &code})
+	suite.Equal(types2.ContainerStatusOff, suite.container.Status)
+
+	suite.service.HandleDockerEvent(&suite.container, vtypes.DockerEvent{Action: vtypes.DockerEventStart})
+	suite.Equal(types2.ContainerStatusRunning, suite.container.Status)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestHandleDockerEventOOM() {
+	suite.service.HandleDockerEvent(&suite.container, vtypes.DockerEvent{Action: vtypes.DockerEventOOM})
+	suite.service.HandleDockerEvent(&suite.container, vtypes.DockerEvent{Action: vtypes.DockerEventDie})
+
+	suite.Equal(types2.ContainerStatusError, suite.container.Status)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestHandleDockerEventBusy() {
+	suite.container.Status = types2.ContainerStatusStopping
+
+	suite.service.HandleDockerEvent(&suite.container, vtypes.DockerEvent{Action: vtypes.DockerEventDie})
+
+	suite.Equal(types2.ContainerStatusStopping, suite.container.Status)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestHandleDockerEventReplicaDie() {
+	replicas := 3
+	suite.container.ContainerSettings.Replicas = &replicas
+
+	suite.service.HandleDockerEvent(&suite.container, vtypes.DockerEvent{
+		Action:        vtypes.DockerEventDie,
+		ContainerName: suite.container.DockerReplicaName(2),
+	})
+
+	suite.Equal(types2.ContainerStatusRunning, suite.container.Status)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestRollingRestartNotRunning() {
+	suite.container.Status = types2.ContainerStatusOff
+
+	err := suite.service.RollingRestart(context.Background(), &suite.container)
+
+	suite.ErrorIs(err, ErrContainerNotRunning)
+	suite.adapter.AssertNotCalled(suite.T(), "RollingRestart", &suite.container)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestLatestVersion() {
+	sorted := vsemver.Sort([]string{"15.4-alpine", "16.0-rc1-alpine", "15.5", "15.5-alpine"})
+
+	current, err := vsemver.Parse("15.4-alpine")
+	suite.NoError(err)
+	latest, ok := latestVersion(current, "15.4-alpine", sorted)
+	suite.True(ok)
+	suite.Equal("15.5-alpine", latest.String())
+
+	current, err = vsemver.Parse("16.0-beta1-alpine")
+	suite.NoError(err)
+	latest, ok = latestVersion(current, "16.0-beta1-alpine", sorted)
+	suite.True(ok)
+	suite.Equal("16.0-rc1-alpine", latest.String())
+
+	latest, ok = latestVersion(current, "15.x", sorted)
+	suite.True(ok)
+	suite.Equal("15.5", latest.String())
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestHandleDockerEventHealthStatus() {
+	suite.service.HandleDockerEvent(&suite.container, vtypes.DockerEvent{Action: vtypes.DockerEventHealthStatus, Health: "unhealthy"})
+	suite.Equal("unhealthy", suite.container.Health)
+
+	suite.service.HandleDockerEvent(&suite.container, vtypes.DockerEvent{Action: vtypes.DockerEventDie})
+	suite.Equal("", suite.container.Health)
+}
+
+type MockContainerRunnerAdapter struct {
+	mock.Mock
+}
+
+func (m *MockContainerRunnerAdapter) Delete(ctx context.Context, inst *types2.Container) error {
+	args := m.Called(inst)
+	return args.Error(0)
+}
+
+func (m *MockContainerRunnerAdapter) Start(ctx context.Context, inst *types2.Container, setStatus func(status string)) (io.ReadCloser, io.ReadCloser, error) {
+	args := m.Called(inst, setStatus)
+	return readClosers(args)
+}
+
+func (m *MockContainerRunnerAdapter) Attach(ctx context.Context, inst *types2.Container, setStatus func(status string)) (io.ReadCloser, io.ReadCloser, error) {
+	args := m.Called(inst, setStatus)
+	return readClosers(args)
+}
+
+func (m *MockContainerRunnerAdapter) Stop(ctx context.Context, inst *types2.Container) error {
+	args := m.Called(inst)
+	return args.Error(0)
+}
+
+func (m *MockContainerRunnerAdapter) Info(ctx context.Context, inst types2.Container) (types2.ContainerInfo, error) {
+	args := m.Called(inst)
+	return args.Get(0).(types2.ContainerInfo), args.Error(1)
+}
+
+func (m *MockContainerRunnerAdapter) WaitCondition(ctx context.Context, inst *types2.Container, cond vtypes.WaitContainerCondition) error {
+	args := m.Called(inst, cond)
+	return args.Error(0)
+}
+
+func (m *MockContainerRunnerAdapter) RunHook(ctx context.Context, inst *types2.Container, hook types2.Hook, output func(line string)) error {
+	args := m.Called(inst, hook, output)
+	return args.Error(0)
+}
+
+func (m *MockContainerRunnerAdapter) CopyVolumes(ctx context.Context, from *types2.Container, to *types2.Container) error {
+	args := m.Called(from, to)
+	return args.Error(0)
+}
+
+func (m *MockContainerRunnerAdapter) RollingRestart(ctx context.Context, inst *types2.Container) error {
+	args := m.Called(inst)
+	return args.Error(0)
+}
+
+func (m *MockContainerRunnerAdapter) CheckForUpdates(ctx context.Context, inst *types2.Container) error {
+	args := m.Called(inst)
+	return args.Error(0)
+}
+
+func (m *MockContainerRunnerAdapter) HasUpdateAvailable(ctx context.Context, inst types2.Container) (bool, error) {
+	args := m.Called(inst)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockContainerRunnerAdapter) GetAllVersions(ctx context.Context, inst types2.Container) ([]string, error) {
+	args := m.Called(inst)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockContainerRunnerAdapter) GetImageTag(ctx context.Context, inst types2.Container) (string, error) {
+	args := m.Called(inst)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockContainerRunnerAdapter) GetVolumes(ctx context.Context, inst types2.Container) ([]types2.ContainerVolume, error) {
+	args := m.Called(inst)
+	return args.Get(0).([]types2.ContainerVolume), args.Error(1)
+}
+
+func (m *MockContainerRunnerAdapter) DeleteVolumes(ctx context.Context, inst *types2.Container) error {
+	args := m.Called(inst)
+	return args.Error(0)
+}
+
+func (m *MockContainerRunnerAdapter) WatchEvents(ctx context.Context, handle func(e vtypes.DockerEvent)) error {
+	args := m.Called(ctx, handle)
+	return args.Error(0)
+}
+
+func readClosers(args mock.Arguments) (io.ReadCloser, io.ReadCloser, error) {
+	stdout, _ := args.Get(0).(io.ReadCloser)
+	stderr, _ := args.Get(1).(io.ReadCloser)
+	return stdout, stderr, args.Error(2)
+}
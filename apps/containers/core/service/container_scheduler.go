@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/config"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+var (
+	ErrJobTimeout     = errors.New("the job did not exit in time")
+	ErrJobUnscheduled = errors.New("the job was unscheduled while running")
+)
+
+// ContainerSchedulerService starts and stops the containers according to
+// their schedule, and runs the scheduled jobs.
+type ContainerSchedulerService struct {
+	uuid uuid.UUID
+	ctx  *app.Context
+
+	containerService       port.ContainerService
+	containerRunnerService port.ContainerRunnerService
+
+	scheduler *gocron.Scheduler
+
+	// schedulerMutex prevents jobs from being scheduled while the scheduler
+	// is cleared.
+	schedulerMutex sync.Mutex
+
+	// jobs contains, for each container running a scheduled job, the
+	// function that stops waiting for the end of the job.
+	jobs      map[uuid.UUID]context.CancelFunc
+	jobsMutex sync.Mutex
+}
+
+type ContainerSchedulerServiceParams struct {
+	Ctx *app.Context
+
+	ContainerService       port.ContainerService
+	ContainerRunnerService port.ContainerRunnerService
+}
+
+func NewContainerSchedulerService(params ContainerSchedulerServiceParams) port.ContainerSchedulerService {
+	s := &ContainerSchedulerService{
+		uuid:                   uuid.New(),
+		ctx:                    params.Ctx,
+		containerService:       params.ContainerService,
+		containerRunnerService: params.ContainerRunnerService,
+		scheduler:              gocron.NewScheduler(time.Local),
+		jobs:                   map[uuid.UUID]context.CancelFunc{},
+	}
+	s.ctx.AddListener(s)
+	return s
+}
+
+// Schedule replaces the scheduled actions of the container with the ones
+// of its current schedule.
+func (s *ContainerSchedulerService) Schedule(inst *types.Container) error {
+	s.schedulerMutex.Lock()
+	defer s.schedulerMutex.Unlock()
+
+	s.unschedule(inst.UUID)
+
+	if inst.Schedule == nil {
+		return nil
+	}
+
+	err := inst.Schedule.Validate()
+	if err != nil {
+		return err
+	}
+
+	actions := map[string]*string{
+		types.ScheduleActionStart: inst.Schedule.Start,
+		types.ScheduleActionStop:  inst.Schedule.Stop,
+		types.ScheduleActionRun:   inst.Schedule.Run,
+	}
+
+	for action, expr := range actions {
+		if expr == nil {
+			continue
+		}
+		_, err := s.scheduler.
+			Cron(*expr).
+			Tag(inst.UUID.String()).
+			SingletonMode().
+			Do(s.run, inst.UUID, action)
+		if err != nil {
+			s.unschedule(inst.UUID)
+			return err
+		}
+	}
+
+	log.Info("container scheduled",
+		vlog.String("uuid", inst.UUID.String()),
+	)
+	return nil
+}
+
+// Unschedule removes the scheduled actions of the container, and stops
+// waiting for its running job.
+func (s *ContainerSchedulerService) Unschedule(id uuid.UUID) error {
+	s.schedulerMutex.Lock()
+	defer s.schedulerMutex.Unlock()
+
+	s.unschedule(id)
+
+	s.jobsMutex.Lock()
+	defer s.jobsMutex.Unlock()
+	if cancel, ok := s.jobs[id]; ok {
+		cancel()
+	}
+	return nil
+}
+
+func (s *ContainerSchedulerService) unschedule(id uuid.UUID) {
+	// RemoveByTag fails only if no job has this tag.
+	_ = s.scheduler.RemoveByTag(id.String())
+}
+
+func (s *ContainerSchedulerService) scheduleAll() {
+	for _, inst := range s.containerService.GetAll() {
+		err := s.Schedule(inst)
+		if err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// run executes a scheduled action, and dispatches its result.
+func (s *ContainerSchedulerService) run(id uuid.UUID, action string) {
+	inst, err := s.containerService.Get(id)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	log.Info("running scheduled action",
+		vlog.String("uuid", id.String()),
+		vlog.String("action", action),
+	)
+
+	event := types.EventContainerScheduled{
+		ContainerUUID: id,
+		Action:        action,
+	}
+
+	switch action {
+	case types.ScheduleActionStart:
+		if !inst.IsRunning() {
+			go func() {
+				err := s.containerRunnerService.Start(inst)
+				if err != nil {
+					log.Error(err)
+				}
+			}()
+		}
+	case types.ScheduleActionStop:
+		if inst.IsRunning() {
+			err = s.containerRunnerService.Stop(context.Background(), inst)
+		}
+	case types.ScheduleActionRun:
+		event.ExitCode, err = s.runJob(inst)
+	}
+
+	if err != nil {
+		event.Error = err.Error()
+	}
+	s.dispatch(event)
+}
+
+// runJob starts the container, and waits until it exits. It returns the
+// exit code of the container. It returns ErrJobTimeout if the job runs for
+// longer than the job timeout, in which case the container is stopped, and
+// ErrJobUnscheduled if the container is unscheduled while the job runs.
+func (s *ContainerSchedulerService) runJob(inst *types.Container) (*int, error) {
+	if inst.IsBusy() || inst.IsRunning() {
+		return nil, ErrContainerAlreadyRunning
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Current.Timeouts.Job)
+	defer cancel()
+
+	s.jobsMutex.Lock()
+	s.jobs[inst.UUID] = cancel
+	s.jobsMutex.Unlock()
+	defer func() {
+		s.jobsMutex.Lock()
+		delete(s.jobs, inst.UUID)
+		s.jobsMutex.Unlock()
+	}()
+
+	exited := make(chan struct{})
+	var once sync.Once
+
+	listener := vtypes.NewTempListener(func(e interface{}) {
+		change, ok := e.(types.EventContainerStatusChange)
+		if !ok || change.ContainerUUID != inst.UUID {
+			return
+		}
+		if change.Status == types.ContainerStatusOff || change.Status == types.ContainerStatusError {
+			once.Do(func() { close(exited) })
+		}
+	})
+
+	s.ctx.AddListener(listener)
+	defer s.ctx.RemoveListener(listener)
+
+	err := s.containerRunnerService.Start(inst)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-exited:
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrJobUnscheduled
+		}
+		err := s.containerRunnerService.Stop(context.Background(), inst)
+		if err != nil {
+			log.Error(err, vlog.String("uuid", inst.UUID.String()))
+		}
+		return nil, ErrJobTimeout
+	}
+
+	info, err := s.containerRunnerService.GetDockerContainerInfo(context.Background(), *inst)
+	if err != nil {
+		return nil, err
+	}
+	exitCode := info.Container.State.ExitCode
+	return &exitCode, nil
+}
+
+// dispatch dispatches the result of a scheduled action, and writes it in
+// the logs of the container.
+func (s *ContainerSchedulerService) dispatch(event types.EventContainerScheduled) {
+	kind := types.LogKindVertexOut
+	message := fmt.Sprintf("Scheduled %s done.", event.Action)
+	if event.ExitCode != nil {
+		message = fmt.Sprintf("Scheduled %s done with exit code %d.", event.Action, *event.ExitCode)
+	}
+	if event.Error != "" {
+		kind = types.LogKindVertexErr
+		message = fmt.Sprintf("Scheduled %s failed: %s", event.Action, event.Error)
+	}
+
+	s.ctx.DispatchEvent(types.EventContainerLog{
+		ContainerUUID: event.ContainerUUID,
+		Kind:          kind,
+		Message:       types.NewLogLineMessageString(message),
+	})
+	s.ctx.DispatchEvent(event)
+}
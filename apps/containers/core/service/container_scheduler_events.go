@@ -0,0 +1,31 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+)
+
+func (s *ContainerSchedulerService) GetUUID() uuid.UUID {
+	return s.uuid
+}
+
+func (s *ContainerSchedulerService) OnEvent(e interface{}) {
+	switch e := e.(type) {
+	case vtypes.EventServerStart:
+		s.scheduler.StartAsync()
+	case types.EventContainersLoaded:
+		s.scheduleAll()
+	case types.EventContainerDeleted:
+		err := s.Unschedule(e.ContainerUUID)
+		if err != nil {
+			log.Error(err)
+		}
+	case vtypes.EventServerStop:
+		s.schedulerMutex.Lock()
+		defer s.schedulerMutex.Unlock()
+		s.scheduler.Clear()
+		s.scheduler.Stop()
+	}
+}
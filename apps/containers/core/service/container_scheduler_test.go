@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+)
+
+type ContainerSchedulerServiceTestSuite struct {
+	suite.Suite
+	service *ContainerSchedulerService
+}
+
+func TestContainerSchedulerServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerSchedulerServiceTestSuite))
+}
+
+func (suite *ContainerSchedulerServiceTestSuite) SetupTest() {
+	suite.service = NewContainerSchedulerService(ContainerSchedulerServiceParams{
+		Ctx: app.NewContext(vtypes.NewVertexContext()),
+	}).(*ContainerSchedulerService)
+}
+
+func (suite *ContainerSchedulerServiceTestSuite) TestSchedule() {
+	start := "0 8 * * *"
+	stop := "0 20 * * *"
+	inst := &types.Container{
+		UUID: uuid.New(),
+		ContainerSettings: types.ContainerSettings{
+			Schedule: &types.ContainerSchedule{Start: &start, Stop: &stop},
+		},
+	}
+
+	err := suite.service.Schedule(inst)
+	suite.NoError(err)
+	suite.Equal(2, suite.service.scheduler.Len())
+
+	// Scheduling again replaces the previous jobs.
+	inst.Schedule = &types.ContainerSchedule{Run: &start}
+	err = suite.service.Schedule(inst)
+	suite.NoError(err)
+	suite.Equal(1, suite.service.scheduler.Len())
+
+	err = suite.service.Unschedule(inst.UUID)
+	suite.NoError(err)
+	suite.Equal(0, suite.service.scheduler.Len())
+}
+
+func (suite *ContainerSchedulerServiceTestSuite) TestScheduleInvalid() {
+	invalid := "every day"
+	inst := &types.Container{
+		UUID: uuid.New(),
+		ContainerSettings: types.ContainerSettings{
+			Schedule: &types.ContainerSchedule{Start: &invalid},
+		},
+	}
+
+	err := suite.service.Schedule(inst)
+	suite.ErrorIs(err, types.ErrInvalidSchedule)
+	suite.Equal(0, suite.service.scheduler.Len())
+}
+
+func (suite *ContainerSchedulerServiceTestSuite) TestUnscheduleCancelsJob() {
+	id := uuid.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	suite.service.jobs[id] = cancel
+
+	err := suite.service.Unschedule(id)
+	suite.NoError(err)
+	suite.ErrorIs(ctx.Err(), context.Canceled)
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"fmt"
+
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers/core/port"
 	"github.com/vertex-center/vertex/apps/containers/core/types"
@@ -53,7 +55,57 @@ func (s *ContainerSettingsService) SetVersion(inst *types.Container, value strin
 	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
 }
 
+// SetVersionChannel sets the channel used to list versions. The cached
+// versions are cleared, as they may belong to another channel.
+func (s *ContainerSettingsService) SetVersionChannel(inst *types.Container, channel string) error {
+	if inst.Service.Methods.Docker == nil {
+		return types.ErrVersionChannelNotFound
+	}
+	if _, err := inst.Service.Methods.Docker.Versions.FilterTags(nil, channel); err != nil {
+		return err
+	}
+	inst.VersionChannel = &channel
+	inst.CacheVersions = nil
+	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
+}
+
 func (s *ContainerSettingsService) SetTags(inst *types.Container, tags []string) error {
 	inst.Tags = tags
 	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
 }
+
+// SetHooks sets the hooks of the container, which are run after the hooks
+// of its service.
+func (s *ContainerSettingsService) SetHooks(inst *types.Container, hooks types.Hooks) error {
+	err := hooks.Validate()
+	if err != nil {
+		return err
+	}
+	inst.ContainerSettings.Hooks = &hooks
+	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
+}
+
+// SetReplicas sets the number of replicas of the container. The change is
+// applied the next time the container is started.
+func (s *ContainerSettingsService) SetReplicas(inst *types.Container, replicas int) error {
+	if replicas < 1 || replicas > types.MaxReplicas {
+		return fmt.Errorf("%w: must be between 1 and %d", types.ErrInvalidReplicas, types.MaxReplicas)
+	}
+	inst.ContainerSettings.Replicas = &replicas
+	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
+}
+
+// SetSchedule sets the schedule of the container. An empty schedule removes
+// it.
+func (s *ContainerSettingsService) SetSchedule(inst *types.Container, schedule types.ContainerSchedule) error {
+	err := schedule.Validate()
+	if err != nil {
+		return err
+	}
+	if schedule.IsEmpty() {
+		inst.Schedule = nil
+	} else {
+		inst.Schedule = &schedule
+	}
+	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"testing"
 
 	types2 "github.com/vertex-center/vertex/apps/containers/core/types"
@@ -29,18 +30,23 @@ func (suite *ContainerServiceTestSuite) SetupTest() {
 	}).(*ContainerService)
 
 	suite.containerA = types2.Container{
-		UUID: uuid.New(),
+		UUID:   uuid.New(),
+		Status: types2.ContainerStatusRunning,
 		Service: types2.Service{
+			ID:   "service-a",
 			Name: "service-a",
 		},
 		ContainerSettings: types2.ContainerSettings{
-			Tags: []string{"Global Tag", "Service A Tag 0", "Service A Tag 1"},
+			DisplayName: "Container A",
+			Tags:        []string{"Global Tag", "Service A Tag 0", "Service A Tag 1"},
 		},
 	}
 
 	suite.containerB = types2.Container{
-		UUID: uuid.New(),
+		UUID:   uuid.New(),
+		Status: types2.ContainerStatusOff,
 		Service: types2.Service{
+			ID:   "service-b",
 			Name: "service-b",
 			Features: &types2.Features{
 				Databases: &[]types2.DatabaseFeature{
@@ -55,12 +61,47 @@ func (suite *ContainerServiceTestSuite) SetupTest() {
 		},
 	}
 
+	suite.containerA.Databases = map[string]uuid.UUID{"postgres": suite.containerB.UUID}
+
 	suite.service.containers = map[uuid.UUID]*types2.Container{
 		suite.containerA.UUID: &suite.containerA,
 		suite.containerB.UUID: &suite.containerB,
 	}
 }
 
+func (suite *ContainerServiceTestSuite) TestReallocatePorts() {
+	replicas := 2
+	suite.containerA.Service.Env = []types2.ServiceEnv{{Type: "port", Name: "PORT"}}
+	suite.containerA.Env = types2.ContainerEnvVariables{"PORT": "8080"}
+	suite.containerA.ContainerSettings.Replicas = &replicas
+	suite.containerB.Service.Env = []types2.ServiceEnv{{Type: "port", Name: "DB_PORT"}}
+	suite.containerB.Env = types2.ContainerEnvVariables{"DB_PORT": "8082"}
+
+	clone := types2.Container{
+		UUID:    uuid.New(),
+		Service: suite.containerA.Service,
+	}
+	env := types2.ContainerEnvVariables{"PORT": "8080"}
+
+	err := suite.service.reallocatePorts(&clone, env)
+	suite.NoError(err)
+	suite.Equal("8083", env["PORT"])
+
+	env = types2.ContainerEnvVariables{"PORT": "65535"}
+	err = suite.service.reallocatePorts(&clone, env)
+	suite.ErrorIs(err, types2.ErrNoFreePort)
+}
+
+func (suite *ContainerServiceTestSuite) TestCheckReplicaPorts() {
+	suite.containerA.Service.Env = []types2.ServiceEnv{{Type: "port", Name: "PORT"}}
+	suite.containerA.Env = types2.ContainerEnvVariables{"PORT": "8080"}
+	suite.containerB.Service.Env = []types2.ServiceEnv{{Type: "port", Name: "DB_PORT"}}
+	suite.containerB.Env = types2.ContainerEnvVariables{"DB_PORT": "8082"}
+
+	suite.NoError(suite.service.checkReplicaPorts(&suite.containerA, 2))
+	suite.ErrorIs(suite.service.checkReplicaPorts(&suite.containerA, 3), types2.ErrReplicaPortsUsed)
+}
+
 func (suite *ContainerServiceTestSuite) TestSearch() {
 	tests := []struct {
 		query    types2.ContainerSearchQuery
@@ -121,6 +162,47 @@ func (suite *ContainerServiceTestSuite) TestSearch() {
 			},
 			[]uuid.UUID{suite.containerB.UUID},
 		},
+		// Statuses
+		{
+			types2.ContainerSearchQuery{
+				Statuses: &[]string{types2.ContainerStatusRunning},
+			},
+			[]uuid.UUID{suite.containerA.UUID},
+		},
+		// Service IDs
+		{
+			types2.ContainerSearchQuery{
+				ServiceIDs: &[]string{"service-b"},
+			},
+			[]uuid.UUID{suite.containerB.UUID},
+		},
+		// Text
+		{
+			types2.ContainerSearchQuery{
+				Text: &[]string{"SRV-B"}[0],
+			},
+			[]uuid.UUID{suite.containerB.UUID},
+		},
+		{
+			types2.ContainerSearchQuery{
+				Text: &[]string{"svca"}[0],
+			},
+			[]uuid.UUID{suite.containerA.UUID},
+		},
+		// Databases
+		{
+			types2.ContainerSearchQuery{
+				Databases: &[]uuid.UUID{suite.containerB.UUID},
+			},
+			[]uuid.UUID{suite.containerA.UUID},
+		},
+		// Scope
+		{
+			types2.ContainerSearchQuery{
+				Scope: &[]string{"Service A Tag 0"},
+			},
+			[]uuid.UUID{suite.containerA.UUID},
+		},
 		// Multiple
 		{
 			types2.ContainerSearchQuery{
@@ -132,13 +214,14 @@ func (suite *ContainerServiceTestSuite) TestSearch() {
 	}
 
 	for _, t := range tests {
-		results := suite.service.Search(t.query)
+		results, total := suite.service.Search(t.query)
 
 		suite.Len(results, len(t.expected))
+		suite.Equal(len(t.expected), total)
 
 		var resultUUIDs []uuid.UUID
-		for id := range results {
-			resultUUIDs = append(resultUUIDs, id)
+		for _, inst := range results {
+			resultUUIDs = append(resultUUIDs, inst.UUID)
 		}
 
 		for _, expected := range t.expected {
@@ -155,3 +238,85 @@ func (suite *ContainerServiceTestSuite) TestGetTags() {
 	suite.Contains(tags, "Service A Tag 0")
 	suite.Contains(tags, "Service A Tag 1")
 }
+
+func (suite *ContainerServiceTestSuite) TestBulkWaves() {
+	external := types2.Container{
+		UUID: uuid.New(),
+		ContainerSettings: types2.ContainerSettings{
+			Databases: map[string]uuid.UUID{"redis": uuid.New()},
+		},
+	}
+	suite.service.containers[external.UUID] = &external
+
+	containers, _ := suite.service.Search(types2.ContainerSearchQuery{})
+	waves := bulkWaves(containers)
+
+	suite.Require().Len(waves, 2)
+	suite.ElementsMatch([]*types2.Container{&suite.containerB, &external}, waves[0])
+	suite.Equal([]*types2.Container{&suite.containerA}, waves[1])
+}
+
+func (suite *ContainerServiceTestSuite) TestBulkWavesCycle() {
+	suite.containerB.Databases = map[string]uuid.UUID{"postgres": suite.containerA.UUID}
+
+	containers, _ := suite.service.Search(types2.ContainerSearchQuery{})
+	waves := bulkWaves(containers)
+
+	count := 0
+	for _, wave := range waves {
+		count += len(wave)
+	}
+	suite.Equal(2, count)
+}
+
+func (suite *ContainerServiceTestSuite) TestBulkSkipped() {
+	var progress []types2.EventContainersBulk
+	listener := vtypes.NewTempListener(func(e interface{}) {
+		if e, ok := e.(types2.EventContainersBulk); ok {
+			progress = append(progress, e)
+		}
+	})
+	suite.service.ctx.AddListener(listener)
+	defer suite.service.ctx.RemoveListener(listener)
+
+	containers := []*types2.Container{&suite.containerA}
+	results, err := suite.service.Bulk(context.Background(), containers, types2.BulkRequest{
+		Action: types2.BulkActionStart,
+	})
+
+	suite.NoError(err)
+	suite.Equal([]types2.BulkResult{{ContainerUUID: suite.containerA.UUID, Result: types2.BulkResultSkipped}}, results)
+	suite.Require().Len(progress, 1)
+	suite.Equal(1, progress[0].Done)
+	suite.Equal(1, progress[0].Total)
+}
+
+func (suite *ContainerServiceTestSuite) TestBulkInvalidAction() {
+	_, err := suite.service.Bulk(context.Background(), nil, types2.BulkRequest{Action: "invalid"})
+	suite.ErrorIs(err, types2.ErrBulkActionInvalid)
+
+	_, err = suite.service.Bulk(context.Background(), nil, types2.BulkRequest{Action: types2.BulkActionSetLaunchOnStartup})
+	suite.ErrorIs(err, types2.ErrBulkActionInvalid)
+}
+
+func (suite *ContainerServiceTestSuite) TestSearchSortAndPaginate() {
+	query := types2.ContainerSearchQuery{
+		Sort:  types2.ContainerSortService,
+		Order: types2.SortOrderDesc,
+	}
+	results, total := suite.service.Search(query)
+
+	suite.Equal(2, total)
+	suite.Equal([]*types2.Container{&suite.containerB, &suite.containerA}, results)
+
+	query.Offset = 1
+	query.Limit = 1
+	results, total = suite.service.Search(query)
+
+	suite.Equal(2, total)
+	suite.Equal([]*types2.Container{&suite.containerA}, results)
+
+	query.Offset = 5
+	results, _ = suite.service.Search(query)
+	suite.Empty(results)
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+const (
+	vertexImagePrefix    = "vertex_image_"
+	vertexVolumePrefix   = "vertex_volume_"
+	vertexContainerLabel = "vertex.container"
+	danglingImageTag     = "<none>:<none>"
+)
+
+type GarbageCollectorService struct {
+	uuid uuid.UUID
+	ctx  *app.Context
+
+	adapter          port.DockerResourcesAdapter
+	containerService port.ContainerService
+
+	scheduler *gocron.Scheduler
+
+	// collectMutex prevents the scheduled and the manual collections
+	// from running at the same time.
+	collectMutex sync.Mutex
+}
+
+type GarbageCollectorServiceParams struct {
+	Ctx *app.Context
+
+	Adapter          port.DockerResourcesAdapter
+	ContainerService port.ContainerService
+}
+
+func NewGarbageCollectorService(params GarbageCollectorServiceParams) port.GarbageCollectorService {
+	s := &GarbageCollectorService{
+		uuid:             uuid.New(),
+		ctx:              params.Ctx,
+		adapter:          params.Adapter,
+		containerService: params.ContainerService,
+	}
+	s.ctx.AddListener(s)
+	return s
+}
+
+// Collect removes the images that are no longer used by any Vertex container.
+// Images used by a Docker container are never removed. For each service, the
+// options.KeepLast most recent unused images are kept to allow rollbacks.
+// With options.Volumes, the unused volumes of deleted Vertex containers are
+// removed too.
+func (s *GarbageCollectorService) Collect(options types.GarbageCollectOptions) (types.GarbageCollectReport, error) {
+	s.collectMutex.Lock()
+	defer s.collectMutex.Unlock()
+
+	report := types.GarbageCollectReport{
+		Images:  []string{},
+		Volumes: []string{},
+	}
+
+	containers, err := s.adapter.ListContainers()
+	if err != nil {
+		return report, err
+	}
+
+	images, err := s.adapter.ListImages()
+	if err != nil {
+		return report, err
+	}
+
+	for _, image := range unusedImages(images, containers, s.containerService.GetAll(), options.KeepLast) {
+		err := s.adapter.DeleteImage(image.ID)
+		if err != nil {
+			log.Warn("failed to delete image",
+				vlog.String("id", image.ID),
+				vlog.String("error", err.Error()),
+			)
+			continue
+		}
+		report.Images = append(report.Images, image.ID)
+		report.ReclaimedBytes += uint64(image.Size)
+	}
+
+	if options.Volumes {
+		volumes, err := s.adapter.ListVolumes()
+		if err != nil {
+			return report, err
+		}
+
+		for _, volume := range unusedVolumes(volumes, s.containerService.GetAll()) {
+			err := s.adapter.DeleteVolume(volume.Name)
+			if err != nil {
+				log.Warn("failed to delete volume",
+					vlog.String("name", volume.Name),
+					vlog.String("error", err.Error()),
+				)
+				continue
+			}
+			report.Volumes = append(report.Volumes, volume.Name)
+			if volume.Size > 0 {
+				report.ReclaimedBytes += uint64(volume.Size)
+			}
+		}
+	}
+
+	if options.BuildCache {
+		res, err := s.adapter.PruneBuildCache()
+		if err != nil {
+			return report, err
+		}
+		report.ReclaimedBytes += res.SpaceReclaimed
+	}
+
+	log.Info("garbage collected",
+		vlog.Int("images", len(report.Images)),
+		vlog.Int("volumes", len(report.Volumes)),
+		vlog.Uint64("reclaimed_bytes", report.ReclaimedBytes),
+	)
+
+	s.ctx.DispatchEvent(types.EventGarbageCollected{
+		Report: report,
+	})
+
+	return report, nil
+}
+
+func (s *GarbageCollectorService) startCron() error {
+	s.scheduler = gocron.NewScheduler(time.Local)
+	_, err := s.scheduler.Every(1).Day().At("03:00").Do(func() {
+		_, err := s.Collect(types.DefaultGarbageCollectOptions())
+		if err != nil {
+			log.Error(err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	s.scheduler.StartAsync()
+	return nil
+}
+
+func (s *GarbageCollectorService) stopCron() {
+	if s.scheduler == nil {
+		return
+	}
+	s.scheduler.Clear()
+	s.scheduler.Stop()
+	s.scheduler = nil
+}
+
+// unusedImages returns the images that can be removed. An image is removable
+// if it is not used by any Docker container, if it was created by Vertex for
+// one of its containers or services, and if all its tags either belong to a
+// deleted Vertex container, or are older than the keepLast most recent
+// unused images of a service. The dangling and untagged images are never
+// removed, as nothing tells that Vertex created them.
+func unusedImages(images []vtypes.Image, containers []vtypes.Container, vertexContainers map[uuid.UUID]*types.Container, keepLast int) []vtypes.Image {
+	used := map[string]bool{}
+	for _, c := range containers {
+		used[c.ImageID] = true
+	}
+
+	repositories := map[string]bool{}
+	for _, c := range vertexContainers {
+		if c.Service.Methods.Docker != nil && c.Service.Methods.Docker.Image != nil {
+			repositories[*c.Service.Methods.Docker.Image] = true
+		}
+	}
+
+	// groups contains, for each repository, the unused images of this repository.
+	groups := map[string][]vtypes.Image{}
+	removable := map[string]bool{}
+
+	for _, image := range images {
+		if used[image.ID] {
+			continue
+		}
+
+		managed, foreign := false, false
+		for _, tag := range image.Tags {
+			if tag == danglingImageTag {
+				continue
+			}
+
+			repository := imageRepository(tag)
+			if strings.HasPrefix(repository, vertexImagePrefix) {
+				managed = true
+				id, err := uuid.Parse(strings.TrimPrefix(repository, vertexImagePrefix))
+				if err == nil {
+					if _, ok := vertexContainers[id]; !ok {
+						continue
+					}
+				}
+			} else if repositories[repository] {
+				managed = true
+			} else {
+				// This tag is not managed by Vertex.
+				foreign = true
+				continue
+			}
+
+			groups[repository] = append(groups[repository], image)
+		}
+		removable[image.ID] = managed && !foreign
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].Created > group[j].Created
+		})
+		for i := 0; i < len(group) && i < keepLast; i++ {
+			removable[group[i].ID] = false
+		}
+	}
+
+	var res []vtypes.Image
+	for _, image := range images {
+		if removable[image.ID] {
+			res = append(res, image)
+		}
+	}
+	return res
+}
+
+// unusedVolumes returns the volumes that can be removed. A volume is
+// removable if it is not used by any Docker container, and if it was created
+// for a Vertex container that no longer exists. The volumes that Vertex did
+// not create are never removed.
+func unusedVolumes(volumes []vtypes.Volume, vertexContainers map[uuid.UUID]*types.Container) []vtypes.Volume {
+	var res []vtypes.Volume
+	for _, volume := range volumes {
+		if volume.RefCount != 0 {
+			continue
+		}
+
+		id, ok := volume.Labels[vertexContainerLabel]
+		if !ok && strings.HasPrefix(volume.Name, vertexVolumePrefix) {
+			// The UUID is followed by the name of the volume.
+			id = strings.TrimPrefix(volume.Name, vertexVolumePrefix)
+			if len(id) > 36 {
+				id = id[:36]
+			}
+		}
+
+		containerUUID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		if _, ok := vertexContainers[containerUUID]; ok {
+			continue
+		}
+		res = append(res, volume)
+	}
+	return res
+}
+
+// imageRepository returns the repository of an image tag. The tag can contain
+// a registry port, like "host:5000/image:tag".
+func imageRepository(tag string) string {
+	i := strings.LastIndex(tag, ":")
+	if i == -1 || strings.Contains(tag[i:], "/") {
+		return tag
+	}
+	return tag[:i]
+}
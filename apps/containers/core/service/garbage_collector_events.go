@@ -0,0 +1,23 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+)
+
+func (s *GarbageCollectorService) GetUUID() uuid.UUID {
+	return s.uuid
+}
+
+func (s *GarbageCollectorService) OnEvent(e interface{}) {
+	switch e.(type) {
+	case vtypes.EventServerStart:
+		err := s.startCron()
+		if err != nil {
+			log.Error(err)
+		}
+	case vtypes.EventServerStop:
+		s.stopCron()
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"testing"
+
+	types2 "github.com/vertex-center/vertex/apps/containers/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type GarbageCollectorTestSuite struct {
+	suite.Suite
+
+	container types2.Container
+}
+
+func TestGarbageCollectorTestSuite(t *testing.T) {
+	suite.Run(t, new(GarbageCollectorTestSuite))
+}
+
+func (suite *GarbageCollectorTestSuite) SetupTest() {
+	image := "postgres"
+	suite.container = types2.Container{
+		UUID: uuid.New(),
+		Service: types2.Service{
+			Methods: types2.ServiceMethods{
+				Docker: &types2.ServiceMethodDocker{
+					Image: &image,
+				},
+			},
+		},
+	}
+}
+
+func (suite *GarbageCollectorTestSuite) TestUnusedImages() {
+	deleted := uuid.New()
+
+	images := []vtypes.Image{
+		{ID: "current", Tags: []string{"postgres:15.4"}, Created: 4},
+		{ID: "previous", Tags: []string{"postgres:15.3"}, Created: 3},
+		{ID: "old", Tags: []string{"postgres:15.2"}, Created: 2},
+		{ID: "other", Tags: []string{"nginx:latest"}, Created: 1},
+		{ID: "dangling", Tags: []string{"<none>:<none>"}, Created: 1},
+		{ID: "untagged", Created: 1},
+		{ID: "deleted", Tags: []string{"vertex_image_" + deleted.String() + ":latest"}, Created: 1},
+		{ID: "built", Tags: []string{"vertex_image_" + suite.container.UUID.String() + ":latest"}, Created: 1},
+	}
+	containers := []vtypes.Container{
+		{ImageID: "current"},
+	}
+	vertexContainers := map[uuid.UUID]*types2.Container{
+		suite.container.UUID: &suite.container,
+	}
+
+	unused := unusedImages(images, containers, vertexContainers, 1)
+
+	var ids []string
+	for _, image := range unused {
+		ids = append(ids, image.ID)
+	}
+	suite.Equal([]string{"old", "deleted"}, ids)
+
+	unused = unusedImages(images, containers, vertexContainers, 0)
+
+	ids = nil
+	for _, image := range unused {
+		ids = append(ids, image.ID)
+	}
+	suite.Equal([]string{"previous", "old", "deleted", "built"}, ids)
+}
+
+func (suite *GarbageCollectorTestSuite) TestUnusedVolumes() {
+	deleted := uuid.New()
+
+	volumes := []vtypes.Volume{
+		{Name: "foreign"},
+		{Name: "foreign_labelled", Labels: map[string]string{"com.example": "data"}},
+		{Name: "vertex_volume_" + deleted.String() + "_data"},
+		{Name: "labelled", Labels: map[string]string{"vertex.container": deleted.String()}},
+		{Name: "vertex_volume_" + suite.container.UUID.String() + "_data"},
+		{Name: "used", Labels: map[string]string{"vertex.container": deleted.String()}, RefCount: 1},
+		{Name: "unknown", Labels: map[string]string{"vertex.container": deleted.String()}, RefCount: -1},
+	}
+	vertexContainers := map[uuid.UUID]*types2.Container{
+		suite.container.UUID: &suite.container,
+	}
+
+	var names []string
+	for _, volume := range unusedVolumes(volumes, vertexContainers) {
+		names = append(names, volume.Name)
+	}
+	suite.Equal([]string{"vertex_volume_" + deleted.String() + "_data", "labelled"}, names)
+}
+
+func (suite *GarbageCollectorTestSuite) TestImageRepository() {
+	suite.Equal("postgres", imageRepository("postgres:15.4"))
+	suite.Equal("postgres", imageRepository("postgres"))
+	suite.Equal("host:5000/image", imageRepository("host:5000/image:1.0"))
+	suite.Equal("host:5000/image", imageRepository("host:5000/image"))
+}
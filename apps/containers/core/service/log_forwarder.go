@@ -0,0 +1,285 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+const (
+	logForwarderInterval  = 5 * time.Second
+	logForwarderBatchSize = 500
+
+	// logForwarderRetryBatches is the maximum number of buffered batches
+	// retried on each flush.
+	logForwarderRetryBatches = 20
+)
+
+type LogForwarderService struct {
+	uuid uuid.UUID
+	ctx  *app.Context
+
+	sinkAdapter      port.LogSinkAdapter
+	forwarderAdapter port.LogForwarderAdapter
+	bufferAdapter    port.LogBufferAdapter
+
+	containerService port.ContainerService
+
+	queue      []types.LogEntry
+	queueMutex sync.Mutex
+
+	// flushMutex prevents two flushes from sending the same buffer twice.
+	flushMutex sync.Mutex
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type LogForwarderServiceParams struct {
+	Ctx *app.Context
+
+	SinkAdapter      port.LogSinkAdapter
+	ForwarderAdapter port.LogForwarderAdapter
+	BufferAdapter    port.LogBufferAdapter
+
+	ContainerService port.ContainerService
+}
+
+func NewLogForwarderService(params LogForwarderServiceParams) port.LogForwarderService {
+	s := &LogForwarderService{
+		uuid:             uuid.New(),
+		ctx:              params.Ctx,
+		sinkAdapter:      params.SinkAdapter,
+		forwarderAdapter: params.ForwarderAdapter,
+		bufferAdapter:    params.BufferAdapter,
+		containerService: params.ContainerService,
+	}
+	s.ctx.AddListener(s)
+	return s
+}
+
+func (s *LogForwarderService) GetSinks() types.LogSinks {
+	return s.sinkAdapter.GetAll()
+}
+
+func (s *LogForwarderService) AddSink(sink types.LogSink) (uuid.UUID, error) {
+	err := sink.Validate()
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	id := uuid.New()
+	return id, s.sinkAdapter.Add(id, sink)
+}
+
+func (s *LogForwarderService) RemoveSink(id uuid.UUID) error {
+	err := s.sinkAdapter.Remove(id)
+	if err != nil {
+		return err
+	}
+	return s.bufferAdapter.Clear(id)
+}
+
+// push adds a log line to the queue of lines to forward.
+func (s *LogForwarderService) push(e types.EventContainerLog) {
+	if e.Kind == types.LogKindDownload || e.Kind == types.LogKindDownloads {
+		return
+	}
+	if len(s.sinkAdapter.GetAll()) == 0 {
+		return
+	}
+
+	line := types.LogLine{
+		Time:    e.Time,
+		Kind:    e.Kind,
+		Message: e.Message,
+	}
+	if line.Time.IsZero() {
+		line.Time = time.Now()
+	}
+	record := types.NewLogRecord(line)
+
+	entry := types.LogEntry{
+		Time:    record.Time,
+		Kind:    record.Kind,
+		Stream:  record.Stream,
+		Level:   record.Level,
+		Message: record.Message,
+		Labels:  s.labels(e.ContainerUUID),
+	}
+	if e.Replica != 0 {
+		entry.Labels[types.LogLabelReplica] = strconv.Itoa(e.Replica)
+	}
+
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+	s.queue = append(s.queue, entry)
+}
+
+func (s *LogForwarderService) labels(id uuid.UUID) map[string]string {
+	labels := map[string]string{
+		types.LogLabelContainerUUID: id.String(),
+	}
+
+	inst, err := s.containerService.Get(id)
+	if err != nil {
+		return labels
+	}
+
+	labels[types.LogLabelServiceID] = inst.Service.ID
+	if inst.DisplayName != "" {
+		labels[types.LogLabelDisplayName] = inst.DisplayName
+	}
+	if len(inst.Tags) > 0 {
+		labels[types.LogLabelTags] = strings.Join(inst.Tags, ",")
+	}
+	return labels
+}
+
+// flush sends the queued lines to all sinks. The lines that could not be
+// sent are kept in the buffer of the sink, and are retried on the next flush.
+// While the buffer is not empty, the new lines are appended to it, so that
+// the lines are sent in order.
+func (s *LogForwarderService) flush() {
+	s.flushMutex.Lock()
+	defer s.flushMutex.Unlock()
+
+	s.queueMutex.Lock()
+	entries := s.queue
+	s.queue = nil
+	s.queueMutex.Unlock()
+
+	for id, sink := range s.sinkAdapter.GetAll() {
+		empty, err := s.retry(id, sink)
+		if err != nil {
+			s.buffer(id, entries, err)
+			continue
+		}
+		if !empty {
+			s.buffer(id, entries, nil)
+			continue
+		}
+
+		err = s.send(sink, entries)
+		if err != nil {
+			s.buffer(id, entries, err)
+		}
+	}
+}
+
+// retry sends the lines kept in the buffer of the sink, batch by batch. Each
+// batch is removed from the buffer once sent, so that it is never sent twice.
+// It returns true if the buffer is empty.
+func (s *LogForwarderService) retry(id uuid.UUID, sink types.LogSink) (bool, error) {
+	sent := 0
+	defer func() {
+		if sent > 0 {
+			log.Info("sent buffered logs",
+				vlog.String("sink", id.String()),
+				vlog.Int("count", sent),
+			)
+		}
+	}()
+
+	for i := 0; i < logForwarderRetryBatches; i++ {
+		buffered, err := s.bufferAdapter.Load(id, logForwarderBatchSize)
+		if err != nil {
+			return false, err
+		}
+		if len(buffered) == 0 {
+			return true, s.bufferAdapter.Clear(id)
+		}
+
+		err = s.forwarderAdapter.Send(sink, buffered)
+		if err != nil {
+			return false, err
+		}
+
+		err = s.bufferAdapter.Trim(id, len(buffered))
+		if err != nil {
+			return false, err
+		}
+		sent += len(buffered)
+	}
+	return false, nil
+}
+
+func (s *LogForwarderService) send(sink types.LogSink, entries []types.LogEntry) error {
+	for i := 0; i < len(entries); i += logForwarderBatchSize {
+		end := i + logForwarderBatchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		err := s.forwarderAdapter.Send(sink, entries[i:end])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buffer keeps the lines in the buffer of the sink. The reason is nil if the
+// lines are buffered only because older lines are still buffered.
+func (s *LogForwarderService) buffer(id uuid.UUID, entries []types.LogEntry, reason error) {
+	if reason != nil {
+		log.Warn("failed to forward logs",
+			vlog.String("sink", id.String()),
+			vlog.String("error", reason.Error()),
+		)
+	}
+
+	if len(entries) == 0 {
+		return
+	}
+
+	err := s.bufferAdapter.Push(id, entries)
+	if err != nil {
+		log.Error(err,
+			vlog.String("sink", id.String()),
+			vlog.Int("dropped", len(entries)),
+		)
+	}
+}
+
+func (s *LogForwarderService) start() {
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go func(stop <-chan struct{}, done chan<- struct{}) {
+		defer close(done)
+
+		ticker := time.NewTicker(logForwarderInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.flush()
+			case <-stop:
+				s.flush()
+				return
+			}
+		}
+	}(s.stop, s.done)
+}
+
+func (s *LogForwarderService) shutdown() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	s.stop = nil
+	s.done = nil
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+)
+
+func (s *LogForwarderService) GetUUID() uuid.UUID {
+	return s.uuid
+}
+
+func (s *LogForwarderService) OnEvent(e interface{}) {
+	switch e := e.(type) {
+	case types.EventContainerLog:
+		s.push(e)
+	case vtypes.EventServerStart:
+		s.start()
+	case vtypes.EventServerStop:
+		s.shutdown()
+	}
+}
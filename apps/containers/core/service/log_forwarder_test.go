@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+)
+
+type LogForwarderServiceTestSuite struct {
+	suite.Suite
+
+	service   *LogForwarderService
+	forwarder *MockLogForwarderAdapter
+	buffer    *MockLogBufferAdapter
+
+	sinkID uuid.UUID
+	sink   types.LogSink
+}
+
+func TestLogForwarderServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(LogForwarderServiceTestSuite))
+}
+
+func (suite *LogForwarderServiceTestSuite) SetupTest() {
+	suite.sinkID = uuid.New()
+	suite.sink = types.LogSink{Type: types.LogSinkTypeHTTP, URL: "http://localhost"}
+	suite.forwarder = &MockLogForwarderAdapter{}
+	suite.buffer = &MockLogBufferAdapter{}
+
+	ctx := app.NewContext(vtypes.NewVertexContext())
+	suite.service = NewLogForwarderService(LogForwarderServiceParams{
+		Ctx:              ctx,
+		SinkAdapter:      &MockLogSinkAdapter{sinks: types.LogSinks{suite.sinkID: suite.sink}},
+		ForwarderAdapter: suite.forwarder,
+		BufferAdapter:    suite.buffer,
+		ContainerService: NewContainerService(ContainerServiceParams{Ctx: ctx}),
+	}).(*LogForwarderService)
+}
+
+func (suite *LogForwarderServiceTestSuite) TestFlushBuffersOnFailure() {
+	suite.service.push(types.EventContainerLog{
+		ContainerUUID: uuid.New(),
+		Kind:          types.LogKindOut,
+		Message:       types.NewLogLineMessageString("hello"),
+	})
+
+	suite.buffer.On("Load", suite.sinkID, logForwarderBatchSize).Return([]types.LogEntry(nil), nil).Once()
+	suite.buffer.On("Clear", suite.sinkID).Return(nil).Once()
+	suite.forwarder.On("Send", suite.sink, mock.Anything).Return(errors.New("sink is down")).Once()
+	suite.buffer.On("Push", suite.sinkID, mock.MatchedBy(func(entries []types.LogEntry) bool {
+		return len(entries) == 1 && entries[0].Message == "hello"
+	})).Return(nil).Once()
+
+	suite.service.flush()
+
+	suite.forwarder.AssertExpectations(suite.T())
+	suite.buffer.AssertExpectations(suite.T())
+	suite.Empty(suite.service.queue)
+}
+
+func (suite *LogForwarderServiceTestSuite) TestFlushRetriesBuffer() {
+	buffered := []types.LogEntry{{Message: "buffered"}}
+
+	suite.buffer.On("Load", suite.sinkID, logForwarderBatchSize).Return(buffered, nil).Once()
+	suite.forwarder.On("Send", suite.sink, buffered).Return(nil).Once()
+	suite.buffer.On("Trim", suite.sinkID, 1).Return(nil).Once()
+	suite.buffer.On("Load", suite.sinkID, logForwarderBatchSize).Return([]types.LogEntry(nil), nil).Once()
+	suite.buffer.On("Clear", suite.sinkID).Return(nil).Once()
+
+	suite.service.flush()
+
+	suite.forwarder.AssertExpectations(suite.T())
+	suite.buffer.AssertExpectations(suite.T())
+}
+
+func (suite *LogForwarderServiceTestSuite) TestFlushRetryPartialFailure() {
+	suite.service.push(types.EventContainerLog{
+		ContainerUUID: uuid.New(),
+		Kind:          types.LogKindOut,
+		Message:       types.NewLogLineMessageString("new"),
+	})
+
+	first := []types.LogEntry{{Message: "first"}}
+	second := []types.LogEntry{{Message: "second"}}
+
+	suite.buffer.On("Load", suite.sinkID, logForwarderBatchSize).Return(first, nil).Once()
+	suite.forwarder.On("Send", suite.sink, first).Return(nil).Once()
+	suite.buffer.On("Trim", suite.sinkID, 1).Return(nil).Once()
+	suite.buffer.On("Load", suite.sinkID, logForwarderBatchSize).Return(second, nil).Once()
+	suite.forwarder.On("Send", suite.sink, second).Return(errors.New("sink is down")).Once()
+	suite.buffer.On("Push", suite.sinkID, mock.MatchedBy(func(entries []types.LogEntry) bool {
+		return len(entries) == 1 && entries[0].Message == "new"
+	})).Return(nil).Once()
+
+	suite.service.flush()
+
+	// The first batch is removed from the buffer, and never sent again.
+	suite.forwarder.AssertExpectations(suite.T())
+	suite.buffer.AssertExpectations(suite.T())
+}
+
+func (suite *LogForwarderServiceTestSuite) TestAddSinkInvalid() {
+	_, err := suite.service.AddSink(types.LogSink{Type: types.LogSinkTypeSyslog, URL: "http://localhost"})
+	suite.ErrorIs(err, types.ErrLogSinkURLInvalid)
+
+	_, err = suite.service.AddSink(types.LogSink{Type: "unknown", URL: "http://localhost"})
+	suite.ErrorIs(err, types.ErrLogSinkTypeInvalid)
+}
+
+type MockLogSinkAdapter struct {
+	sinks types.LogSinks
+}
+
+func (m *MockLogSinkAdapter) GetAll() types.LogSinks {
+	return m.sinks
+}
+
+func (m *MockLogSinkAdapter) Add(id uuid.UUID, sink types.LogSink) error {
+	m.sinks[id] = sink
+	return nil
+}
+
+func (m *MockLogSinkAdapter) Remove(id uuid.UUID) error {
+	delete(m.sinks, id)
+	return nil
+}
+
+type MockLogForwarderAdapter struct {
+	mock.Mock
+}
+
+func (m *MockLogForwarderAdapter) Send(sink types.LogSink, entries []types.LogEntry) error {
+	args := m.Called(sink, entries)
+	return args.Error(0)
+}
+
+type MockLogBufferAdapter struct {
+	mock.Mock
+}
+
+func (m *MockLogBufferAdapter) Push(sinkID uuid.UUID, entries []types.LogEntry) error {
+	args := m.Called(sinkID, entries)
+	return args.Error(0)
+}
+
+func (m *MockLogBufferAdapter) Load(sinkID uuid.UUID, limit int) ([]types.LogEntry, error) {
+	args := m.Called(sinkID, limit)
+	return args.Get(0).([]types.LogEntry), args.Error(1)
+}
+
+func (m *MockLogBufferAdapter) Trim(sinkID uuid.UUID, count int) error {
+	args := m.Called(sinkID, count)
+	return args.Error(0)
+}
+
+func (m *MockLogBufferAdapter) Clear(sinkID uuid.UUID) error {
+	args := m.Called(sinkID)
+	return args.Error(0)
+}
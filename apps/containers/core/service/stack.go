@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+var ErrStackActionFailed = errors.New("the action failed for some members of the stack")
+
+// StackService installs and manages groups of containers as a unit. The
+// actions on a stack are run with the bulk actions, so that the members
+// are started after the members they depend on.
+type StackService struct {
+	uuid uuid.UUID
+	ctx  *app.Context
+
+	adapter port.StackAdapter
+
+	containerService         port.ContainerService
+	containerEnvService      port.ContainerEnvService
+	containerSettingsService port.ContainerSettingsService
+	serviceService           port.ServiceService
+
+	// statuses are the last statuses dispatched for each stack.
+	statuses      map[uuid.UUID]string
+	statusesMutex sync.Mutex
+}
+
+type StackServiceParams struct {
+	Ctx     *app.Context
+	Adapter port.StackAdapter
+
+	ContainerService         port.ContainerService
+	ContainerEnvService      port.ContainerEnvService
+	ContainerSettingsService port.ContainerSettingsService
+	ServiceService           port.ServiceService
+}
+
+func NewStackService(params StackServiceParams) port.StackService {
+	s := &StackService{
+		uuid:                     uuid.New(),
+		ctx:                      params.Ctx,
+		adapter:                  params.Adapter,
+		containerService:         params.ContainerService,
+		containerEnvService:      params.ContainerEnvService,
+		containerSettingsService: params.ContainerSettingsService,
+		serviceService:           params.ServiceService,
+		statuses:                 map[uuid.UUID]string{},
+	}
+	s.ctx.AddListener(s)
+	return s
+}
+
+// GetAll returns the stacks, sorted by name.
+func (s *StackService) GetAll() []types.Stack {
+	var stacks []types.Stack
+	for _, stack := range s.adapter.GetAll() {
+		stack.Status = types.StackStatus(s.members(stack))
+		stacks = append(stacks, stack)
+	}
+	sort.Slice(stacks, func(i, j int) bool {
+		return stacks[i].Definition.Name < stacks[j].Definition.Name
+	})
+	return stacks
+}
+
+// Get returns a stack with its status. It returns ErrStackNotFound if the
+// stack doesn't exist.
+func (s *StackService) Get(id uuid.UUID) (types.Stack, error) {
+	stack, err := s.adapter.Get(id)
+	if err != nil {
+		return types.Stack{}, err
+	}
+	stack.Status = types.StackStatus(s.members(stack))
+	return stack, nil
+}
+
+// Tags returns the tags shared by all the members of the stack. A role
+// limited to some tags can only manage the stack if it is allowed to manage
+// all its members.
+func (s *StackService) Tags(id uuid.UUID) ([]string, error) {
+	stack, err := s.adapter.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	members := s.members(stack)
+	if len(members) == 0 {
+		return []string{}, nil
+	}
+
+	tags := []string{}
+	for _, tag := range members[0].Tags {
+		shared := true
+		for _, inst := range members[1:] {
+			if !inst.HasTag(tag) {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// Install installs all the members of a stack, and links them together. If
+// a member fails to install, the members already installed are deleted.
+func (s *StackService) Install(ctx context.Context, definition types.StackDefinition) (types.Stack, error) {
+	err := definition.Validate()
+	if err != nil {
+		return types.Stack{}, err
+	}
+
+	services := map[string]types.Service{}
+	for _, m := range definition.Members {
+		service, err := s.serviceService.GetById(m.ServiceID)
+		if err != nil {
+			return types.Stack{}, fmt.Errorf("%s: %w", m.Name, err)
+		}
+		services[m.Name] = service
+	}
+
+	log.Info("installing stack",
+		vlog.String("name", definition.Name),
+		vlog.Int("members", len(definition.Members)),
+	)
+
+	stack := types.Stack{
+		UUID:       uuid.New(),
+		Definition: definition,
+		Members:    map[string]uuid.UUID{},
+	}
+
+	var installed []*types.Container
+	rollback := func(err error) (types.Stack, error) {
+		log.Error(err, vlog.String("stack", definition.Name))
+		for i := len(installed) - 1; i >= 0; i-- {
+			err := s.containerService.Delete(ctx, installed[i], types.DeleteContainerOptions{Volumes: true})
+			if err != nil {
+				log.Error(err, vlog.String("uuid", installed[i].UUID.String()))
+			}
+		}
+		return types.Stack{}, err
+	}
+
+	for _, m := range definition.Members {
+		inst, err := s.containerService.Install(services[m.Name], "docker")
+		if err != nil {
+			return rollback(fmt.Errorf("%s: %w", m.Name, err))
+		}
+		installed = append(installed, inst)
+		stack.Members[m.Name] = inst.UUID
+
+		err = s.configure(inst, stack, m)
+		if err != nil {
+			return rollback(fmt.Errorf("%s: %w", m.Name, err))
+		}
+	}
+
+	// The links are set once all the members are installed, because a
+	// member can depend on a member defined after it.
+	for i, m := range definition.Members {
+		if len(m.Databases) == 0 {
+			continue
+		}
+		databases := map[string]uuid.UUID{}
+		for db, member := range m.Databases {
+			databases[db] = stack.Members[member]
+		}
+		err := s.containerService.SetDatabases(installed[i], databases)
+		if err != nil {
+			return rollback(fmt.Errorf("%s: %w", m.Name, err))
+		}
+	}
+
+	err = s.adapter.Save(stack)
+	if err != nil {
+		return rollback(err)
+	}
+
+	log.Info("stack installed",
+		vlog.String("name", definition.Name),
+		vlog.String("uuid", stack.UUID.String()),
+	)
+
+	s.ctx.DispatchEvent(types.EventStacksChange{})
+
+	stack.Status = types.StackStatus(installed)
+	return stack, nil
+}
+
+// configure sets the settings and the env of a new member of the stack.
+func (s *StackService) configure(inst *types.Container, stack types.Stack, m types.StackMemberDefinition) error {
+	settings := inst.ContainerSettings
+	settings.DisplayName = fmt.Sprintf("%s - %s", stack.Definition.Name, m.Name)
+	settings.Tags = m.Tags
+	settings.Stack = &types.StackMember{
+		UUID: stack.UUID,
+		Name: m.Name,
+	}
+
+	err := s.containerSettingsService.Save(inst, settings)
+	if err != nil {
+		return err
+	}
+
+	env := inst.Env
+	for _, e := range inst.Service.Env {
+		if value, ok := stack.Definition.Env[e.Name]; ok {
+			env[e.Name] = value
+		}
+	}
+	for name, value := range m.Env {
+		env[name] = value
+	}
+	return s.containerEnvService.Save(inst, env)
+}
+
+// Run runs a bulk action on all the members of the stack. It returns
+// ErrStackActionFailed if the action failed for some members. The stack is
+// deleted with Delete instead.
+func (s *StackService) Run(ctx context.Context, id uuid.UUID, action string) ([]types.BulkResult, error) {
+	switch action {
+	case types.BulkActionStart, types.BulkActionStop, types.BulkActionRestart, types.BulkActionUpdate:
+	default:
+		return nil, types.ErrBulkActionInvalid
+	}
+
+	stack, err := s.adapter.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("running stack action",
+		vlog.String("uuid", id.String()),
+		vlog.String("action", action),
+	)
+
+	results, err := s.containerService.Bulk(ctx, s.members(stack), types.BulkRequest{Action: action})
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if result.Result == types.BulkResultFailed {
+			return results, ErrStackActionFailed
+		}
+	}
+	return results, nil
+}
+
+// Delete deletes all the members of the stack, and the stack. The stack is
+// kept with its remaining members if some of them could not be deleted.
+func (s *StackService) Delete(ctx context.Context, id uuid.UUID, volumes bool) error {
+	stack, err := s.adapter.Get(id)
+	if err != nil {
+		return err
+	}
+
+	results, err := s.containerService.Bulk(ctx, s.members(stack), types.BulkRequest{
+		Action:  types.BulkActionDelete,
+		Volumes: volumes,
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, result := range results {
+		if result.Result == types.BulkResultFailed {
+			errs = append(errs, fmt.Errorf("%s: %s", result.ContainerUUID, result.Error))
+			continue
+		}
+		for name, member := range stack.Members {
+			if member == result.ContainerUUID {
+				delete(stack.Members, name)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		err = s.adapter.Save(stack)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return errors.Join(append([]error{ErrStackActionFailed}, errs...)...)
+	}
+
+	err = s.adapter.Delete(id)
+	if err != nil {
+		return err
+	}
+
+	s.statusesMutex.Lock()
+	delete(s.statuses, id)
+	s.statusesMutex.Unlock()
+
+	log.Info("stack deleted", vlog.String("uuid", id.String()))
+
+	s.ctx.DispatchEvent(types.EventStacksChange{})
+	return nil
+}
+
+// members returns the containers of the stack. The members deleted outside
+// of the stack are ignored.
+func (s *StackService) members(stack types.Stack) []*types.Container {
+	var members []*types.Container
+	for _, m := range stack.Definition.Members {
+		id, ok := stack.Members[m.Name]
+		if !ok {
+			continue
+		}
+		inst, err := s.containerService.Get(id)
+		if err != nil {
+			continue
+		}
+		members = append(members, inst)
+	}
+	return members
+}
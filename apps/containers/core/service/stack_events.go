@@ -0,0 +1,41 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+)
+
+func (s *StackService) GetUUID() uuid.UUID {
+	return s.uuid
+}
+
+func (s *StackService) OnEvent(e interface{}) {
+	switch e := e.(type) {
+	case types.EventContainerStatusChange:
+		if e.Container.Stack == nil {
+			return
+		}
+		s.updateStatus(e.Container.Stack.UUID)
+	}
+}
+
+// updateStatus dispatches EventStackStatusChange if the aggregated status of
+// the stack changed.
+func (s *StackService) updateStatus(id uuid.UUID) {
+	stack, err := s.Get(id)
+	if err != nil {
+		return
+	}
+
+	s.statusesMutex.Lock()
+	changed := s.statuses[id] != stack.Status
+	s.statuses[id] = stack.Status
+	s.statusesMutex.Unlock()
+
+	if changed {
+		s.ctx.DispatchEvent(types.EventStackStatusChange{
+			StackUUID: id,
+			Status:    stack.Status,
+		})
+	}
+}
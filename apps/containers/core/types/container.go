@@ -2,8 +2,16 @@ package types
 
 import (
 	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/config"
+	vtypes "github.com/vertex-center/vertex/core/types"
 )
 
 const (
@@ -19,9 +27,20 @@ const (
 	ContainerInstallMethodDocker = "docker"
 )
 
+const (
+	VersionChannelStable = "stable"
+	VersionChannelBeta   = "beta"
+)
+
+// MaxReplicas is the maximum number of replicas of a container.
+const MaxReplicas = 16
+
 var (
 	ErrContainerNotFound     = errors.New("container not found")
 	ErrContainerStillRunning = errors.New("container still running")
+	ErrInvalidReplicas       = errors.New("invalid number of replicas")
+	ErrReplicaPortsUsed      = errors.New("the ports of the replicas are used by another container")
+	ErrNoFreePort            = errors.New("no free port")
 )
 
 type Container struct {
@@ -32,17 +51,54 @@ type Container struct {
 	Status  string                `json:"status"`
 	Env     ContainerEnvVariables `json:"environment,omitempty"`
 
+	// Health is the health status reported by Docker, if the image has a
+	// health check.
+	Health string `json:"health,omitempty"`
+
 	Update        *ContainerUpdate `json:"update,omitempty"`
 	ServiceUpdate ServiceUpdate    `json:"service_update,omitempty"`
 
+	// ResolvedVersion is the image tag used when the Version
+	// is a constraint like "15.x".
+	ResolvedVersion string `json:"resolved_version,omitempty"`
+
 	CacheVersions []string `json:"cache_versions,omitempty"`
 }
 
-type ContainerSearchQuery struct {
-	Tags     *[]string `json:"tags,omitempty"`
-	Features *[]string `json:"features,omitempty"`
+type DeleteContainerOptions struct {
+	// Volumes deletes the named volumes of the container too.
+	Volumes bool `json:"volumes,omitempty"`
 }
 
+const (
+	ContainerVolumeTypeBind   = "bind"
+	ContainerVolumeTypeVolume = "volume"
+	ContainerVolumeTypeTmpfs  = "tmpfs"
+)
+
+// ContainerVolume is a volume mounted in a container.
+type ContainerVolume struct {
+	Type     string `json:"type"`
+	Name     string `json:"name,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Target   string `json:"target"`
+	ReadOnly bool   `json:"read_only,omitempty"`
+
+	// Size is the disk usage of the volume in bytes, or -1 if it is unknown.
+	Size int64 `json:"size"`
+}
+
+// ContainerInfo is the info of the Docker container and of its image.
+type ContainerInfo struct {
+	// Version is the version of the fields, see vtypes.InfoVersion.
+	Version   int                          `json:"version"`
+	Container vtypes.InfoContainerResponse `json:"container"`
+	Image     vtypes.InfoImageResponse     `json:"image"`
+}
+
+// SecretEnvMask replaces the values of the secret environment variables.
+const SecretEnvMask = "********"
+
 type ContainerUpdate struct {
 	CurrentVersion string `json:"current_version"`
 	LatestVersion  string `json:"latest_version"`
@@ -72,6 +128,118 @@ func (i *Container) DockerContainerName() string {
 	return "VERTEX_CONTAINER_" + i.UUID.String()
 }
 
+// DockerReplicaName is the name of the Docker container of a replica. The
+// first replica keeps the name of the container.
+func (i *Container) DockerReplicaName(replica int) string {
+	if replica == 0 {
+		return i.DockerContainerName()
+	}
+	return fmt.Sprintf("%s_REPLICA_%d", i.DockerContainerName(), replica)
+}
+
+// ParseDockerContainerName returns the UUID of the container from its
+// Docker container name. It returns false if the Docker container is not
+// managed by Vertex.
+func ParseDockerContainerName(name string) (uuid.UUID, bool) {
+	id, _, ok := ParseDockerReplicaName(name)
+	return id, ok
+}
+
+// ParseDockerReplicaName returns the UUID of the container and the replica
+// from the Docker container name of a replica.
+func ParseDockerReplicaName(name string) (uuid.UUID, int, bool) {
+	name, ok := strings.CutPrefix(strings.TrimPrefix(name, "/"), "VERTEX_CONTAINER_")
+	if !ok {
+		return uuid.Nil, 0, false
+	}
+
+	replica := 0
+	name, suffix, found := strings.Cut(name, "_REPLICA_")
+	if found {
+		r, err := strconv.Atoi(suffix)
+		if err != nil || r <= 0 {
+			return uuid.Nil, 0, false
+		}
+		replica = r
+	}
+
+	id, err := uuid.Parse(name)
+	if err != nil {
+		return uuid.Nil, 0, false
+	}
+	return id, replica, true
+}
+
+// DockerNetworkName is the name of the private network shared by the
+// container and its databases.
+func (i *Container) DockerNetworkName() string {
+	return "vertex_network_" + i.UUID.String()
+}
+
+// DockerStackNetworkName is the name of the network shared by the members
+// of the stack of the container. It is empty if the container is not a
+// member of a stack.
+func (i *Container) DockerStackNetworkName() string {
+	if i.Stack == nil {
+		return ""
+	}
+	return "vertex_stack_" + i.Stack.UUID.String()
+}
+
+// DockerVolumeName is the name of a named volume of the container.
+func (i *Container) DockerVolumeName(name string) string {
+	return "vertex_volume_" + i.UUID.String() + "_" + name
+}
+
+// DockerNetworkAlias is the host name of the container in the private
+// networks. Unlike the container name, it is a valid host name.
+func (i *Container) DockerNetworkAlias() string {
+	return "vertex-" + i.UUID.String()
+}
+
+// InternalPort returns the port listened inside the container for the
+// given port environment variable, which is the port to use from the
+// private networks. It falls back to the published port.
+func (i *Container) InternalPort(envName string) string {
+	if i.Service.Methods.Docker == nil || i.Service.Methods.Docker.Ports == nil {
+		return i.Env[envName]
+	}
+
+	for _, e := range i.Service.Env {
+		if e.Name != envName || e.Type != "port" {
+			continue
+		}
+		for in, out := range *i.Service.Methods.Docker.Ports {
+			if out == e.Default {
+				return in
+			}
+		}
+	}
+	return i.Env[envName]
+}
+
+// MaskSecretEnv returns the environment variables, formatted as
+// "NAME=value", with the values of the secret variables of the service
+// masked.
+func (i *Container) MaskSecretEnv(env []string) []string {
+	secrets := map[string]bool{}
+	for _, e := range i.Service.Env {
+		if e.Secret != nil && *e.Secret {
+			secrets[e.Name] = true
+		}
+	}
+
+	masked := make([]string, len(env))
+	for j, v := range env {
+		name, _, _ := strings.Cut(v, "=")
+		if secrets[name] {
+			v = name + "=" + SecretEnvMask
+		}
+		masked[j] = v
+	}
+	return masked
+}
+
 func (i *Container) IsRunning() bool {
 	return i.Status != ContainerStatusOff && i.Status != ContainerStatusError
 }
@@ -88,6 +256,84 @@ func (i *Container) LaunchOnStartup() bool {
 	return true
 }
 
+// GetReplicas returns the number of Docker containers to run for this
+// container. It is at least 1.
+func (i *Container) GetReplicas() int {
+	if i.ContainerSettings.Replicas == nil || *i.ContainerSettings.Replicas < 1 {
+		return 1
+	}
+	return *i.ContainerSettings.Replicas
+}
+
+// ReplicaPort returns the port published on the host by a replica. Each
+// replica publishes the ports of the container, offset by its index.
+func ReplicaPort(port string, replica int) string {
+	p, err := strconv.Atoi(port)
+	if err != nil || replica == 0 {
+		return port
+	}
+	return strconv.Itoa(p + replica)
+}
+
+// PublishesPort returns true if the container publishes the port on the
+// host, through one of its port environment variables.
+func (i *Container) PublishesPort(port string) bool {
+	for _, e := range i.Service.Env {
+		if e.Type == "port" && i.Env[e.Name] == port {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLocalHost returns true if the host is the machine running the
+// containers, where they publish their ports.
+func IsLocalHost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == config.Current.Host
+}
+
+// ReplicaTargets returns one URL for each replica of the container, if the
+// target is a port published by the container on the local host. Otherwise,
+// it returns nil.
+func (i *Container) ReplicaTargets(target *url.URL) []*url.URL {
+	host, p := target.Hostname(), target.Port()
+	if p == "" || !IsLocalHost(host) || !i.PublishesPort(p) {
+		return nil
+	}
+
+	replicas := i.GetReplicas()
+	targets := make([]*url.URL, 0, replicas)
+	for replica := 0; replica < replicas; replica++ {
+		u := *target
+		u.Host = net.JoinHostPort(host, ReplicaPort(p, replica))
+		targets = append(targets, &u)
+	}
+	return targets
+}
+
+// IsInScheduleWindow returns true if the container can run at the time t
+// according to its schedule. Containers without schedule are always in
+// their window.
+func (i *Container) IsInScheduleWindow(t time.Time) bool {
+	if i.Schedule == nil {
+		return true
+	}
+	return i.Schedule.InWindow(t)
+}
+
+// GetHooks returns the hooks of a step of the lifecycle, starting with the
+// hooks of the service.
+func (i *Container) GetHooks(step string) []Hook {
+	var hooks []Hook
+	if i.Service.Hooks != nil {
+		hooks = append(hooks, i.Service.Hooks.Get(step)...)
+	}
+	if i.ContainerSettings.Hooks != nil {
+		hooks = append(hooks, i.ContainerSettings.Hooks.Get(step)...)
+	}
+	return hooks
+}
+
 func (i *Container) ResetDefaultEnv() {
 	i.Env = ContainerEnvVariables{}
 	for _, env := range i.Service.Env {
@@ -132,8 +378,32 @@ func (i *Container) GetVersion() string {
 	return *i.ContainerSettings.Version
 }
 
+// GetImageTag returns the image tag to run. This is the resolved version
+// if the version is a constraint, or the version itself otherwise.
+func (i *Container) GetImageTag() string {
+	if i.ResolvedVersion != "" {
+		return i.ResolvedVersion
+	}
+	return i.GetVersion()
+}
+
+// GetVersionChannel returns the channel used to list versions. If the container
+// doesn't specify a channel, the stable channel is used if the service declares it.
+func (i *Container) GetVersionChannel() string {
+	if i.ContainerSettings.VersionChannel != nil {
+		return *i.ContainerSettings.VersionChannel
+	}
+	docker := i.Service.Methods.Docker
+	if docker != nil && docker.Versions != nil {
+		if _, ok := docker.Versions.Channels[VersionChannelStable]; ok {
+			return VersionChannelStable
+		}
+	}
+	return ""
+}
+
 func (i *Container) GetImageNameWithTag() string {
-	return *i.Service.Methods.Docker.Image + ":" + i.GetVersion()
+	return *i.Service.Methods.Docker.Image + ":" + i.GetImageTag()
 }
 
 func (i *Container) HasTag(tag string) bool {
@@ -159,3 +429,22 @@ func (i *Container) HasTagIn(tags []string) bool {
 	}
 	return false
 }
+
+func (i *Container) HasStatusIn(statuses []string) bool {
+	return contains(statuses, i.Status)
+}
+
+func (i *Container) HasServiceIn(serviceIDs []string) bool {
+	return contains(serviceIDs, i.Service.ID)
+}
+
+func (i *Container) HasDatabaseIn(databases []uuid.UUID) bool {
+	for _, db := range i.Databases {
+		for _, d := range databases {
+			if db == d {
+				return true
+			}
+		}
+	}
+	return false
+}
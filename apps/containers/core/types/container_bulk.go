@@ -0,0 +1,77 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	vtypes "github.com/vertex-center/vertex/core/types"
+)
+
+const (
+	BulkActionStart              = "start"
+	BulkActionStop               = "stop"
+	BulkActionRestart            = "restart"
+	BulkActionRecreate           = "recreate"
+	BulkActionUpdate             = "update"
+	BulkActionDelete             = "delete"
+	BulkActionSetLaunchOnStartup = "set-launch-on-startup"
+)
+
+var ErrBulkActionInvalid = errors.New("invalid bulk action")
+
+// BulkRequest applies an action to all the containers matching a query.
+type BulkRequest struct {
+	Query  ContainerSearchQuery `json:"query"`
+	Action string               `json:"action"`
+
+	// LaunchOnStartup is the value set by the set-launch-on-startup action.
+	LaunchOnStartup *bool `json:"launch_on_startup,omitempty"`
+
+	// Volumes deletes the named volumes of the containers with the delete
+	// action.
+	Volumes bool `json:"volumes,omitempty"`
+}
+
+// Validate returns ErrBulkActionInvalid if the action is unknown or misses
+// its parameters.
+func (r BulkRequest) Validate() error {
+	switch r.Action {
+	case BulkActionStart, BulkActionStop, BulkActionRestart, BulkActionRecreate, BulkActionUpdate, BulkActionDelete:
+		return nil
+	case BulkActionSetLaunchOnStartup:
+		if r.LaunchOnStartup == nil {
+			return fmt.Errorf("%w: launch_on_startup is required", ErrBulkActionInvalid)
+		}
+		return nil
+	}
+	return ErrBulkActionInvalid
+}
+
+// Permission returns the permission required by the action.
+func (r BulkRequest) Permission() vtypes.Permission {
+	switch r.Action {
+	case BulkActionStart, BulkActionStop, BulkActionRestart, BulkActionRecreate:
+		return vtypes.PermissionContainersStart
+	}
+	return vtypes.PermissionContainersWrite
+}
+
+// Reversed returns true if the action must be applied to the containers
+// before their dependencies, like stopping a container before its database.
+func (r BulkRequest) Reversed() bool {
+	return r.Action == BulkActionStop || r.Action == BulkActionDelete
+}
+
+const (
+	BulkResultSuccess = "success"
+	BulkResultSkipped = "skipped"
+	BulkResultFailed  = "failed"
+)
+
+// BulkResult is the result of a bulk action for one container.
+type BulkResult struct {
+	ContainerUUID uuid.UUID `json:"container_uuid"`
+	Result        string    `json:"result"`
+	Error         string    `json:"error,omitempty"`
+}
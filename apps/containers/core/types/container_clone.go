@@ -0,0 +1,56 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	CloneDatabasesKeep  = "keep"
+	CloneDatabasesClone = "clone"
+)
+
+var ErrInvalidCloneOptions = errors.New("invalid clone options")
+
+// CloneContainerOptions describes how a container is cloned. The clone
+// always gets the service, the settings and the environment of the
+// container, with new ports.
+type CloneContainerOptions struct {
+	// DisplayName is the name of the clone. The name of the container
+	// followed by "(copy)" is used if nil.
+	DisplayName *string `json:"display_name,omitempty"`
+
+	// Tags are the tags of the clone. The tags of the container are kept
+	// if nil.
+	Tags []string `json:"tags,omitempty"`
+
+	// RegenerateSecrets replaces the values of the secret environment
+	// variables with random values. The secrets of the cloned databases
+	// are kept if their volumes are copied, as their data still uses them.
+	RegenerateSecrets bool `json:"regenerate_secrets,omitempty"`
+
+	// Volumes copies the content of the volumes of the container.
+	Volumes bool `json:"volumes,omitempty"`
+
+	// Databases is "keep" to use the same databases as the container, the
+	// default, or "clone" to clone them too.
+	Databases string `json:"databases,omitempty"`
+}
+
+// Validate returns ErrInvalidCloneOptions if the options are invalid.
+func (o CloneContainerOptions) Validate() error {
+	switch o.Databases {
+	case "", CloneDatabasesKeep, CloneDatabasesClone:
+	default:
+		return fmt.Errorf("%w: unknown databases '%s'", ErrInvalidCloneOptions, o.Databases)
+	}
+	if o.DisplayName != nil && *o.DisplayName == "" {
+		return fmt.Errorf("%w: the display name can't be empty", ErrInvalidCloneOptions)
+	}
+	return nil
+}
+
+// CloneDatabases returns true if the databases must be cloned too.
+func (o CloneContainerOptions) CloneDatabases() bool {
+	return o.Databases == CloneDatabasesClone
+}
@@ -0,0 +1,181 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	HookPreStart  = "pre_start"
+	HookPostStart = "post_start"
+	HookPreStop   = "pre_stop"
+	HookPostStop  = "post_stop"
+)
+
+const (
+	HookOnFailureAbort    = "abort"
+	HookOnFailureContinue = "continue"
+)
+
+var (
+	ErrInvalidHook = errors.New("invalid hook")
+	ErrHookFailed  = errors.New("the hook failed")
+)
+
+// Hooks are the actions run around the start and the stop of a container.
+// The stop hooks are only run when the container is stopped by Vertex.
+type Hooks struct {
+	// PreStart hooks run before the container is started. They can't use
+	// Exec or HTTP, as the container is not running yet.
+	PreStart []Hook `yaml:"pre_start,omitempty" json:"pre_start,omitempty"`
+
+	// PostStart hooks run once the container is running.
+	PostStart []Hook `yaml:"post_start,omitempty" json:"post_start,omitempty"`
+
+	// PreStop hooks run before the container is stopped.
+	PreStop []Hook `yaml:"pre_stop,omitempty" json:"pre_stop,omitempty"`
+
+	// PostStop hooks run after the container is stopped. They can't use
+	// Exec or HTTP, as the container is not running anymore.
+	PostStop []Hook `yaml:"post_stop,omitempty" json:"post_stop,omitempty"`
+}
+
+// Hook is an action run at a step of the lifecycle of a container. Exactly
+// one of Exec, Container and HTTP must be set.
+type Hook struct {
+	// Name is displayed in the logs of the container.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	// Exec runs a command inside the container.
+	Exec *HookExec `yaml:"exec,omitempty" json:"exec,omitempty"`
+
+	// Container runs a one-shot helper container, in the network of the
+	// container and with its environment.
+	Container *HookContainer `yaml:"container,omitempty" json:"container,omitempty"`
+
+	// HTTP sends a request to the container.
+	HTTP *HookHTTP `yaml:"http,omitempty" json:"http,omitempty"`
+
+	// Timeout is the number of seconds the hook can run. The default
+	// hook timeout is used if nil.
+	Timeout *int `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+
+	// OnFailure is what to do when the hook fails. It can be "abort",
+	// the default, or "continue".
+	OnFailure *string `yaml:"on_failure,omitempty" json:"on_failure,omitempty"`
+}
+
+type HookExec struct {
+	Cmd []string `yaml:"cmd" json:"cmd"`
+}
+
+type HookContainer struct {
+	// Image is the image of the helper container. The image of the
+	// container is used if empty.
+	Image string   `yaml:"image,omitempty" json:"image,omitempty"`
+	Cmd   []string `yaml:"cmd,omitempty" json:"cmd,omitempty"`
+}
+
+type HookHTTP struct {
+	// Method is the HTTP method, GET by default.
+	Method string `yaml:"method,omitempty" json:"method,omitempty"`
+
+	// Port is the name of the environment variable that contains the
+	// port of the container to call.
+	Port string `yaml:"port" json:"port"`
+
+	// Path is the path of the request, like "/cache/flush".
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+}
+
+// Get returns the hooks of a step of the lifecycle.
+func (h Hooks) Get(step string) []Hook {
+	switch step {
+	case HookPreStart:
+		return h.PreStart
+	case HookPostStart:
+		return h.PostStart
+	case HookPreStop:
+		return h.PreStop
+	case HookPostStop:
+		return h.PostStop
+	}
+	return nil
+}
+
+// Validate returns ErrInvalidHook if a hook is invalid, or can't run at its
+// step of the lifecycle.
+func (h Hooks) Validate() error {
+	for _, step := range []string{HookPreStart, HookPostStart, HookPreStop, HookPostStop} {
+		for _, hook := range h.Get(step) {
+			err := hook.Validate()
+			if err != nil {
+				return fmt.Errorf("%s: %w", step, err)
+			}
+			running := step == HookPostStart || step == HookPreStop
+			if !running && (hook.Exec != nil || hook.HTTP != nil) {
+				return fmt.Errorf("%w: %s hooks can only run helper containers", ErrInvalidHook, step)
+			}
+		}
+	}
+	return nil
+}
+
+func (h Hook) Validate() error {
+	count := 0
+	if h.Exec != nil {
+		count++
+		if len(h.Exec.Cmd) == 0 {
+			return fmt.Errorf("%w: exec requires a command", ErrInvalidHook)
+		}
+	}
+	if h.Container != nil {
+		count++
+	}
+	if h.HTTP != nil {
+		count++
+		if h.HTTP.Port == "" {
+			return fmt.Errorf("%w: http requires a port", ErrInvalidHook)
+		}
+	}
+	if count != 1 {
+		return fmt.Errorf("%w: exactly one of exec, container and http must be set", ErrInvalidHook)
+	}
+	if h.Timeout != nil && *h.Timeout <= 0 {
+		return fmt.Errorf("%w: the timeout must be positive", ErrInvalidHook)
+	}
+	if h.OnFailure != nil && *h.OnFailure != HookOnFailureAbort && *h.OnFailure != HookOnFailureContinue {
+		return fmt.Errorf("%w: unknown on_failure '%s'", ErrInvalidHook, *h.OnFailure)
+	}
+	return nil
+}
+
+// DisplayName returns the name of the hook, or its kind if it has no name.
+func (h Hook) DisplayName() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	switch {
+	case h.Exec != nil:
+		return "exec"
+	case h.Container != nil:
+		return "container"
+	case h.HTTP != nil:
+		return "http"
+	}
+	return "hook"
+}
+
+// GetTimeout returns the timeout of the hook, or def if it has none.
+func (h Hook) GetTimeout(def time.Duration) time.Duration {
+	if h.Timeout == nil {
+		return def
+	}
+	return time.Duration(*h.Timeout) * time.Second
+}
+
+// Aborts returns true if a failure of the hook aborts the lifecycle step.
+func (h Hook) Aborts() bool {
+	return h.OnFailure == nil || *h.OnFailure == HookOnFailureAbort
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HooksTestSuite struct {
+	suite.Suite
+}
+
+func TestHooksTestSuite(t *testing.T) {
+	suite.Run(t, new(HooksTestSuite))
+}
+
+func (suite *HooksTestSuite) TestValidate() {
+	exec := Hook{Exec: &HookExec{Cmd: []string{"migrate"}}}
+	helper := Hook{Container: &HookContainer{Cmd: []string{"migrate"}}}
+	unknown := "retry"
+
+	suite.NoError(Hooks{PreStart: []Hook{helper}, PreStop: []Hook{exec}}.Validate())
+	suite.ErrorIs(Hooks{PreStart: []Hook{exec}}.Validate(), ErrInvalidHook)
+	suite.ErrorIs(Hooks{PostStop: []Hook{{HTTP: &HookHTTP{Port: "PORT"}}}}.Validate(), ErrInvalidHook)
+	suite.ErrorIs(Hooks{PostStart: []Hook{{}}}.Validate(), ErrInvalidHook)
+	suite.ErrorIs(Hooks{PostStart: []Hook{{Exec: exec.Exec, Container: helper.Container}}}.Validate(), ErrInvalidHook)
+	suite.ErrorIs(Hooks{PostStart: []Hook{{Exec: &HookExec{}}}}.Validate(), ErrInvalidHook)
+	suite.ErrorIs(Hooks{PostStart: []Hook{{Exec: exec.Exec, OnFailure: &unknown}}}.Validate(), ErrInvalidHook)
+}
+
+func (suite *HooksTestSuite) TestGetHooks() {
+	serviceHook := Hook{Name: "service", Container: &HookContainer{}}
+	settingsHook := Hook{Name: "settings", Container: &HookContainer{}}
+
+	inst := Container{
+		Service: Service{
+			Hooks: &Hooks{PreStart: []Hook{serviceHook}},
+		},
+		ContainerSettings: ContainerSettings{
+			Hooks: &Hooks{PreStart: []Hook{settingsHook}},
+		},
+	}
+
+	suite.Equal([]Hook{serviceHook, settingsHook}, inst.GetHooks(HookPreStart))
+	suite.Empty(inst.GetHooks(HookPostStop))
+}
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/vertex-center/vertex/pkg/log"
 )
@@ -15,14 +18,137 @@ const (
 	LogKindDownloads = "downloads"
 	LogKindVertexOut = "vertex_out"
 	LogKindVertexErr = "vertex_err"
+	LogKindHook      = "hook"
+	LogKindHookErr   = "hook_err"
+)
+
+const (
+	LogFormatJSON   = "json"
+	LogFormatLogfmt = "logfmt"
+)
+
+const (
+	LogLevelTrace = "trace"
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+	LogLevelFatal = "fatal"
+)
+
+const (
+	LogStreamStdout = "stdout"
+	LogStreamStderr = "stderr"
+	LogStreamVertex = "vertex"
+)
+
+const (
+	LogQueryDefaultLimit = 100
+	LogQueryMaxLimit     = 1000
 )
 
 var ErrBufferEmpty = errors.New("the buffer is empty")
 
 type LogLine struct {
 	Id      int            `json:"id"`
+	Time    time.Time      `json:"time"`
 	Kind    string         `json:"kind"`
 	Message LogLineMessage `json:"message"`
+
+	// Replica is the replica that wrote the line.
+	Replica int `json:"replica,omitempty"`
+}
+
+// LogRecord is a log line as it is persisted on disk.
+type LogRecord struct {
+	Id      int       `json:"id"`
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"`
+	Stream  string    `json:"stream"`
+	Message string    `json:"message"`
+	Replica int       `json:"replica,omitempty"`
+
+	// Level and Fields are only set for structured log lines.
+	Level  string         `json:"level,omitempty"`
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+func NewLogRecord(line LogLine) LogRecord {
+	r := LogRecord{
+		Id:      line.Id,
+		Time:    line.Time,
+		Kind:    line.Kind,
+		Replica: line.Replica,
+	}
+	if line.Message != nil {
+		r.Message = line.Message.String()
+	}
+	if m, ok := line.Message.(*LogLineMessageStructured); ok {
+		r.Level = m.Level
+		r.Fields = m.Fields
+	}
+	switch line.Kind {
+	case LogKindOut:
+		r.Stream = LogStreamStdout
+	case LogKindErr:
+		r.Stream = LogStreamStderr
+	default:
+		r.Stream = LogStreamVertex
+	}
+	return r
+}
+
+// LogQuery filters the persisted logs of a container. The results are
+// returned from the newest to the oldest record.
+type LogQuery struct {
+	Since *time.Time
+	Until *time.Time
+
+	// Query is a case-insensitive text that the message must contain.
+	Query string
+
+	// Kinds are the accepted kinds. All kinds are accepted if empty.
+	Kinds []string
+
+	// Levels are the accepted levels. All levels are accepted if empty.
+	// Lines without level never match if Levels is not empty.
+	Levels []string
+
+	Limit int
+
+	// Cursor is the id of the last record of the previous page. Only the
+	// records older than the cursor are returned.
+	Cursor *int
+}
+
+func (q LogQuery) Match(r LogRecord) bool {
+	if q.Cursor != nil && r.Id >= *q.Cursor {
+		return false
+	}
+	if q.Since != nil && r.Time.Before(*q.Since) {
+		return false
+	}
+	if q.Until != nil && r.Time.After(*q.Until) {
+		return false
+	}
+	if len(q.Kinds) > 0 && !contains(q.Kinds, r.Kind) {
+		return false
+	}
+	if len(q.Levels) > 0 && !contains(q.Levels, r.Level) {
+		return false
+	}
+	if q.Query != "" && !strings.Contains(strings.ToLower(r.Message), strings.ToLower(q.Query)) {
+		return false
+	}
+	return true
+}
+
+type LogPage struct {
+	Records []LogRecord `json:"records"`
+
+	// NextCursor is the cursor to use to get the next page, or nil if
+	// there are no more records.
+	NextCursor *int `json:"next_cursor,omitempty"`
 }
 
 type LogLineMessage interface {
@@ -43,6 +169,201 @@ func (m *LogLineMessageString) String() string {
 	return m.Value
 }
 
+// LogLineMessageStructured is a log line written by the service in a
+// structured format, like JSON or logfmt.
+type LogLineMessageStructured struct {
+	// Value is the raw line.
+	Value   string         `json:"value"`
+	Level   string         `json:"level,omitempty"`
+	Message string         `json:"message,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+func (m *LogLineMessageStructured) String() string {
+	return m.Value
+}
+
+// ParseLogLineMessage parses a line written by a service. Lines that are
+// JSON objects, or that match the given format, are returned as
+// LogLineMessageStructured. Other lines are returned as LogLineMessageString.
+func ParseLogLineMessage(line string, format string) LogLineMessage {
+	var fields map[string]any
+	if strings.HasPrefix(strings.TrimSpace(line), "{") {
+		err := json.Unmarshal([]byte(line), &fields)
+		if err != nil {
+			fields = nil
+		}
+	}
+	if fields == nil && format == LogFormatLogfmt {
+		fields = parseLogfmt(line)
+	}
+	if fields == nil {
+		return NewLogLineMessageString(line)
+	}
+
+	m := &LogLineMessageStructured{
+		Value: line,
+	}
+	for _, key := range []string{"level", "lvl", "severity"} {
+		if v, ok := fields[key].(string); ok {
+			m.Level = normalizeLogLevel(v)
+			delete(fields, key)
+			break
+		}
+	}
+	for _, key := range []string{"msg", "message"} {
+		if v, ok := fields[key].(string); ok {
+			m.Message = v
+			delete(fields, key)
+			break
+		}
+	}
+	if len(fields) > 0 {
+		m.Fields = fields
+	}
+	return m
+}
+
+// logReplicaPrefix is written before the log lines of the replicas, except
+// the first one, to multiplex their logs.
+const logReplicaPrefix = "REPLICA "
+
+// FormatLogReplica adds the replica label to a log line.
+func FormatLogReplica(replica int, line string) string {
+	if replica == 0 {
+		return line
+	}
+	return fmt.Sprintf("%s%d %s", logReplicaPrefix, replica, line)
+}
+
+// ParseLogReplica splits the replica label added by FormatLogReplica. The
+// replica is 0 if the line has no label.
+func ParseLogReplica(line string) (int, string) {
+	rest, ok := strings.CutPrefix(line, logReplicaPrefix)
+	if !ok {
+		return 0, line
+	}
+	before, after, found := strings.Cut(rest, " ")
+	if !found {
+		return 0, line
+	}
+	replica, err := strconv.Atoi(before)
+	if err != nil {
+		return 0, line
+	}
+	return replica, after
+}
+
+// ParseLogTimestamp splits the timestamp added by Docker at the start of
+// a log line. If the line has no timestamp, the returned time is zero.
+func ParseLogTimestamp(line string) (time.Time, string) {
+	before, after, found := strings.Cut(line, " ")
+	if !found {
+		return time.Time{}, line
+	}
+	t, err := time.Parse(time.RFC3339Nano, before)
+	if err != nil {
+		return time.Time{}, line
+	}
+	return t, after
+}
+
+func normalizeLogLevel(level string) string {
+	switch strings.ToLower(level) {
+	case "trace":
+		return LogLevelTrace
+	case "debug", "dbug":
+		return LogLevelDebug
+	case "info", "information", "notice":
+		return LogLevelInfo
+	case "warn", "warning":
+		return LogLevelWarn
+	case "error", "err", "eror":
+		return LogLevelError
+	case "fatal", "panic", "critical", "crit":
+		return LogLevelFatal
+	default:
+		return strings.ToLower(level)
+	}
+}
+
+// parseLogfmt parses a logfmt line, like `level=info msg="hello world"`.
+// It returns nil if the line is not a valid logfmt line.
+func parseLogfmt(line string) map[string]any {
+	fields := map[string]any{}
+	pairs := 0
+
+	i := 0
+	for i < len(line) {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		if i == len(line) {
+			break
+		}
+
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' && line[i] != '"' {
+			i++
+		}
+		key := line[start:i]
+		if key == "" {
+			return nil
+		}
+
+		if i == len(line) || line[i] == ' ' {
+			fields[key] = true
+			continue
+		}
+		if line[i] == '"' {
+			return nil
+		}
+
+		// Skip the '='
+		i++
+
+		if i < len(line) && line[i] == '"' {
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil
+			}
+			value, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				return nil
+			}
+			fields[key] = value
+			i = end + 1
+		} else {
+			start = i
+			for i < len(line) && line[i] != ' ' {
+				i++
+			}
+			fields[key] = line[start:i]
+		}
+		pairs++
+	}
+
+	if pairs == 0 {
+		return nil
+	}
+	return fields
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 type LogLineMessageDownload struct {
 	*DownloadProgress
 }
@@ -0,0 +1,81 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ContainerLogsTestSuite struct {
+	suite.Suite
+}
+
+func TestContainerLogsTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerLogsTestSuite))
+}
+
+func (suite *ContainerLogsTestSuite) TestParseLogTimestamp() {
+	t, line := ParseLogTimestamp("2023-10-15T09:30:12.123456789Z server started")
+	suite.Equal(time.Date(2023, 10, 15, 9, 30, 12, 123456789, time.UTC), t)
+	suite.Equal("server started", line)
+
+	t, line = ParseLogTimestamp("server started")
+	suite.True(t.IsZero())
+	suite.Equal("server started", line)
+}
+
+func (suite *ContainerLogsTestSuite) TestParseLogReplica() {
+	replica, line := ParseLogReplica(FormatLogReplica(2, "server started"))
+	suite.Equal(2, replica)
+	suite.Equal("server started", line)
+
+	replica, line = ParseLogReplica(FormatLogReplica(0, "server started"))
+	suite.Equal(0, replica)
+	suite.Equal("server started", line)
+
+	replica, line = ParseLogReplica("REPLICA server started")
+	suite.Equal(0, replica)
+	suite.Equal("REPLICA server started", line)
+}
+
+func (suite *ContainerLogsTestSuite) TestParseLogLineMessageJSON() {
+	m := ParseLogLineMessage(`{"level":"WARNING","msg":"disk almost full","usage":0.93}`, "")
+
+	structured, ok := m.(*LogLineMessageStructured)
+	suite.Require().True(ok)
+	suite.Equal(LogLevelWarn, structured.Level)
+	suite.Equal("disk almost full", structured.Message)
+	suite.Equal(map[string]any{"usage": 0.93}, structured.Fields)
+	suite.Equal(`{"level":"WARNING","msg":"disk almost full","usage":0.93}`, m.String())
+}
+
+func (suite *ContainerLogsTestSuite) TestParseLogLineMessageLogfmt() {
+	line := `time=2023-10-15 level=error msg="connection refused" retry`
+
+	m := ParseLogLineMessage(line, LogFormatLogfmt)
+
+	structured, ok := m.(*LogLineMessageStructured)
+	suite.Require().True(ok)
+	suite.Equal(LogLevelError, structured.Level)
+	suite.Equal("connection refused", structured.Message)
+	suite.Equal(map[string]any{"time": "2023-10-15", "retry": true}, structured.Fields)
+
+	// Without the logfmt format, the line is kept as is.
+	_, ok = ParseLogLineMessage(line, "").(*LogLineMessageString)
+	suite.True(ok)
+
+	// Plain text is never structured.
+	_, ok = ParseLogLineMessage("Server listening on port 80", LogFormatLogfmt).(*LogLineMessageString)
+	suite.True(ok)
+	_, ok = ParseLogLineMessage(`msg="unterminated`, LogFormatLogfmt).(*LogLineMessageString)
+	suite.True(ok)
+}
+
+func (suite *ContainerLogsTestSuite) TestLogQueryMatchLevels() {
+	query := LogQuery{Levels: []string{LogLevelError}}
+
+	suite.True(query.Match(LogRecord{Level: LogLevelError}))
+	suite.False(query.Match(LogRecord{Level: LogLevelInfo}))
+	suite.False(query.Match(LogRecord{}))
+}
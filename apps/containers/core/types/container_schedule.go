@@ -0,0 +1,82 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	ScheduleActionStart = "start"
+	ScheduleActionStop  = "stop"
+	ScheduleActionRun   = "run"
+)
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// ContainerSchedule restricts when a container runs. The expressions use
+// the standard cron format, in the local time of the server.
+type ContainerSchedule struct {
+	// Start is the cron expression that starts the container.
+	Start *string `json:"start,omitempty" yaml:"start,omitempty"`
+
+	// Stop is the cron expression that stops the container.
+	Stop *string `json:"stop,omitempty" yaml:"stop,omitempty"`
+
+	// Run is the cron expression that runs the container as a job. The job
+	// is done when the container exits by itself.
+	Run *string `json:"run,omitempty" yaml:"run,omitempty"`
+}
+
+// IsEmpty returns true if the schedule has no expression.
+func (s ContainerSchedule) IsEmpty() bool {
+	return s.Start == nil && s.Stop == nil && s.Run == nil
+}
+
+// IsJob returns true if the container is run as a job.
+func (s ContainerSchedule) IsJob() bool {
+	return s.Run != nil
+}
+
+// Validate returns ErrInvalidSchedule if an expression is invalid, or if
+// the schedule mixes a window with a job.
+func (s ContainerSchedule) Validate() error {
+	if s.Run != nil && (s.Start != nil || s.Stop != nil) {
+		return fmt.Errorf("%w: a job can't have start or stop expressions", ErrInvalidSchedule)
+	}
+	for _, expr := range []*string{s.Start, s.Stop, s.Run} {
+		if expr == nil {
+			continue
+		}
+		_, err := cron.ParseStandard(*expr)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidSchedule, err.Error())
+		}
+	}
+	return nil
+}
+
+// InWindow returns true if the container should be running at the time t,
+// which is when the next stop comes before the next start. A window with
+// only a start or a stop is always open. Jobs are never in their window,
+// because they only run when triggered.
+func (s ContainerSchedule) InWindow(t time.Time) bool {
+	if s.IsJob() {
+		return false
+	}
+	if s.Start == nil || s.Stop == nil {
+		return true
+	}
+
+	start, err := cron.ParseStandard(*s.Start)
+	if err != nil {
+		return true
+	}
+	stop, err := cron.ParseStandard(*s.Stop)
+	if err != nil {
+		return true
+	}
+	return stop.Next(t).Before(start.Next(t))
+}
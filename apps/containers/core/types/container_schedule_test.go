@@ -0,0 +1,41 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ContainerScheduleTestSuite struct {
+	suite.Suite
+}
+
+func TestContainerScheduleTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerScheduleTestSuite))
+}
+
+func (suite *ContainerScheduleTestSuite) TestValidate() {
+	start := "0 8 * * *"
+	stop := "0 20 * * *"
+	invalid := "every day"
+
+	suite.NoError(ContainerSchedule{}.Validate())
+	suite.NoError(ContainerSchedule{Start: &start, Stop: &stop}.Validate())
+	suite.NoError(ContainerSchedule{Run: &start}.Validate())
+	suite.ErrorIs(ContainerSchedule{Start: &invalid}.Validate(), ErrInvalidSchedule)
+	suite.ErrorIs(ContainerSchedule{Start: &start, Run: &stop}.Validate(), ErrInvalidSchedule)
+}
+
+func (suite *ContainerScheduleTestSuite) TestInWindow() {
+	start := "0 8 * * *"
+	stop := "0 20 * * *"
+	schedule := ContainerSchedule{Start: &start, Stop: &stop}
+
+	suite.True(schedule.InWindow(time.Date(2023, 10, 1, 12, 0, 0, 0, time.Local)))
+	suite.False(schedule.InWindow(time.Date(2023, 10, 1, 22, 0, 0, 0, time.Local)))
+	suite.False(schedule.InWindow(time.Date(2023, 10, 1, 6, 0, 0, 0, time.Local)))
+
+	suite.True(ContainerSchedule{Start: &start}.InWindow(time.Now()))
+	suite.False(ContainerSchedule{Run: &start}.InWindow(time.Now()))
+}
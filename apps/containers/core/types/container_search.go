@@ -0,0 +1,167 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ContainerSortName    = "name"
+	ContainerSortService = "service"
+	ContainerSortStatus  = "status"
+)
+
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+type ContainerSearchQuery struct {
+	// Text is a case-insensitive text that the display name or the service
+	// name must contain, or match fuzzily.
+	Text *string `json:"text,omitempty"`
+
+	Tags           *[]string `json:"tags,omitempty"`
+	Features       *[]string `json:"features,omitempty"`
+	Statuses       *[]string `json:"statuses,omitempty"`
+	ServiceIDs     *[]string `json:"service_ids,omitempty"`
+	InstallMethods *[]string `json:"install_methods,omitempty"`
+
+	// UpdateAvailable filters the containers with, or without, an update.
+	UpdateAvailable *bool `json:"update_available,omitempty"`
+
+	// Databases are the UUIDs of the database containers that the
+	// containers must be linked to.
+	Databases *[]uuid.UUID `json:"databases,omitempty"`
+
+	// Sort is the field used to sort the containers. The containers are
+	// sorted by name by default.
+	Sort  string `json:"sort,omitempty"`
+	Order string `json:"order,omitempty"`
+
+	// Limit is the maximum number of containers returned. All the
+	// containers are returned if zero.
+	Limit  int `json:"limit,omitempty"`
+	Offset int `json:"offset,omitempty"`
+
+	// Scope are the tags the user is limited to, if any.
+	Scope *[]string `json:"-"`
+}
+
+// Validate returns ErrInvalidSearchQuery if the sort, the order or the
+// pagination is invalid.
+func (q ContainerSearchQuery) Validate() error {
+	switch q.Sort {
+	case "", ContainerSortName, ContainerSortService, ContainerSortStatus:
+	default:
+		return fmt.Errorf("%w: unknown sort '%s'", ErrInvalidSearchQuery, q.Sort)
+	}
+	switch q.Order {
+	case "", SortOrderAsc, SortOrderDesc:
+	default:
+		return fmt.Errorf("%w: unknown order '%s'", ErrInvalidSearchQuery, q.Order)
+	}
+	if q.Limit < 0 || q.Offset < 0 {
+		return fmt.Errorf("%w: limit and offset must be positive", ErrInvalidSearchQuery)
+	}
+	return nil
+}
+
+// Match returns true if the container matches all the filters of the query.
+func (q ContainerSearchQuery) Match(inst *Container) bool {
+	if q.Scope != nil && !inst.HasTagIn(*q.Scope) {
+		return false
+	}
+	if q.Features != nil && !inst.HasFeatureIn(*q.Features) {
+		return false
+	}
+	if q.Tags != nil && !inst.HasTagIn(*q.Tags) {
+		return false
+	}
+	if q.Statuses != nil && !inst.HasStatusIn(*q.Statuses) {
+		return false
+	}
+	if q.ServiceIDs != nil && !inst.HasServiceIn(*q.ServiceIDs) {
+		return false
+	}
+	if q.InstallMethods != nil {
+		if inst.InstallMethod == nil || !contains(*q.InstallMethods, *inst.InstallMethod) {
+			return false
+		}
+	}
+	if q.UpdateAvailable != nil && (inst.Update != nil) != *q.UpdateAvailable {
+		return false
+	}
+	if q.Databases != nil && !inst.HasDatabaseIn(*q.Databases) {
+		return false
+	}
+	if q.Text != nil && *q.Text != "" {
+		if !fuzzyMatch(inst.DisplayName, *q.Text) && !fuzzyMatch(inst.Service.Name, *q.Text) {
+			return false
+		}
+	}
+	return true
+}
+
+// SortContainers sorts the containers in place. The UUID breaks the ties, so that the
+// order is stable between requests.
+func (q ContainerSearchQuery) SortContainers(containers []*Container) {
+	key := func(inst *Container) string {
+		switch q.Sort {
+		case ContainerSortService:
+			return strings.ToLower(inst.Service.Name)
+		case ContainerSortStatus:
+			return inst.Status
+		}
+		return strings.ToLower(inst.DisplayName)
+	}
+
+	sort.Slice(containers, func(i, j int) bool {
+		a, b := containers[i], containers[j]
+		if q.Order == SortOrderDesc {
+			a, b = b, a
+		}
+		ka, kb := key(a), key(b)
+		if ka != kb {
+			return ka < kb
+		}
+		return a.UUID.String() < b.UUID.String()
+	})
+}
+
+// Paginate returns the page of containers selected by Limit and Offset.
+func (q ContainerSearchQuery) Paginate(containers []*Container) []*Container {
+	if q.Offset >= len(containers) {
+		return []*Container{}
+	}
+	containers = containers[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(containers) {
+		containers = containers[:q.Limit]
+	}
+	return containers
+}
+
+// fuzzyMatch returns true if the text contains the pattern, or the letters
+// of the pattern in the same order, ignoring the case.
+func fuzzyMatch(text, pattern string) bool {
+	text = strings.ToLower(text)
+	pattern = strings.ToLower(pattern)
+	if strings.Contains(text, pattern) {
+		return true
+	}
+
+	runes := []rune(pattern)
+	i := 0
+	for _, r := range text {
+		if i < len(runes) && r == runes[i] {
+			i++
+		}
+	}
+	return i == len(runes)
+}
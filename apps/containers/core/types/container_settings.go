@@ -18,9 +18,29 @@ type ContainerSettings struct {
 	// The key is the database ID, and the value is the database container UUID.
 	Databases map[string]uuid.UUID `json:"databases,omitempty" yaml:"databases,omitempty"`
 
-	// Version is the version of the program.
+	// Version is the version of the program. It can be a fixed image tag,
+	// or a constraint like "15.x" to track the latest version within 15.
 	Version *string `json:"version,omitempty" yaml:"version,omitempty"`
 
+	// VersionChannel is the channel (stable, beta...) used to
+	// list versions and check for updates.
+	VersionChannel *string `json:"version_channel,omitempty" yaml:"version_channel,omitempty"`
+
 	// Tags are the tags assigned to the container.
 	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// Schedule restricts when the container runs. If nil, the container
+	// runs until it is stopped.
+	Schedule *ContainerSchedule `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+
+	// Hooks are the actions run around the start and the stop of the
+	// container, after the hooks of the service.
+	Hooks *Hooks `json:"hooks,omitempty" yaml:"hooks,omitempty"`
+
+	// Replicas is the number of Docker containers running this container.
+	// The default value is 1.
+	Replicas *int `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+
+	// Stack is set if the container is a member of a stack.
+	Stack *StackMember `json:"stack,omitempty" yaml:"stack,omitempty"`
 }
@@ -0,0 +1,156 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/config"
+)
+
+type ContainerTestSuite struct {
+	suite.Suite
+}
+
+func TestContainerTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerTestSuite))
+}
+
+func (suite *ContainerTestSuite) TestInternalPort() {
+	ports := map[string]string{"5432": "5432"}
+	inst := NewContainer(uuid.New(), Service{
+		Env: []ServiceEnv{
+			{Type: "port", Name: "DB_PORT", Default: "5432"},
+			{Type: "string", Name: "DB_USER", Default: "postgres"},
+		},
+		Methods: ServiceMethods{
+			Docker: &ServiceMethodDocker{Ports: &ports},
+		},
+	})
+	inst.Env["DB_PORT"] = "15432"
+	inst.Env["OTHER_PORT"] = "8080"
+
+	suite.Equal("5432", inst.InternalPort("DB_PORT"))
+	suite.Equal("8080", inst.InternalPort("OTHER_PORT"))
+}
+
+func (suite *ContainerTestSuite) TestDockerNetworkAlias() {
+	inst := NewContainer(uuid.MustParse("6e0e7d08-9a1c-4d4e-8e7e-3d2f0b4c1a2b"), Service{})
+
+	suite.Equal("vertex-6e0e7d08-9a1c-4d4e-8e7e-3d2f0b4c1a2b", inst.DockerNetworkAlias())
+	suite.Equal("vertex_network_6e0e7d08-9a1c-4d4e-8e7e-3d2f0b4c1a2b", inst.DockerNetworkName())
+}
+
+func (suite *ContainerTestSuite) TestParseDockerContainerName() {
+	inst := NewContainer(uuid.New(), Service{})
+
+	id, ok := ParseDockerContainerName(inst.DockerContainerName())
+	suite.True(ok)
+	suite.Equal(inst.UUID, id)
+
+	id, ok = ParseDockerContainerName("/" + inst.DockerContainerName())
+	suite.True(ok)
+	suite.Equal(inst.UUID, id)
+
+	_, ok = ParseDockerContainerName("postgres")
+	suite.False(ok)
+
+	_, ok = ParseDockerContainerName("VERTEX_CONTAINER_invalid")
+	suite.False(ok)
+}
+
+func (suite *ContainerTestSuite) TestParseDockerReplicaName() {
+	inst := NewContainer(uuid.New(), Service{})
+
+	suite.Equal(inst.DockerContainerName(), inst.DockerReplicaName(0))
+
+	id, replica, ok := ParseDockerReplicaName("/" + inst.DockerReplicaName(3))
+	suite.True(ok)
+	suite.Equal(inst.UUID, id)
+	suite.Equal(3, replica)
+
+	id, replica, ok = ParseDockerReplicaName(inst.DockerReplicaName(0))
+	suite.True(ok)
+	suite.Equal(inst.UUID, id)
+	suite.Equal(0, replica)
+
+	_, _, ok = ParseDockerReplicaName(inst.DockerContainerName() + "_REPLICA_x")
+	suite.False(ok)
+
+	_, _, ok = ParseDockerReplicaName(inst.DockerContainerName() + "_HOOK_1a2b3c4d")
+	suite.False(ok)
+}
+
+func (suite *ContainerTestSuite) TestReplicas() {
+	inst := NewContainer(uuid.New(), Service{})
+	suite.Equal(1, inst.GetReplicas())
+
+	replicas := 3
+	inst.ContainerSettings.Replicas = &replicas
+	suite.Equal(3, inst.GetReplicas())
+
+	suite.Equal("8080", ReplicaPort("8080", 0))
+	suite.Equal("8082", ReplicaPort("8080", 2))
+}
+
+func (suite *ContainerTestSuite) TestReplicaTargets() {
+	replicas := 2
+	inst := NewContainer(uuid.New(), Service{
+		Env: []ServiceEnv{{Type: "port", Name: "PORT"}},
+	})
+	inst.Env = ContainerEnvVariables{"PORT": "8080"}
+	inst.ContainerSettings.Replicas = &replicas
+
+	target, err := url.Parse("http://127.0.0.1:8080/api")
+	suite.NoError(err)
+	targets := inst.ReplicaTargets(target)
+	suite.Len(targets, 2)
+	suite.Equal("http://127.0.0.1:8081/api", targets[1].String())
+
+	target, err = url.Parse(fmt.Sprintf("http://%s:8080", config.Current.Host))
+	suite.NoError(err)
+	suite.Len(inst.ReplicaTargets(target), 2)
+
+	target, err = url.Parse("http://example.com:8080")
+	suite.NoError(err)
+	suite.Nil(inst.ReplicaTargets(target))
+
+	target, err = url.Parse("http://127.0.0.1:9000")
+	suite.NoError(err)
+	suite.Nil(inst.ReplicaTargets(target))
+}
+
+func (suite *ContainerTestSuite) TestDockerVolumeName() {
+	inst := NewContainer(uuid.MustParse("6e0e7d08-9a1c-4d4e-8e7e-3d2f0b4c1a2b"), Service{})
+
+	suite.Equal("vertex_volume_6e0e7d08-9a1c-4d4e-8e7e-3d2f0b4c1a2b_data", inst.DockerVolumeName("data"))
+}
+
+func (suite *ContainerTestSuite) TestMaskSecretEnv() {
+	secret := true
+	inst := NewContainer(uuid.New(), Service{
+		Env: []ServiceEnv{
+			{Name: "DB_USER"},
+			{Name: "DB_PASSWORD", Secret: &secret},
+		},
+	})
+
+	env := inst.MaskSecretEnv([]string{"DB_USER=postgres", "DB_PASSWORD=hunter2", "PATH=/bin"})
+
+	suite.Equal([]string{"DB_USER=postgres", "DB_PASSWORD=" + SecretEnvMask, "PATH=/bin"}, env)
+}
+
+func (suite *ContainerTestSuite) TestFuzzyMatch() {
+	suite.True(fuzzyMatch("Postgres", "gres"))
+	suite.True(fuzzyMatch("Postgres", "PGS"))
+	suite.False(fuzzyMatch("Postgres", "sgp"))
+}
+
+func (suite *ContainerTestSuite) TestSearchQueryValidate() {
+	suite.NoError(ContainerSearchQuery{Sort: ContainerSortStatus, Order: SortOrderDesc}.Validate())
+	suite.ErrorIs(ContainerSearchQuery{Sort: "uuid"}.Validate(), ErrInvalidSearchQuery)
+	suite.ErrorIs(ContainerSearchQuery{Order: "random"}.Validate(), ErrInvalidSearchQuery)
+	suite.ErrorIs(ContainerSearchQuery{Limit: -1}.Validate(), ErrInvalidSearchQuery)
+}
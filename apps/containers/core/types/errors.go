@@ -13,17 +13,52 @@ const (
 	ErrCodeFailedToStartContainer         router.ErrCode = "failed_to_start_container"
 	ErrCodeFailedToStopContainer          router.ErrCode = "failed_to_stop_container"
 	ErrCodeFailedToDeleteContainer        router.ErrCode = "failed_to_delete_container"
+	ErrCodeInvalidCloneOptions            router.ErrCode = "invalid_clone_options"
+	ErrCodeFailedToCloneContainer         router.ErrCode = "failed_to_clone_container"
 	ErrCodeFailedToGetContainerLogs       router.ErrCode = "failed_to_get_logs"
+	ErrCodeInvalidLogQuery                router.ErrCode = "invalid_log_query"
+	ErrCodeInvalidSearchQuery             router.ErrCode = "invalid_search_query"
 	ErrCodeFailedToUpdateServiceContainer router.ErrCode = "failed_to_update_service_container"
 	ErrCodeFailedToGetVersions            router.ErrCode = "failed_to_get_versions"
+	ErrCodeFailedToGetVolumes             router.ErrCode = "failed_to_get_volumes"
 	ErrCodeFailedToWaitContainer          router.ErrCode = "failed_to_wait_container"
 	ErrCodeFailedToSetLaunchOnStartup     router.ErrCode = "failed_to_set_launch_on_startup"
 	ErrCodeFailedToSetDisplayName         router.ErrCode = "failed_to_set_display_name"
 	ErrCodeFailedToSetDatabase            router.ErrCode = "failed_to_set_database"
 	ErrCodeFailedToSetVersion             router.ErrCode = "failed_to_set_version"
+	ErrCodeFailedToSetVersionChannel      router.ErrCode = "failed_to_set_version_channel"
+	ErrCodeVersionChannelNotFound         router.ErrCode = "version_channel_not_found"
 	ErrCodeFailedToSetTags                router.ErrCode = "failed_to_set_tags"
+	ErrCodeInvalidSchedule                router.ErrCode = "invalid_schedule"
+	ErrCodeFailedToSetSchedule            router.ErrCode = "failed_to_set_schedule"
+	ErrCodeInvalidHooks                   router.ErrCode = "invalid_hooks"
+	ErrCodeFailedToSetHooks               router.ErrCode = "failed_to_set_hooks"
+	ErrCodeInvalidReplicas                router.ErrCode = "invalid_replicas"
+	ErrCodeReplicaPortsUsed               router.ErrCode = "replica_ports_used"
+	ErrCodeFailedToSetReplicas            router.ErrCode = "failed_to_set_replicas"
+	ErrCodeFailedToRestartContainer       router.ErrCode = "failed_to_restart_container"
 	ErrCodeFailedToSetEnv                 router.ErrCode = "failed_to_set_env"
 	ErrCodeFailedToCheckForUpdates        router.ErrCode = "failed_to_check_for_updates"
+	ErrCodeFailedToCollectGarbage         router.ErrCode = "failed_to_collect_garbage"
+	ErrCodeBulkActionInvalid              router.ErrCode = "bulk_action_invalid"
+	ErrCodeFailedToRunBulkAction          router.ErrCode = "failed_to_run_bulk_action"
+
+	ErrCodeStackUuidMissing       router.ErrCode = "stack_uuid_missing"
+	ErrCodeStackUuidInvalid       router.ErrCode = "stack_uuid_invalid"
+	ErrCodeStackNotFound          router.ErrCode = "stack_not_found"
+	ErrCodeFailedToGetStack       router.ErrCode = "failed_to_get_stack"
+	ErrCodeInvalidStack           router.ErrCode = "invalid_stack"
+	ErrCodeFailedToInstallStack   router.ErrCode = "failed_to_install_stack"
+	ErrCodeFailedToRunStackAction router.ErrCode = "failed_to_run_stack_action"
+	ErrCodeFailedToDeleteStack    router.ErrCode = "failed_to_delete_stack"
+
+	ErrCodeLogSinkUuidMissing    router.ErrCode = "log_sink_uuid_missing"
+	ErrCodeLogSinkUuidInvalid    router.ErrCode = "log_sink_uuid_invalid"
+	ErrCodeLogSinkNotFound       router.ErrCode = "log_sink_not_found"
+	ErrCodeLogSinkTypeInvalid    router.ErrCode = "log_sink_type_invalid"
+	ErrCodeLogSinkURLInvalid     router.ErrCode = "log_sink_url_invalid"
+	ErrCodeFailedToAddLogSink    router.ErrCode = "failed_to_add_log_sink"
+	ErrCodeFailedToRemoveLogSink router.ErrCode = "failed_to_remove_log_sink"
 
 	ErrCodeServiceIdMissing       router.ErrCode = "service_id_missing"
 	ErrCodeServiceNotFound        router.ErrCode = "service_not_found"
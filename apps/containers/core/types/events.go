@@ -1,6 +1,10 @@
 package types
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 const (
 	EventNameContainersChange      = "change"
@@ -8,6 +12,11 @@ const (
 	EventNameContainerStdout       = "stdout"
 	EventNameContainerStderr       = "stderr"
 	EventNameContainerDownload     = "download"
+	EventNameContainersBulk        = "bulk"
+	EventNameContainerScheduled    = "scheduled"
+	EventNameContainerHook         = "hook"
+	EventNameStacksChange          = "stacks_change"
+	EventNameStackStatusChange     = "stack_status_change"
 )
 
 type (
@@ -19,6 +28,13 @@ type (
 		ContainerUUID uuid.UUID
 		Kind          string
 		Message       LogLineMessage
+
+		// Time is the time the line was written. If zero, the time the
+		// event is received is used.
+		Time time.Time
+
+		// Replica is the replica that wrote the line.
+		Replica int
 	}
 
 	EventContainerStatusChange struct {
@@ -43,4 +59,42 @@ type (
 	}
 
 	EventContainersStopped struct{}
+
+	// EventContainersBulk is dispatched each time a bulk action is done
+	// for a container.
+	EventContainersBulk struct {
+		Action string     `json:"action"`
+		Result BulkResult `json:"result"`
+		Done   int        `json:"done"`
+		Total  int        `json:"total"`
+
+		// Tags are the tags of the container, to only stream the event to
+		// the users allowed to see it.
+		Tags []string `json:"-"`
+	}
+
+	// EventContainerScheduled is dispatched each time a scheduled action
+	// is done for a container. For jobs, ExitCode is the exit code of the
+	// container.
+	EventContainerScheduled struct {
+		ContainerUUID uuid.UUID `json:"container_uuid"`
+		Action        string    `json:"action"`
+		ExitCode      *int      `json:"exit_code,omitempty"`
+		Error         string    `json:"error,omitempty"`
+	}
+
+	// EventStacksChange is dispatched when a stack is installed or
+	// deleted.
+	EventStacksChange struct{}
+
+	// EventStackStatusChange is dispatched when the status aggregated from
+	// the members of a stack changes.
+	EventStackStatusChange struct {
+		StackUUID uuid.UUID `json:"stack_uuid"`
+		Status    string    `json:"status"`
+	}
+
+	EventGarbageCollected struct {
+		Report GarbageCollectReport
+	}
 )
@@ -0,0 +1,33 @@
+package types
+
+const (
+	DefaultGarbageCollectKeepLast = 1
+)
+
+type GarbageCollectOptions struct {
+	// KeepLast is the number of unused images to keep for each service,
+	// to allow rolling back to a previous version.
+	KeepLast int `json:"keep_last"`
+
+	// BuildCache also prunes the Docker build cache.
+	BuildCache bool `json:"build_cache"`
+
+	// Volumes also removes the unused volumes of deleted Vertex containers.
+	Volumes bool `json:"volumes"`
+}
+
+type GarbageCollectReport struct {
+	Images  []string `json:"images"`
+	Volumes []string `json:"volumes"`
+
+	// ReclaimedBytes is the sum of the sizes of everything removed. Images
+	// can share layers, so the real disk space reclaimed can be lower.
+	ReclaimedBytes uint64 `json:"reclaimed_bytes"`
+}
+
+func DefaultGarbageCollectOptions() GarbageCollectOptions {
+	return GarbageCollectOptions{
+		KeepLast:   DefaultGarbageCollectKeepLast,
+		BuildCache: true,
+	}
+}
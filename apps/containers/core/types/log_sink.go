@@ -0,0 +1,92 @@
+package types
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	LogSinkTypeSyslog = "syslog"
+	LogSinkTypeLoki   = "loki"
+	LogSinkTypeHTTP   = "http"
+)
+
+const (
+	LogLabelContainerUUID = "container_uuid"
+	LogLabelServiceID     = "service_id"
+	LogLabelDisplayName   = "display_name"
+	LogLabelTags          = "tags"
+	LogLabelReplica       = "replica"
+)
+
+var (
+	ErrLogSinkNotFound    = errors.New("log sink not found")
+	ErrLogSinkTypeInvalid = errors.New("log sink type is invalid")
+	ErrLogSinkURLInvalid  = errors.New("log sink url is invalid")
+)
+
+type LogSinks map[uuid.UUID]LogSink
+
+// LogSink is a destination where the container logs are forwarded.
+type LogSink struct {
+	Type string `json:"type"`
+
+	// URL is the address of the sink. For syslog, it is "udp://host:port"
+	// or "tcp://host:port". For Loki, it is the base URL of the Loki
+	// server. For HTTP, it is the endpoint receiving the batches.
+	URL string `json:"url"`
+
+	// Headers are added to the requests sent to Loki and HTTP sinks.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Redacted returns the sinks with the values of their headers masked, as
+// the headers usually contain credentials.
+func (s LogSinks) Redacted() LogSinks {
+	redacted := LogSinks{}
+	for id, sink := range s {
+		if sink.Headers != nil {
+			headers := map[string]string{}
+			for name := range sink.Headers {
+				headers[name] = SecretEnvMask
+			}
+			sink.Headers = headers
+		}
+		redacted[id] = sink
+	}
+	return redacted
+}
+
+func (s LogSink) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return ErrLogSinkURLInvalid
+	}
+
+	switch s.Type {
+	case LogSinkTypeSyslog:
+		if (u.Scheme != "udp" && u.Scheme != "tcp") || u.Host == "" {
+			return ErrLogSinkURLInvalid
+		}
+	case LogSinkTypeLoki, LogSinkTypeHTTP:
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrLogSinkURLInvalid
+		}
+	default:
+		return ErrLogSinkTypeInvalid
+	}
+	return nil
+}
+
+// LogEntry is a log line sent to the log sinks.
+type LogEntry struct {
+	Time    time.Time         `json:"time"`
+	Kind    string            `json:"kind"`
+	Stream  string            `json:"stream"`
+	Level   string            `json:"level,omitempty"`
+	Message string            `json:"message"`
+	Labels  map[string]string `json:"labels"`
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"errors"
+	"regexp"
 
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vlog"
@@ -12,7 +13,8 @@ const (
 )
 
 var (
-	ErrServiceNotFound = errors.New("the service was not found")
+	ErrServiceNotFound        = errors.New("the service was not found")
+	ErrVersionChannelNotFound = errors.New("the version channel was not found")
 )
 
 type Version int
@@ -57,6 +59,13 @@ type Service struct {
 
 	// Methods defines different methods to install the service.
 	Methods ServiceMethods `yaml:"methods" json:"methods"`
+
+	// Logs describes the format of the logs written by the service.
+	Logs *ServiceLogs `yaml:"logs,omitempty" json:"logs,omitempty"`
+
+	// Hooks are the actions run around the start and the stop of the
+	// containers of the service.
+	Hooks *Hooks `yaml:"hooks,omitempty" json:"hooks,omitempty"`
 }
 
 type ServiceV1 Service
@@ -93,6 +102,21 @@ func (s *Service) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+type ServiceLogs struct {
+	// Format is the format of the log lines, like "logfmt". JSON lines
+	// are always detected, even without format.
+	Format *string `yaml:"format,omitempty" json:"format,omitempty"`
+}
+
+// LogFormat returns the log format declared by the service, or an empty
+// string if the service doesn't declare any.
+func (s *Service) LogFormat() string {
+	if s.Logs == nil || s.Logs.Format == nil {
+		return ""
+	}
+	return *s.Logs.Format
+}
+
 type ServiceUpdate struct {
 	Available bool `json:"available"`
 }
@@ -200,9 +224,17 @@ type ServiceMethodDocker struct {
 	Ports *map[string]string `yaml:"ports,omitempty" json:"ports,omitempty"`
 
 	// Volumes is a map containing output folder as a key, and input folder from Docker
-	// as a string value.
+	// as a string value. The input folder can end with ":ro" to mount it read-only.
 	Volumes *map[string]string `yaml:"volumes,omitempty" json:"volumes,omitempty"`
 
+	// NamedVolumes is a map containing the volume name as a key, and the
+	// volume to mount as a value. Named volumes are managed by Docker.
+	NamedVolumes *map[string]ServiceNamedVolume `yaml:"named_volumes,omitempty" json:"named_volumes,omitempty"`
+
+	// Tmpfs is a map containing the input folder from Docker as a key, and
+	// the tmpfs mount as a value.
+	Tmpfs *map[string]ServiceTmpfs `yaml:"tmpfs,omitempty" json:"tmpfs,omitempty"`
+
 	// Environment is a map containing docker environment variable as a key, and
 	// its corresponding service environment name as a value.
 	Environment *map[string]string `yaml:"environment,omitempty" json:"environment,omitempty"`
@@ -215,6 +247,96 @@ type ServiceMethodDocker struct {
 
 	// Cmd is the command to run in the container.
 	Cmd *string `yaml:"command,omitempty" json:"command,omitempty"`
+
+	// StopTimeout is the number of seconds given to the container to stop
+	// gracefully before it is killed.
+	StopTimeout *int `yaml:"stop_timeout,omitempty" json:"stop_timeout,omitempty"`
+
+	// Versions describes which image tags are considered as versions.
+	Versions *ServiceVersions `yaml:"versions,omitempty" json:"versions,omitempty"`
+}
+
+type ServiceNamedVolume struct {
+	// Target is the input folder from Docker.
+	Target string `yaml:"target" json:"target"`
+
+	// Driver is the volume driver. It defaults to the local driver.
+	Driver *string `yaml:"driver,omitempty" json:"driver,omitempty"`
+
+	// DriverOptions are the options of the volume driver.
+	DriverOptions map[string]string `yaml:"driver_options,omitempty" json:"driver_options,omitempty"`
+
+	// ReadOnly mounts the volume read-only.
+	ReadOnly bool `yaml:"read_only,omitempty" json:"read_only,omitempty"`
+}
+
+type ServiceTmpfs struct {
+	// Size is the size limit of the tmpfs mount, like 64m.
+	Size *string `yaml:"size,omitempty" json:"size,omitempty"`
+}
+
+// Options returns the tmpfs options in the Docker format.
+func (t ServiceTmpfs) Options() string {
+	if t.Size == nil {
+		return ""
+	}
+	return "size=" + *t.Size
+}
+
+type ServiceVersions struct {
+	// Filter is a regular expression that image tags must match to be
+	// listed as versions. It allows to exclude nightly or arch-specific tags.
+	Filter *string `yaml:"filter,omitempty" json:"filter,omitempty"`
+
+	// Channels is a map containing the channel name (stable, beta...) as a key,
+	// and a regular expression matching the tags of this channel as a value.
+	Channels map[string]string `yaml:"channels,omitempty" json:"channels,omitempty"`
+}
+
+// FilterTags returns the tags matching the Filter and the given channel. If the
+// channel is empty, only the Filter is applied. If the channel is not declared by
+// the service, it returns ErrVersionChannelNotFound.
+func (v *ServiceVersions) FilterTags(tags []string, channel string) ([]string, error) {
+	if v == nil && channel != "" {
+		return nil, ErrVersionChannelNotFound
+	} else if v == nil {
+		return tags, nil
+	}
+
+	var filters []*regexp.Regexp
+	if v.Filter != nil {
+		re, err := regexp.Compile(*v.Filter)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, re)
+	}
+	if channel != "" {
+		expr, ok := v.Channels[channel]
+		if !ok {
+			return nil, ErrVersionChannelNotFound
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, re)
+	}
+
+	filtered := []string{}
+	for _, tag := range tags {
+		match := true
+		for _, re := range filters {
+			if !re.MatchString(tag) {
+				match = false
+				break
+			}
+		}
+		if match {
+			filtered = append(filtered, tag)
+		}
+	}
+	return filtered, nil
 }
 
 type ServiceMethods struct {
@@ -0,0 +1,93 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type ServiceVersionsTestSuite struct {
+	suite.Suite
+
+	versions *ServiceVersions
+}
+
+func TestServiceVersionsTestSuite(t *testing.T) {
+	suite.Run(t, new(ServiceVersionsTestSuite))
+}
+
+func (suite *ServiceVersionsTestSuite) SetupTest() {
+	filter := `^\d+(\.\d+)*(-rc\d+)?$`
+	suite.versions = &ServiceVersions{
+		Filter: &filter,
+		Channels: map[string]string{
+			VersionChannelStable: `^\d+(\.\d+)*$`,
+			VersionChannelBeta:   `.*`,
+		},
+	}
+}
+
+func (suite *ServiceVersionsTestSuite) TestFilterTags() {
+	tags := []string{"latest", "15.4", "15.5-rc1", "nightly", "15.4-arm64"}
+
+	stable, err := suite.versions.FilterTags(tags, VersionChannelStable)
+	suite.NoError(err)
+	suite.Equal([]string{"15.4"}, stable)
+
+	beta, err := suite.versions.FilterTags(tags, VersionChannelBeta)
+	suite.NoError(err)
+	suite.Equal([]string{"15.4", "15.5-rc1"}, beta)
+
+	_, err = suite.versions.FilterTags(tags, "unknown")
+	suite.ErrorIs(err, ErrVersionChannelNotFound)
+}
+
+func (suite *ServiceVersionsTestSuite) TestFilterTagsNil() {
+	var versions *ServiceVersions
+
+	tags, err := versions.FilterTags([]string{"latest"}, "")
+	suite.NoError(err)
+	suite.Equal([]string{"latest"}, tags)
+
+	_, err = versions.FilterTags([]string{"latest"}, VersionChannelStable)
+	suite.ErrorIs(err, ErrVersionChannelNotFound)
+}
+
+type ServiceMethodDockerTestSuite struct {
+	suite.Suite
+}
+
+func TestServiceMethodDockerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServiceMethodDockerTestSuite))
+}
+
+func (suite *ServiceMethodDockerTestSuite) TestUnmarshalVolumes() {
+	data := `
+volumes:
+  config: /config:ro
+named_volumes:
+  data:
+    target: /data
+    driver_options:
+      type: nfs
+    read_only: true
+tmpfs:
+  /tmp:
+    size: 64m
+`
+	var docker ServiceMethodDocker
+	err := yaml.Unmarshal([]byte(data), &docker)
+	suite.Require().NoError(err)
+
+	suite.Equal("/config:ro", (*docker.Volumes)["config"])
+
+	volume := (*docker.NamedVolumes)["data"]
+	suite.Equal("/data", volume.Target)
+	suite.Equal("nfs", volume.DriverOptions["type"])
+	suite.True(volume.ReadOnly)
+	suite.Nil(volume.Driver)
+
+	suite.Equal("size=64m", (*docker.Tmpfs)["/tmp"].Options())
+	suite.Equal("", ServiceTmpfs{}.Options())
+}
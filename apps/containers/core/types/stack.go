@@ -0,0 +1,129 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StackStatusRunning = "running"
+	StackStatusOff     = "off"
+	StackStatusError   = "error"
+
+	// StackStatusPartial is the status of a stack with members that are
+	// running and members that are not, or that are changing status.
+	StackStatusPartial = "partial"
+)
+
+var (
+	ErrStackNotFound = errors.New("stack not found")
+	ErrInvalidStack  = errors.New("invalid stack")
+)
+
+type Stacks map[uuid.UUID]Stack
+
+// Stack is a group of containers installed and managed as a unit.
+type Stack struct {
+	UUID       uuid.UUID       `json:"uuid"`
+	Definition StackDefinition `json:"definition"`
+
+	// Members are the containers of the stack, by member name.
+	Members map[string]uuid.UUID `json:"members"`
+
+	// Status is aggregated from the status of the members. It is not
+	// persisted.
+	Status string `json:"status,omitempty"`
+}
+
+// StackDefinition describes the containers of a stack, and how they are
+// linked together.
+type StackDefinition struct {
+	Name    string                  `json:"name" yaml:"name"`
+	Members []StackMemberDefinition `json:"members" yaml:"members"`
+
+	// Env is shared by the members. A variable is only set in the members
+	// whose service uses it.
+	Env map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+}
+
+// StackMemberDefinition describes a container of a stack.
+type StackMemberDefinition struct {
+	// Name identifies the member in the stack. The other members reach it
+	// with this name on the network of the stack.
+	Name      string `json:"name" yaml:"name"`
+	ServiceID string `json:"service_id" yaml:"service_id"`
+
+	// Env overrides the shared env of the stack for this member.
+	Env map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// Databases links the databases of the service to other members. The
+	// key is the database ID, and the value is the name of the member.
+	Databases map[string]string `json:"databases,omitempty" yaml:"databases,omitempty"`
+
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// StackMember is set in the settings of the containers that are members of
+// a stack.
+type StackMember struct {
+	UUID uuid.UUID `json:"uuid" yaml:"uuid"`
+	Name string    `json:"name" yaml:"name"`
+}
+
+// Validate returns ErrInvalidStack if the stack has no name or members, if
+// two members have the same name, or if a database links to an unknown
+// member.
+func (d StackDefinition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("%w: the name is required", ErrInvalidStack)
+	}
+	if len(d.Members) == 0 {
+		return fmt.Errorf("%w: at least one member is required", ErrInvalidStack)
+	}
+
+	names := map[string]bool{}
+	for _, m := range d.Members {
+		if m.Name == "" || m.ServiceID == "" {
+			return fmt.Errorf("%w: the members require a name and a service_id", ErrInvalidStack)
+		}
+		if names[m.Name] {
+			return fmt.Errorf("%w: duplicate member '%s'", ErrInvalidStack, m.Name)
+		}
+		names[m.Name] = true
+	}
+
+	for _, m := range d.Members {
+		for db, member := range m.Databases {
+			if !names[member] || member == m.Name {
+				return fmt.Errorf("%w: the database '%s' of '%s' links to an unknown member '%s'", ErrInvalidStack, db, m.Name, member)
+			}
+		}
+	}
+	return nil
+}
+
+// StackStatus returns the status of a stack from the status of its
+// members.
+func StackStatus(members []*Container) string {
+	running, off := 0, 0
+	for _, inst := range members {
+		switch inst.Status {
+		case ContainerStatusError:
+			return StackStatusError
+		case ContainerStatusRunning:
+			running++
+		case ContainerStatusOff:
+			off++
+		}
+	}
+
+	switch {
+	case running == len(members) && running > 0:
+		return StackStatusRunning
+	case off == len(members):
+		return StackStatusOff
+	}
+	return StackStatusPartial
+}
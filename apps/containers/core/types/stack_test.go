@@ -0,0 +1,41 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type StackTestSuite struct {
+	suite.Suite
+}
+
+func TestStackTestSuite(t *testing.T) {
+	suite.Run(t, new(StackTestSuite))
+}
+
+func (suite *StackTestSuite) TestValidate() {
+	app := StackMemberDefinition{Name: "app", ServiceID: "app", Databases: map[string]string{"db": "db"}}
+	db := StackMemberDefinition{Name: "db", ServiceID: "postgres"}
+
+	suite.NoError(StackDefinition{Name: "stack", Members: []StackMemberDefinition{app, db}}.Validate())
+	suite.ErrorIs(StackDefinition{Members: []StackMemberDefinition{db}}.Validate(), ErrInvalidStack)
+	suite.ErrorIs(StackDefinition{Name: "stack"}.Validate(), ErrInvalidStack)
+	suite.ErrorIs(StackDefinition{Name: "stack", Members: []StackMemberDefinition{{Name: "db"}}}.Validate(), ErrInvalidStack)
+	suite.ErrorIs(StackDefinition{Name: "stack", Members: []StackMemberDefinition{db, db}}.Validate(), ErrInvalidStack)
+	suite.ErrorIs(StackDefinition{Name: "stack", Members: []StackMemberDefinition{app}}.Validate(), ErrInvalidStack)
+}
+
+func (suite *StackTestSuite) TestStackStatus() {
+	running := &Container{Status: ContainerStatusRunning}
+	off := &Container{Status: ContainerStatusOff}
+	starting := &Container{Status: ContainerStatusStarting}
+	failed := &Container{Status: ContainerStatusError}
+
+	suite.Equal(StackStatusOff, StackStatus(nil))
+	suite.Equal(StackStatusRunning, StackStatus([]*Container{running, running}))
+	suite.Equal(StackStatusOff, StackStatus([]*Container{off, off}))
+	suite.Equal(StackStatusPartial, StackStatus([]*Container{running, off}))
+	suite.Equal(StackStatusPartial, StackStatus([]*Container{running, starting}))
+	suite.Equal(StackStatusError, StackStatus([]*Container{running, failed}))
+}
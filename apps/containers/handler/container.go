@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/vertex-center/vertex/apps/containers/core/port"
 	"github.com/vertex-center/vertex/apps/containers/core/service"
@@ -19,37 +22,40 @@ import (
 )
 
 type ContainerHandler struct {
-	ctx                      *apptypes.Context
-	containerService         port.ContainerService
-	containerSettingsService port.ContainerSettingsService
-	containerRunnerService   port.ContainerRunnerService
-	containerEnvService      port.ContainerEnvService
-	containerServiceService  port.ContainerServiceService
-	containerLogsService     port.ContainerLogsService
-	serviceService           port.ServiceService
+	ctx                       *apptypes.Context
+	containerService          port.ContainerService
+	containerSettingsService  port.ContainerSettingsService
+	containerRunnerService    port.ContainerRunnerService
+	containerEnvService       port.ContainerEnvService
+	containerServiceService   port.ContainerServiceService
+	containerLogsService      port.ContainerLogsService
+	containerSchedulerService port.ContainerSchedulerService
+	serviceService            port.ServiceService
 }
 
 type ContainerHandlerParams struct {
-	Ctx                      *apptypes.Context
-	ContainerService         port.ContainerService
-	ContainerSettingsService port.ContainerSettingsService
-	ContainerRunnerService   port.ContainerRunnerService
-	ContainerEnvService      port.ContainerEnvService
-	ContainerServiceService  port.ContainerServiceService
-	ContainerLogsService     port.ContainerLogsService
-	ServiceService           port.ServiceService
+	Ctx                       *apptypes.Context
+	ContainerService          port.ContainerService
+	ContainerSettingsService  port.ContainerSettingsService
+	ContainerRunnerService    port.ContainerRunnerService
+	ContainerEnvService       port.ContainerEnvService
+	ContainerServiceService   port.ContainerServiceService
+	ContainerLogsService      port.ContainerLogsService
+	ContainerSchedulerService port.ContainerSchedulerService
+	ServiceService            port.ServiceService
 }
 
 func NewContainerHandler(params ContainerHandlerParams) port.ContainerHandler {
 	return &ContainerHandler{
-		ctx:                      params.Ctx,
-		containerService:         params.ContainerService,
-		containerSettingsService: params.ContainerSettingsService,
-		containerRunnerService:   params.ContainerRunnerService,
-		containerEnvService:      params.ContainerEnvService,
-		containerServiceService:  params.ContainerServiceService,
-		containerLogsService:     params.ContainerLogsService,
-		serviceService:           params.ServiceService,
+		ctx:                       params.Ctx,
+		containerService:          params.ContainerService,
+		containerSettingsService:  params.ContainerSettingsService,
+		containerRunnerService:    params.ContainerRunnerService,
+		containerEnvService:       params.ContainerEnvService,
+		containerServiceService:   params.ContainerServiceService,
+		containerLogsService:      params.ContainerLogsService,
+		containerSchedulerService: params.ContainerSchedulerService,
+		serviceService:            params.ServiceService,
 	}
 }
 
@@ -117,7 +123,11 @@ func (h *ContainerHandler) Delete(c *router.Context) {
 		return
 	}
 
-	err := h.containerService.Delete(inst)
+	options := types3.DeleteContainerOptions{
+		Volumes: c.Query("volumes") == "true",
+	}
+
+	err := h.containerService.Delete(c.Request.Context(), inst, options)
 	if err != nil && errors.Is(err, types3.ErrContainerStillRunning) {
 		c.Conflict(router.Error{
 			Code:           types3.ErrCodeContainerStillRunning,
@@ -137,12 +147,137 @@ func (h *ContainerHandler) Delete(c *router.Context) {
 	c.OK()
 }
 
+// Clone creates a copy of the container, and returns it.
+func (h *ContainerHandler) Clone(c *router.Context) {
+	var options types3.CloneContainerOptions
+	err := c.ParseBody(&options)
+	if err != nil {
+		return
+	}
+
+	inst := h.getContainer(c)
+	if inst == nil {
+		return
+	}
+
+	// A role limited to some tags can't give the clone a tag it is not
+	// allowed to manage.
+	if allowed, scoped := apptypes.AllowedTags(c); scoped && options.Tags != nil && !tagsAllowed(options.Tags, allowed) {
+		c.AbortWithCode(http.StatusForbidden, router.Error{
+			Code:           api.ErrForbidden,
+			PublicMessage:  "The clone can only have the tags you are allowed to manage.",
+			PrivateMessage: "the tags of the clone are not allowed",
+		})
+		return
+	}
+
+	clone, err := h.containerService.Clone(c.Request.Context(), inst, options)
+	if err != nil && errors.Is(err, types3.ErrInvalidCloneOptions) {
+		c.BadRequest(router.Error{
+			Code:           types3.ErrCodeInvalidCloneOptions,
+			PublicMessage:  "The clone options are invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil && errors.Is(err, types3.ErrContainerStillRunning) {
+		c.Conflict(router.Error{
+			Code:           types3.ErrCodeContainerStillRunning,
+			PublicMessage:  fmt.Sprintf("The container '%s' is still running. Stop it first before copying its volumes.", inst.DisplayName),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           types3.ErrCodeFailedToCloneContainer,
+			PublicMessage:  fmt.Sprintf("The container '%s' could not be cloned.", inst.DisplayName),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	// The clone and its cloned databases follow the schedules they copied.
+	schedule := []*types3.Container{clone}
+	for _, id := range clone.Databases {
+		db, err := h.containerService.Get(id)
+		if err == nil {
+			schedule = append(schedule, db)
+		}
+	}
+	for _, inst := range schedule {
+		err = h.containerSchedulerService.Schedule(inst)
+		if err != nil {
+			log.Error(err)
+		}
+	}
+
+	c.JSON(clone)
+}
+
+// tagsAllowed returns true if all the tags are allowed. The tags can't be
+// empty, otherwise nobody limited to some tags could manage the resource.
+func tagsAllowed(tags []string, allowed []string) bool {
+	if len(tags) == 0 {
+		return false
+	}
+	for _, tag := range tags {
+		found := false
+		for _, a := range allowed {
+			if tag == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// patchAllowed checks that a role limited to some tags only links the
+// databases it can manage, because their credentials are copied in the env
+// of the container, and only sets the tags it is allowed to manage. The
+// request is aborted if not.
+func (h *ContainerHandler) patchAllowed(c *router.Context, body PatchBody) bool {
+	allowed, scoped := apptypes.AllowedTags(c)
+	if !scoped {
+		return true
+	}
+
+	scope := types2.Authorization{Allowed: true, Tags: allowed}
+	for _, id := range body.Databases {
+		db, err := h.containerService.Get(id)
+		if err != nil || !scope.AllowsTags(db.Tags) {
+			c.AbortWithCode(http.StatusForbidden, router.Error{
+				Code:           api.ErrForbidden,
+				PublicMessage:  "The container can only be linked to the databases you are allowed to manage.",
+				PrivateMessage: fmt.Sprintf("the database %s is not allowed", id),
+			})
+			return false
+		}
+	}
+
+	if body.Tags != nil && !tagsAllowed(body.Tags, allowed) {
+		c.AbortWithCode(http.StatusForbidden, router.Error{
+			Code:           api.ErrForbidden,
+			PublicMessage:  "The container can only have the tags you are allowed to manage.",
+			PrivateMessage: "the tags of the container are not allowed",
+		})
+		return false
+	}
+	return true
+}
+
 type PatchBody struct {
-	LaunchOnStartup *bool                `json:"launch_on_startup,omitempty"`
-	DisplayName     *string              `json:"display_name,omitempty"`
-	Databases       map[string]uuid.UUID `json:"databases,omitempty"`
-	Version         *string              `json:"version,omitempty"`
-	Tags            []string             `json:"tags,omitempty"`
+	LaunchOnStartup *bool                     `json:"launch_on_startup,omitempty"`
+	DisplayName     *string                   `json:"display_name,omitempty"`
+	Databases       map[string]uuid.UUID      `json:"databases,omitempty"`
+	Version         *string                   `json:"version,omitempty"`
+	VersionChannel  *string                   `json:"version_channel,omitempty"`
+	Tags            []string                  `json:"tags,omitempty"`
+	Schedule        *types3.ContainerSchedule `json:"schedule,omitempty"`
+	Hooks           *types3.Hooks             `json:"hooks,omitempty"`
+	Replicas        *int                      `json:"replicas,omitempty"`
 }
 
 func (h *ContainerHandler) Patch(c *router.Context) {
@@ -157,6 +292,10 @@ func (h *ContainerHandler) Patch(c *router.Context) {
 		return
 	}
 
+	if !h.patchAllowed(c, body) {
+		return
+	}
+
 	if body.LaunchOnStartup != nil {
 		err = h.containerSettingsService.SetLaunchOnStartup(inst, *body.LaunchOnStartup)
 		if err != nil {
@@ -205,6 +344,25 @@ func (h *ContainerHandler) Patch(c *router.Context) {
 		}
 	}
 
+	if body.VersionChannel != nil {
+		err = h.containerSettingsService.SetVersionChannel(inst, *body.VersionChannel)
+		if err != nil && errors.Is(err, types3.ErrVersionChannelNotFound) {
+			c.NotFound(router.Error{
+				Code:           types3.ErrCodeVersionChannelNotFound,
+				PublicMessage:  fmt.Sprintf("The version channel '%s' is not available for this service.", *body.VersionChannel),
+				PrivateMessage: err.Error(),
+			})
+			return
+		} else if err != nil {
+			c.Abort(router.Error{
+				Code:           types3.ErrCodeFailedToSetVersionChannel,
+				PublicMessage:  "Failed to change version channel.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+	}
+
 	if body.Tags != nil {
 		err = h.containerSettingsService.SetTags(inst, body.Tags)
 		if err != nil {
@@ -217,6 +375,80 @@ func (h *ContainerHandler) Patch(c *router.Context) {
 		}
 	}
 
+	if body.Schedule != nil {
+		err = h.containerSettingsService.SetSchedule(inst, *body.Schedule)
+		if err != nil && errors.Is(err, types3.ErrInvalidSchedule) {
+			c.BadRequest(router.Error{
+				Code:           types3.ErrCodeInvalidSchedule,
+				PublicMessage:  "The schedule is invalid.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		} else if err != nil {
+			c.Abort(router.Error{
+				Code:           types3.ErrCodeFailedToSetSchedule,
+				PublicMessage:  "Failed to change schedule.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+
+		err = h.containerSchedulerService.Schedule(inst)
+		if err != nil {
+			c.Abort(router.Error{
+				Code:           types3.ErrCodeFailedToSetSchedule,
+				PublicMessage:  "Failed to schedule the container.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+	}
+
+	if body.Hooks != nil {
+		err = h.containerSettingsService.SetHooks(inst, *body.Hooks)
+		if err != nil && errors.Is(err, types3.ErrInvalidHook) {
+			c.BadRequest(router.Error{
+				Code:           types3.ErrCodeInvalidHooks,
+				PublicMessage:  "The hooks are invalid.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		} else if err != nil {
+			c.Abort(router.Error{
+				Code:           types3.ErrCodeFailedToSetHooks,
+				PublicMessage:  "Failed to change hooks.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+	}
+
+	if body.Replicas != nil {
+		err = h.containerService.SetReplicas(inst, *body.Replicas)
+		if err != nil && errors.Is(err, types3.ErrInvalidReplicas) {
+			c.BadRequest(router.Error{
+				Code:           types3.ErrCodeInvalidReplicas,
+				PublicMessage:  "The number of replicas is invalid.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		} else if err != nil && errors.Is(err, types3.ErrReplicaPortsUsed) {
+			c.Conflict(router.Error{
+				Code:           types3.ErrCodeReplicaPortsUsed,
+				PublicMessage:  "The ports of the replicas are used by another container.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		} else if err != nil {
+			c.Abort(router.Error{
+				Code:           types3.ErrCodeFailedToSetReplicas,
+				PublicMessage:  "Failed to change replicas.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+	}
+
 	c.OK()
 }
 
@@ -259,7 +491,7 @@ func (h *ContainerHandler) Stop(c *router.Context) {
 		return
 	}
 
-	err := h.containerRunnerService.Stop(inst)
+	err := h.containerRunnerService.Stop(c.Request.Context(), inst)
 	if err != nil && errors.Is(err, service.ErrContainerNotRunning) {
 		c.Conflict(router.Error{
 			Code:           types3.ErrCodeContainerNotRunning,
@@ -279,6 +511,33 @@ func (h *ContainerHandler) Stop(c *router.Context) {
 	c.OK()
 }
 
+// RollingRestart restarts the replicas of the container one after the other.
+func (h *ContainerHandler) RollingRestart(c *router.Context) {
+	inst := h.getContainer(c)
+	if inst == nil {
+		return
+	}
+
+	err := h.containerRunnerService.RollingRestart(c.Request.Context(), inst)
+	if err != nil && errors.Is(err, service.ErrContainerNotRunning) {
+		c.Conflict(router.Error{
+			Code:           types3.ErrCodeContainerNotRunning,
+			PublicMessage:  fmt.Sprintf("Container %s is not running.", inst.UUID),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           types3.ErrCodeFailedToRestartContainer,
+			PublicMessage:  fmt.Sprintf("Failed to restart container %s.", inst.UUID),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
 func (h *ContainerHandler) PatchEnvironment(c *router.Context) {
 	var environment map[string]string
 	err := c.ParseBody(&environment)
@@ -301,7 +560,7 @@ func (h *ContainerHandler) PatchEnvironment(c *router.Context) {
 		return
 	}
 
-	err = h.containerRunnerService.RecreateContainer(inst)
+	err = h.containerRunnerService.RecreateContainer(c.Request.Context(), inst)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToRecreateContainer,
@@ -347,6 +606,11 @@ func (h *ContainerHandler) Events(c *router.Context) {
 					Event: types3.EventNameContainerDownload,
 					Data:  e.Message,
 				}
+			} else if e.Kind == types3.LogKindHook || e.Kind == types3.LogKindHookErr {
+				eventsChan <- sse.Event{
+					Event: types3.EventNameContainerHook,
+					Data:  e.Message,
+				}
 			}
 
 		case types3.EventContainerStatusChange:
@@ -358,6 +622,16 @@ func (h *ContainerHandler) Events(c *router.Context) {
 				Event: types3.EventNameContainerStatusChange,
 				Data:  e.Status,
 			}
+
+		case types3.EventContainerScheduled:
+			if inst.UUID != e.ContainerUUID {
+				break
+			}
+
+			eventsChan <- sse.Event{
+				Event: types3.EventNameContainerScheduled,
+				Data:  e,
+			}
 		}
 	})
 
@@ -399,7 +673,7 @@ func (h *ContainerHandler) GetDocker(c *router.Context) {
 		return
 	}
 
-	info, err := h.containerRunnerService.GetDockerContainerInfo(*inst)
+	info, err := h.containerRunnerService.GetDockerContainerInfo(c.Request.Context(), *inst)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToGetContainerInfo,
@@ -418,7 +692,7 @@ func (h *ContainerHandler) RecreateDocker(c *router.Context) {
 		return
 	}
 
-	err := h.containerRunnerService.RecreateContainer(inst)
+	err := h.containerRunnerService.RecreateContainer(c.Request.Context(), inst)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToRecreateContainer,
@@ -437,7 +711,34 @@ func (h *ContainerHandler) GetLogs(c *router.Context) {
 		return
 	}
 
-	logs, err := h.containerLogsService.GetLatestLogs(*uid)
+	// Without any query parameter, only the latest logs kept in memory
+	// are returned.
+	if len(c.Request.URL.Query()) == 0 {
+		logs, err := h.containerLogsService.GetLatestLogs(*uid)
+		if err != nil {
+			c.Abort(router.Error{
+				Code:           types3.ErrCodeFailedToGetContainerLogs,
+				PublicMessage:  fmt.Sprintf("Failed to get logs for container %s.", uid),
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(logs)
+		return
+	}
+
+	query, err := h.getLogQuery(c)
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           types3.ErrCodeInvalidLogQuery,
+			PublicMessage:  "The logs query is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	page, err := h.containerLogsService.Query(*uid, query)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           types3.ErrCodeFailedToGetContainerLogs,
@@ -447,7 +748,49 @@ func (h *ContainerHandler) GetLogs(c *router.Context) {
 		return
 	}
 
-	c.JSON(logs)
+	c.JSON(page)
+}
+
+func (h *ContainerHandler) getLogQuery(c *router.Context) (types3.LogQuery, error) {
+	query := types3.LogQuery{
+		Query:  c.Query("q"),
+		Kinds:  c.QueryArray("kind"),
+		Levels: c.QueryArray("level"),
+	}
+
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return query, err
+		}
+		query.Since = &t
+	}
+
+	if until := c.Query("until"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return query, err
+		}
+		query.Until = &t
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return query, err
+		}
+		query.Limit = l
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		cur, err := strconv.Atoi(cursor)
+		if err != nil {
+			return query, err
+		}
+		query.Cursor = &cur
+	}
+
+	return query, nil
 }
 
 func (h *ContainerHandler) UpdateService(c *router.Context) {
@@ -487,7 +830,7 @@ func (h *ContainerHandler) GetVersions(c *router.Context) {
 
 	useCache := c.Query("reload") != "true"
 
-	versions, err := h.containerRunnerService.GetAllVersions(inst, useCache)
+	versions, err := h.containerRunnerService.GetAllVersions(c.Request.Context(), inst, useCache)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           types3.ErrCodeFailedToGetVersions,
@@ -500,6 +843,25 @@ func (h *ContainerHandler) GetVersions(c *router.Context) {
 	c.JSON(versions)
 }
 
+func (h *ContainerHandler) GetVolumes(c *router.Context) {
+	inst := h.getContainer(c)
+	if inst == nil {
+		return
+	}
+
+	volumes, err := h.containerRunnerService.GetVolumes(c.Request.Context(), inst)
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           types3.ErrCodeFailedToGetVolumes,
+			PublicMessage:  fmt.Sprintf("Failed to get volumes for container %s.", inst.UUID),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(volumes)
+}
+
 func (h *ContainerHandler) Wait(c *router.Context) {
 	cond := c.Param("cond")
 
@@ -508,7 +870,7 @@ func (h *ContainerHandler) Wait(c *router.Context) {
 		return
 	}
 
-	err := h.containerRunnerService.WaitCondition(inst, types2.WaitContainerCondition(cond))
+	err := h.containerRunnerService.WaitCondition(c.Request.Context(), inst, types2.WaitContainerCondition(cond))
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           types3.ErrCodeFailedToWaitContainer,
@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"fmt"
 	"io"
+	"strconv"
 
+	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers/core/port"
 	types2 "github.com/vertex-center/vertex/apps/containers/core/types"
 	vtypes "github.com/vertex-center/vertex/core/types"
@@ -14,29 +17,79 @@ import (
 )
 
 type ContainersHandler struct {
-	ctx              *apptypes.Context
-	containerService port.ContainerService
+	ctx                     *apptypes.Context
+	containerService        port.ContainerService
+	garbageCollectorService port.GarbageCollectorService
 }
 
-func NewContainersHandler(ctx *apptypes.Context, containerService port.ContainerService) port.ContainersHandler {
+func NewContainersHandler(ctx *apptypes.Context, containerService port.ContainerService, garbageCollectorService port.GarbageCollectorService) port.ContainersHandler {
 	return &ContainersHandler{
-		ctx:              ctx,
-		containerService: containerService,
+		ctx:                     ctx,
+		containerService:        containerService,
+		garbageCollectorService: garbageCollectorService,
 	}
 }
 
+// Get returns all the containers, sorted by name.
 func (h *ContainersHandler) Get(c *router.Context) {
-	installed := h.containerService.GetAll()
+	query := types2.ContainerSearchQuery{}
+	setQueryScope(c, &query)
+
+	installed, _ := h.containerService.Search(query)
 	c.JSON(installed)
 }
 
 func (h *ContainersHandler) GetTags(c *router.Context) {
 	tags := h.containerService.GetTags()
+
+	allowed, scoped := apptypes.AllowedTags(c)
+	if scoped {
+		var filtered []string
+		for _, tag := range tags {
+			for _, a := range allowed {
+				if tag == a {
+					filtered = append(filtered, tag)
+					break
+				}
+			}
+		}
+		tags = filtered
+	}
+
 	c.JSON(tags)
 }
 
+// Search returns the page of containers matching the query. The total
+// number of matching containers is sent in the X-Total-Count header.
 func (h *ContainersHandler) Search(c *router.Context) {
-	query := types2.ContainerSearchQuery{}
+	query, err := getSearchQuery(c)
+	if err == nil {
+		err = query.Validate()
+	}
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           types2.ErrCodeInvalidSearchQuery,
+			PublicMessage:  "The search query is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+	setQueryScope(c, &query)
+
+	installed, total := h.containerService.Search(query)
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	c.JSON(installed)
+}
+
+func getSearchQuery(c *router.Context) (types2.ContainerSearchQuery, error) {
+	query := types2.ContainerSearchQuery{
+		Sort:  c.Query("sort"),
+		Order: c.Query("order"),
+	}
+
+	if text := c.Query("q"); text != "" {
+		query.Text = &text
+	}
 
 	features := c.QueryArray("features[]")
 	if len(features) > 0 {
@@ -48,12 +101,62 @@ func (h *ContainersHandler) Search(c *router.Context) {
 		query.Tags = &tags
 	}
 
-	installed := h.containerService.Search(query)
-	c.JSON(installed)
+	statuses := c.QueryArray("statuses[]")
+	if len(statuses) > 0 {
+		query.Statuses = &statuses
+	}
+
+	serviceIDs := c.QueryArray("service_ids[]")
+	if len(serviceIDs) > 0 {
+		query.ServiceIDs = &serviceIDs
+	}
+
+	installMethods := c.QueryArray("install_methods[]")
+	if len(installMethods) > 0 {
+		query.InstallMethods = &installMethods
+	}
+
+	if databases := c.QueryArray("databases[]"); len(databases) > 0 {
+		ids := make([]uuid.UUID, len(databases))
+		for i, db := range databases {
+			id, err := uuid.Parse(db)
+			if err != nil {
+				return query, err
+			}
+			ids[i] = id
+		}
+		query.Databases = &ids
+	}
+
+	if update := c.Query("update_available"); update != "" {
+		b, err := strconv.ParseBool(update)
+		if err != nil {
+			return query, err
+		}
+		query.UpdateAvailable = &b
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return query, err
+		}
+		query.Limit = l
+	}
+
+	if offset := c.Query("offset"); offset != "" {
+		o, err := strconv.Atoi(offset)
+		if err != nil {
+			return query, err
+		}
+		query.Offset = o
+	}
+
+	return query, nil
 }
 
 func (h *ContainersHandler) CheckForUpdates(c *router.Context) {
-	containers, err := h.containerService.CheckForUpdates()
+	containers, err := h.containerService.CheckForUpdates(c.Request.Context())
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           types2.ErrCodeFailedToCheckForUpdates,
@@ -66,18 +169,101 @@ func (h *ContainersHandler) CheckForUpdates(c *router.Context) {
 	c.JSON(containers)
 }
 
+// Bulk applies an action to all the containers matching the query. The
+// progress is streamed by Events.
+func (h *ContainersHandler) Bulk(c *router.Context) {
+	var request types2.BulkRequest
+	err := c.ParseBody(&request)
+	if err != nil {
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           types2.ErrCodeBulkActionInvalid,
+			PublicMessage:  fmt.Sprintf("Invalid bulk action '%s'.", request.Action),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	err = request.Query.Validate()
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           types2.ErrCodeInvalidSearchQuery,
+			PublicMessage:  "The search query is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+	setQueryScope(c, &request.Query)
+
+	containers, _ := h.containerService.Search(request.Query)
+
+	results, err := h.containerService.Bulk(c.Request.Context(), containers, request)
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           types2.ErrCodeFailedToRunBulkAction,
+			PublicMessage:  fmt.Sprintf("Failed to run the bulk action '%s'.", request.Action),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(results)
+}
+
+func (h *ContainersHandler) CollectGarbage(c *router.Context) {
+	options := types2.DefaultGarbageCollectOptions()
+	if c.Request.ContentLength != 0 {
+		err := c.ParseBody(&options)
+		if err != nil {
+			return
+		}
+	}
+
+	report, err := h.garbageCollectorService.Collect(options)
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           types2.ErrCodeFailedToCollectGarbage,
+			PublicMessage:  "Failed to collect unused images and volumes.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(report)
+}
+
+// Events streams the changes of the containers. The bulk progress is only
+// streamed for the containers the current user is allowed to see.
 func (h *ContainersHandler) Events(c *router.Context) {
 	eventsChan := make(chan sse.Event)
 	defer close(eventsChan)
 
 	done := c.Request.Context().Done()
 
+	tags, scoped := apptypes.AllowedTags(c)
+	scope := vtypes.Authorization{Allowed: true}
+	if scoped {
+		scope.Tags = tags
+	}
+
 	listener := vtypes.NewTempListener(func(e interface{}) {
-		switch e.(type) {
+		switch e := e.(type) {
 		case types2.EventContainersChange:
 			eventsChan <- sse.Event{
 				Event: types2.EventNameContainersChange,
 			}
+		case types2.EventContainersBulk:
+			if !scope.AllowsTags(e.Tags) {
+				return
+			}
+			eventsChan <- sse.Event{
+				Event: types2.EventNameContainersBulk,
+				Data:  e,
+			}
 		}
 	})
 
@@ -112,3 +298,12 @@ func (h *ContainersHandler) Events(c *router.Context) {
 		}
 	})
 }
+
+// setQueryScope limits the query to the containers that the current user
+// is allowed to see, if its roles are limited to some tags.
+func setQueryScope(c *router.Context, query *types2.ContainerSearchQuery) {
+	tags, scoped := apptypes.AllowedTags(c)
+	if scoped {
+		query.Scope = &tags
+	}
+}
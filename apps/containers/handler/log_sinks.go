@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type LogSinksHandler struct {
+	logForwarderService port.LogForwarderService
+}
+
+func NewLogSinksHandler(logForwarderService port.LogForwarderService) port.LogSinksHandler {
+	return &LogSinksHandler{
+		logForwarderService: logForwarderService,
+	}
+}
+
+// Get returns the log sinks. The values of the headers are masked.
+func (h *LogSinksHandler) Get(c *router.Context) {
+	c.JSON(h.logForwarderService.GetSinks().Redacted())
+}
+
+func (h *LogSinksHandler) Add(c *router.Context) {
+	var sink types.LogSink
+	err := c.ParseBody(&sink)
+	if err != nil {
+		return
+	}
+
+	id, err := h.logForwarderService.AddSink(sink)
+	if errors.Is(err, types.ErrLogSinkTypeInvalid) {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeLogSinkTypeInvalid,
+			PublicMessage:  fmt.Sprintf("The log sink type '%s' is invalid.", sink.Type),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if errors.Is(err, types.ErrLogSinkURLInvalid) {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeLogSinkURLInvalid,
+			PublicMessage:  fmt.Sprintf("The log sink URL '%s' is invalid.", sink.URL),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           types.ErrCodeFailedToAddLogSink,
+			PublicMessage:  "Failed to add the log sink.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(id)
+}
+
+func (h *LogSinksHandler) Remove(c *router.Context) {
+	p := c.Param("sink_uuid")
+	if p == "" {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeLogSinkUuidMissing,
+			PublicMessage:  "The request is missing the log sink UUID.",
+			PrivateMessage: "Field 'sink_uuid' is required.",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(p)
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeLogSinkUuidInvalid,
+			PublicMessage:  "The log sink UUID is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	err = h.logForwarderService.RemoveSink(id)
+	if errors.Is(err, types.ErrLogSinkNotFound) {
+		c.NotFound(router.Error{
+			Code:           types.ErrCodeLogSinkNotFound,
+			PublicMessage:  fmt.Sprintf("The log sink '%s' could not be found.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           types.ErrCodeFailedToRemoveLogSink,
+			PublicMessage:  fmt.Sprintf("Failed to remove the log sink '%s'.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
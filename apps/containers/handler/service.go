@@ -75,6 +75,13 @@ func (h *ServiceHandler) Install(c *router.Context) {
 			PrivateMessage: err.Error(),
 		})
 		return
+	} else if err != nil && errors.Is(err, types2.ErrInvalidHook) {
+		c.BadRequest(router.Error{
+			Code:           types2.ErrCodeInvalidHooks,
+			PublicMessage:  fmt.Sprintf("The hooks of the service '%s' are invalid.", service.Name),
+			PrivateMessage: err.Error(),
+		})
+		return
 	} else if err != nil {
 		c.Abort(router.Error{
 			Code:           types2.ErrCodeFailedToInstallService,
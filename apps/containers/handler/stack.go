@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type StackHandler struct {
+	stackService port.StackService
+}
+
+func NewStackHandler(stackService port.StackService) port.StackHandler {
+	return &StackHandler{
+		stackService: stackService,
+	}
+}
+
+func (h *StackHandler) getParamStackUUID(c *router.Context) *uuid.UUID {
+	p := c.Param("stack_uuid")
+	if p == "" {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeStackUuidMissing,
+			PublicMessage:  "The request was missing the stack UUID.",
+			PrivateMessage: "Field 'stack_uuid' is required.",
+		})
+		return nil
+	}
+
+	id, err := uuid.Parse(p)
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeStackUuidInvalid,
+			PublicMessage:  "The stack UUID is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return nil
+	}
+
+	return &id
+}
+
+func (h *StackHandler) getStack(c *router.Context) *types.Stack {
+	id := h.getParamStackUUID(c)
+	if id == nil {
+		return nil
+	}
+
+	stack, err := h.stackService.Get(*id)
+	if err != nil && errors.Is(err, types.ErrStackNotFound) {
+		c.NotFound(router.Error{
+			Code:           types.ErrCodeStackNotFound,
+			PublicMessage:  fmt.Sprintf("The stack '%s' could not be found.", id),
+			PrivateMessage: err.Error(),
+		})
+		return nil
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           types.ErrCodeFailedToGetStack,
+			PublicMessage:  fmt.Sprintf("Failed to retrieve stack '%s'.", id),
+			PrivateMessage: err.Error(),
+		})
+		return nil
+	}
+
+	return &stack
+}
+
+func (h *StackHandler) Get(c *router.Context) {
+	stack := h.getStack(c)
+	if stack == nil {
+		return
+	}
+	c.JSON(stack)
+}
+
+// Delete deletes the members of the stack, and the stack. The named volumes
+// of the members are deleted too with ?volumes=true.
+func (h *StackHandler) Delete(c *router.Context) {
+	stack := h.getStack(c)
+	if stack == nil {
+		return
+	}
+
+	err := h.stackService.Delete(c.Request.Context(), stack.UUID, c.Query("volumes") == "true")
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           types.ErrCodeFailedToDeleteStack,
+			PublicMessage:  fmt.Sprintf("The stack '%s' could not be deleted.", stack.Definition.Name),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (h *StackHandler) Start(c *router.Context) {
+	h.run(c, types.BulkActionStart)
+}
+
+func (h *StackHandler) Stop(c *router.Context) {
+	h.run(c, types.BulkActionStop)
+}
+
+func (h *StackHandler) Update(c *router.Context) {
+	h.run(c, types.BulkActionUpdate)
+}
+
+// run runs the action on all the members of the stack, and returns the
+// result for each member.
+func (h *StackHandler) run(c *router.Context, action string) {
+	stack := h.getStack(c)
+	if stack == nil {
+		return
+	}
+
+	results, err := h.stackService.Run(c.Request.Context(), stack.UUID, action)
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           types.ErrCodeFailedToRunStackAction,
+			PublicMessage:  fmt.Sprintf("Failed to %s the stack '%s'.", action, stack.Definition.Name),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(results)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gin-contrib/sse"
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	apptypes "github.com/vertex-center/vertex/core/types/app"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type StacksHandler struct {
+	ctx          *apptypes.Context
+	stackService port.StackService
+}
+
+func NewStacksHandler(ctx *apptypes.Context, stackService port.StackService) port.StacksHandler {
+	return &StacksHandler{
+		ctx:          ctx,
+		stackService: stackService,
+	}
+}
+
+// Get returns all the stacks, sorted by name. The roles limited to some tags
+// only get the stacks whose members all have one of these tags.
+func (h *StacksHandler) Get(c *router.Context) {
+	scope := h.scope(c)
+
+	stacks := []types.Stack{}
+	for _, stack := range h.stackService.GetAll() {
+		if h.allows(scope, stack.UUID) {
+			stacks = append(stacks, stack)
+		}
+	}
+	c.JSON(stacks)
+}
+
+// Install installs all the members of a stack. Nothing is kept if one of
+// the members fails to install.
+func (h *StacksHandler) Install(c *router.Context) {
+	var definition types.StackDefinition
+	err := c.ParseBody(&definition)
+	if err != nil {
+		return
+	}
+
+	stack, err := h.stackService.Install(c.Request.Context(), definition)
+	if err != nil && errors.Is(err, types.ErrInvalidStack) {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeInvalidStack,
+			PublicMessage:  "The stack definition is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil && errors.Is(err, types.ErrServiceNotFound) {
+		c.NotFound(router.Error{
+			Code:           types.ErrCodeServiceNotFound,
+			PublicMessage:  "A service of the stack could not be found.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           types.ErrCodeFailedToInstallStack,
+			PublicMessage:  fmt.Sprintf("Failed to install the stack '%s'.", definition.Name),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(stack)
+}
+
+func (h *StacksHandler) Events(c *router.Context) {
+	eventsChan := make(chan sse.Event)
+	defer close(eventsChan)
+
+	done := c.Request.Context().Done()
+
+	scope := h.scope(c)
+
+	listener := vtypes.NewTempListener(func(e interface{}) {
+		switch e := e.(type) {
+		case types.EventStacksChange:
+			eventsChan <- sse.Event{
+				Event: types.EventNameStacksChange,
+			}
+		case types.EventStackStatusChange:
+			if !h.allows(scope, e.StackUUID) {
+				return
+			}
+			eventsChan <- sse.Event{
+				Event: types.EventNameStackStatusChange,
+				Data:  e,
+			}
+		}
+	})
+
+	h.ctx.AddListener(listener)
+	defer h.ctx.RemoveListener(listener)
+
+	first := true
+
+	c.Stream(func(w io.Writer) bool {
+		if first {
+			err := sse.Encode(w, sse.Event{
+				Event: "open",
+			})
+
+			if err != nil {
+				log.Error(err)
+				return false
+			}
+			first = false
+			return true
+		}
+
+		select {
+		case e := <-eventsChan:
+			err := sse.Encode(w, e)
+			if err != nil {
+				log.Error(err)
+			}
+			return true
+		case <-done:
+			return false
+		}
+	})
+}
+
+// scope returns the authorization of the current user, limited to the tags
+// set by RequireFiltered.
+func (h *StacksHandler) scope(c *router.Context) vtypes.Authorization {
+	scope := vtypes.Authorization{Allowed: true}
+	if tags, scoped := apptypes.AllowedTags(c); scoped {
+		scope.Tags = tags
+	}
+	return scope
+}
+
+func (h *StacksHandler) allows(scope vtypes.Authorization, id uuid.UUID) bool {
+	if scope.Tags == nil {
+		return true
+	}
+	tags, err := h.stackService.Tags(id)
+	return err == nil && scope.AllowsTags(tags)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/vertex-center/vertex/apps/monitoring/core/port"
 	"github.com/vertex-center/vertex/apps/monitoring/core/service"
 	"github.com/vertex-center/vertex/apps/monitoring/handler"
+	vtypes "github.com/vertex-center/vertex/core/types"
 	apptypes "github.com/vertex-center/vertex/core/types/app"
 	"github.com/vertex-center/vertex/pkg/router"
 )
@@ -44,9 +45,9 @@ func (a *App) Initialize(app *apptypes.App) error {
 	app.RegisterRoutes(AppRoute, func(r *router.Group) {
 		metricsHandler := handler.NewMetricsHandler(metricsService)
 
-		r.GET("/metrics", metricsHandler.Get)
-		r.POST("/collector/:collector/install", metricsHandler.InstallCollector)
-		r.POST("/visualizer/:visualizer/install", metricsHandler.InstallVisualizer)
+		r.GET("/metrics", app.Require(vtypes.PermissionMonitoringRead, nil), metricsHandler.Get)
+		r.POST("/collector/:collector/install", app.Require(vtypes.PermissionMonitoringWrite, nil), metricsHandler.InstallCollector)
+		r.POST("/visualizer/:visualizer/install", app.Require(vtypes.PermissionMonitoringWrite, nil), metricsHandler.InstallVisualizer)
 	})
 
 	return nil
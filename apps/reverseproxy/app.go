@@ -5,6 +5,7 @@ import (
 	"github.com/vertex-center/vertex/apps/reverseproxy/core/port"
 	"github.com/vertex-center/vertex/apps/reverseproxy/core/service"
 	"github.com/vertex-center/vertex/apps/reverseproxy/handler"
+	vtypes "github.com/vertex-center/vertex/core/types"
 	apptypes "github.com/vertex-center/vertex/core/types/app"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/router"
@@ -34,7 +35,7 @@ func (a *App) Initialize(app *apptypes.App) error {
 
 	proxyFSAdapter = adapter.NewProxyFSAdapter(nil)
 
-	proxyService = service.NewProxyService(proxyFSAdapter)
+	proxyService = service.NewProxyService(app.Context(), proxyFSAdapter)
 
 	a.proxy = NewProxyRouter(proxyService)
 
@@ -54,9 +55,9 @@ func (a *App) Initialize(app *apptypes.App) error {
 
 	app.RegisterRoutes(AppRoute, func(r *router.Group) {
 		proxyHandler := handler.NewProxyHandler(proxyService)
-		r.GET("/redirects", proxyHandler.GetRedirects)
-		r.POST("/redirect", proxyHandler.AddRedirect)
-		r.DELETE("/redirect/:id", proxyHandler.RemoveRedirect)
+		r.GET("/redirects", app.Require(vtypes.PermissionProxyRead, nil), proxyHandler.GetRedirects)
+		r.POST("/redirect", app.Require(vtypes.PermissionProxyWrite, nil), proxyHandler.AddRedirect)
+		r.DELETE("/redirect/:id", app.Require(vtypes.PermissionProxyWrite, nil), proxyHandler.RemoveRedirect)
 	})
 
 	return nil
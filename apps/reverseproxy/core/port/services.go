@@ -1,6 +1,8 @@
 package port
 
 import (
+	"net/url"
+
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/reverseproxy/core/types"
 )
@@ -9,6 +11,7 @@ type (
 	ProxyService interface {
 		GetRedirects() types.ProxyRedirects
 		GetRedirectByHost(host string) *types.ProxyRedirect
+		GetTargets(redirect types.ProxyRedirect) ([]*url.URL, error)
 		AddRedirect(redirect types.ProxyRedirect) error
 		RemoveRedirect(id uuid.UUID) error
 	}
@@ -1,20 +1,35 @@
 package service
 
 import (
+	"net/url"
+	"sync"
+
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
 	"github.com/vertex-center/vertex/apps/reverseproxy/core/port"
 	"github.com/vertex-center/vertex/apps/reverseproxy/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
 
 	"github.com/google/uuid"
 )
 
 type ProxyService struct {
+	uuid         uuid.UUID
 	proxyAdapter port.ProxyAdapter
+
+	// containers are the running containers, used to load-balance the
+	// redirects across their replicas.
+	containers      map[uuid.UUID]containerstypes.Container
+	containersMutex sync.RWMutex
 }
 
-func NewProxyService(proxyAdapter port.ProxyAdapter) port.ProxyService {
-	return &ProxyService{
+func NewProxyService(ctx *app.Context, proxyAdapter port.ProxyAdapter) port.ProxyService {
+	s := &ProxyService{
+		uuid:         uuid.New(),
 		proxyAdapter: proxyAdapter,
+		containers:   map[uuid.UUID]containerstypes.Container{},
 	}
+	ctx.AddListener(s)
+	return s
 }
 
 func (s *ProxyService) GetRedirects() types.ProxyRedirects {
@@ -25,6 +40,30 @@ func (s *ProxyService) GetRedirectByHost(host string) *types.ProxyRedirect {
 	return s.proxyAdapter.GetRedirectByHost(host)
 }
 
+// GetTargets returns the URLs the redirect can be sent to. If the redirect
+// targets a port published by a running container with replicas, there is
+// one URL for each replica. Otherwise, it is the target of the redirect.
+func (s *ProxyService) GetTargets(redirect types.ProxyRedirect) ([]*url.URL, error) {
+	target, err := url.Parse(redirect.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	s.containersMutex.RLock()
+	defer s.containersMutex.RUnlock()
+
+	for _, inst := range s.containers {
+		if inst.GetReplicas() <= 1 {
+			continue
+		}
+		if targets := inst.ReplicaTargets(target); targets != nil {
+			return targets, nil
+		}
+	}
+
+	return []*url.URL{target}, nil
+}
+
 func (s *ProxyService) AddRedirect(redirect types.ProxyRedirect) error {
 	id := uuid.New()
 
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+)
+
+func (s *ProxyService) GetUUID() uuid.UUID {
+	return s.uuid
+}
+
+func (s *ProxyService) OnEvent(e interface{}) {
+	switch e := e.(type) {
+	case containerstypes.EventContainerStatusChange:
+		s.containersMutex.Lock()
+		defer s.containersMutex.Unlock()
+
+		if e.Status == containerstypes.ContainerStatusRunning {
+			s.containers[e.ContainerUUID] = e.Container
+		} else if e.Status == containerstypes.ContainerStatusOff || e.Status == containerstypes.ContainerStatusError {
+			delete(s.containers, e.ContainerUUID)
+		}
+	}
+}
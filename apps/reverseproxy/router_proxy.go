@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httputil"
-	"net/url"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -23,6 +23,10 @@ type ProxyRouter struct {
 	*router.Router
 
 	proxyService port.ProxyService
+
+	// requests counts the requests, to load-balance them across the
+	// targets of a redirect.
+	requests atomic.Uint64
 }
 
 func NewProxyRouter(proxyService port.ProxyService) *ProxyRouter {
@@ -69,11 +73,12 @@ func (r *ProxyRouter) HandleProxy(c *router.Context) {
 		return
 	}
 
-	target, err := url.Parse(redirect.Target)
+	targets, err := r.proxyService.GetTargets(*redirect)
 	if err != nil {
 		log.Error(err)
 		return
 	}
+	target := targets[r.requests.Add(1)%uint64(len(targets))]
 
 	proxy := httputil.NewSingleHostReverseProxy(target)
 	proxy.ErrorHandler = func(w http.ResponseWriter, request *http.Request, err error) {
@@ -4,6 +4,7 @@ import (
 	"github.com/vertex-center/vertex/apps/sql/core/port"
 	"github.com/vertex-center/vertex/apps/sql/core/service"
 	"github.com/vertex-center/vertex/apps/sql/handler"
+	vtypes "github.com/vertex-center/vertex/core/types"
 	apptypes "github.com/vertex-center/vertex/core/types/app"
 	"github.com/vertex-center/vertex/pkg/router"
 )
@@ -40,8 +41,8 @@ func (a *App) Initialize(app *apptypes.App) error {
 
 	app.RegisterRoutes(AppRoute, func(r *router.Group) {
 		dbmsHandler := handler.NewDBMSHandler(sqlService)
-		r.GET("/container/:container_uuid", dbmsHandler.Get)
-		r.POST("/dbms/:dbms/install", dbmsHandler.Install)
+		r.GET("/container/:container_uuid", app.Require(vtypes.PermissionSqlRead, nil), dbmsHandler.Get)
+		r.POST("/dbms/:dbms/install", app.Require(vtypes.PermissionSqlWrite, nil), dbmsHandler.Install)
 	})
 
 	return nil
@@ -2,6 +2,7 @@ package tunnels
 
 import (
 	"github.com/vertex-center/vertex/apps/tunnels/handler"
+	vtypes "github.com/vertex-center/vertex/core/types"
 	apptypes "github.com/vertex-center/vertex/core/types/app"
 	"github.com/vertex-center/vertex/pkg/router"
 )
@@ -30,7 +31,7 @@ func (a *App) Initialize(app *apptypes.App) error {
 
 	app.RegisterRoutes(AppRoute, func(r *router.Group) {
 		providerHandler := handler.NewProviderHandler()
-		r.POST("/provider/:provider/install", providerHandler.Install)
+		r.POST("/provider/:provider/install", app.Require(vtypes.PermissionTunnelsWrite, nil), providerHandler.Install)
 	})
 
 	return nil
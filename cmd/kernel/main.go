@@ -2,20 +2,29 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	adapter2 "github.com/vertex-center/vertex/adapter"
 	"github.com/vertex-center/vertex/core/port"
 	service "github.com/vertex-center/vertex/core/service"
+	"github.com/vertex-center/vertex/core/types"
+	apptypes "github.com/vertex-center/vertex/core/types/app"
 	"github.com/vertex-center/vertex/handler"
 	"github.com/vertex-center/vertex/pkg/ginutils"
 	"github.com/vertex-center/vertex/pkg/router"
 	"github.com/vertex-center/vlog"
+	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
 	"os/user"
+	"path"
+	"runtime"
 	"strconv"
 	"syscall"
 	"time"
@@ -29,9 +38,12 @@ var (
 
 	dockerCliAdapter port.DockerAdapter
 	sshAdapter       port.SshAdapter
+	auditFSAdapter   port.AuditAdapter
+	policyFSAdapter  port.KernelPolicyAdapter
 
 	dockerService port.DockerService
 	sshService    port.SshService
+	auditService  port.AuditService
 )
 
 func main() {
@@ -46,6 +58,7 @@ func main() {
 	}
 
 	allowPortsManagement()
+	initSecret()
 
 	shutdownChan := make(chan os.Signal, 1)
 
@@ -75,6 +88,7 @@ func main() {
 			"-port-kernel", config.KernelCurrent.PortKernel,
 			"-port-proxy", config.KernelCurrent.PortProxy,
 			"-port-prometheus", config.KernelCurrent.PortPrometheus,
+			"-kernel-socket", config.KernelCurrent.KernelSocket,
 		}...)
 		if err != nil {
 			log.Error(err)
@@ -118,6 +132,9 @@ func parseArgs() {
 		flagPortKernel     = flag.String("port-kernel", config.Current.PortKernel, "The Vertex Kernel port")
 		flagPortProxy      = flag.String("port-proxy", config.Current.PortProxy, "The Vertex Proxy port")
 		flagPortPrometheus = flag.String("port-prometheus", config.Current.PortPrometheus, "The Prometheus port")
+
+		flagKernelSocket = flag.String("kernel-socket", config.DefaultKernelSocket(), "The Vertex Kernel unix socket. Empty to disable")
+		flagKernelTCP    = flag.Bool("kernel-tcp", false, "Also serve the Vertex Kernel on the kernel port")
 	)
 
 	flag.Parse()
@@ -127,6 +144,8 @@ func parseArgs() {
 	config.KernelCurrent.PortKernel = *flagPortKernel
 	config.KernelCurrent.PortProxy = *flagPortProxy
 	config.KernelCurrent.PortPrometheus = *flagPortPrometheus
+	config.KernelCurrent.KernelSocket = *flagKernelSocket
+	config.KernelCurrent.ListenTCP = *flagKernelTCP
 
 	if *flagUsername != "" {
 		u, err := user.Lookup(*flagUsername)
@@ -156,6 +175,23 @@ func parseArgs() {
 	config.KernelCurrent.Gid = uint32(*flagGID)
 }
 
+// initSecret generates the secret shared with the Vertex process. It is
+// kept if it was already set through the environment, for example when
+// running Vertex separately.
+func initSecret() {
+	if config.KernelCurrent.KernelSecret != "" {
+		return
+	}
+
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+	config.KernelCurrent.KernelSecret = hex.EncodeToString(secret)
+}
+
 func buildVertex() {
 	log.Info("Building vertex")
 	cmd := exec.Command("go", "build", "-o", "vertex", "cmd/main/main.go")
@@ -171,14 +207,31 @@ func buildVertex() {
 func initAdapters() {
 	dockerCliAdapter = adapter2.NewDockerCliAdapter()
 	sshAdapter = adapter2.NewSshFsAdapter(nil)
+	policyFSAdapter = adapter2.NewKernelPolicyFSAdapter(nil)
+	auditFSAdapter = adapter2.NewAuditFSAdapter(&adapter2.AuditFSAdapterParams{
+		Source: types.AuditSourceKernel,
+		Chown:  true,
+		Uid:    config.KernelCurrent.Uid,
+		Gid:    config.KernelCurrent.Gid,
+	})
 }
 
 func initServices() {
-	dockerService = service.NewDockerKernelService(dockerCliAdapter)
+	dockerService = service.NewDockerKernelService(dockerCliAdapter, policyFSAdapter)
 	sshService = service.NewSshKernelService(sshAdapter)
+	auditService = service.NewAuditService(service.AuditServiceParams{
+		AuditAdapter: auditFSAdapter,
+		Source:       types.AuditSourceKernel,
+	})
 }
 
 func initRoutes() {
+	// The audit is before the authentication, to record the requests that
+	// are rejected.
+	auditHandler := handler.NewAuditHandler(auditService)
+	r.UseHandlers(auditHandler.Record)
+	r.Use(ginutils.BearerAuth(config.KernelCurrent.KernelSecret))
+
 	api := r.Group("/api")
 
 	dockerHandler := handler.NewDockerKernelHandler(dockerService)
@@ -192,9 +245,23 @@ func initRoutes() {
 	docker.GET("/container/:id/logs/stdout", dockerHandler.LogsStdoutContainer)
 	docker.GET("/container/:id/logs/stderr", dockerHandler.LogsStderrContainer)
 	docker.GET("/container/:id/wait/:cond", dockerHandler.WaitContainer)
+	docker.POST("/container/:id/exec", dockerHandler.ExecContainer)
+	docker.GET("/images", dockerHandler.ListImages)
+	docker.DELETE("/image/:id", dockerHandler.DeleteImage)
 	docker.GET("/image/:id/info", dockerHandler.InfoImage)
 	docker.POST("/image/pull", dockerHandler.PullImage)
 	docker.POST("/image/build", dockerHandler.BuildImage)
+	docker.POST("/build-cache/prune", dockerHandler.PruneBuildCache)
+	docker.GET("/events", apptypes.HeadersSSE, dockerHandler.Events)
+	docker.GET("/volumes", dockerHandler.ListVolumes)
+	docker.POST("/volume", dockerHandler.CreateVolume)
+	docker.GET("/volume/:name", dockerHandler.InspectVolume)
+	docker.DELETE("/volume/:name", dockerHandler.DeleteVolume)
+	docker.GET("/networks", dockerHandler.ListNetworks)
+	docker.POST("/network", dockerHandler.CreateNetwork)
+	docker.DELETE("/network/:id", dockerHandler.DeleteNetwork)
+	docker.POST("/network/:id/connect", dockerHandler.ConnectNetwork)
+	docker.POST("/network/:id/disconnect", dockerHandler.DisconnectNetwork)
 
 	sshHandler := handler.NewSshKernelHandler(sshService)
 	ssh := api.Group("/security/ssh")
@@ -204,16 +271,63 @@ func initRoutes() {
 }
 
 func startRouter() {
-	log.Info("vertex-kernel started", vlog.String("url", config.KernelCurrent.KernelURL()))
-	addr := fmt.Sprintf(":%s", config.KernelCurrent.PortKernel)
+	errs := make(chan error, 2)
+	listening := false
 
-	err := r.Start(addr)
-	if err != nil {
+	if config.KernelCurrent.KernelSocket != "" {
+		l, err := listenSocket(config.KernelCurrent.KernelSocket)
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+		go func() { errs <- r.Serve(l) }()
+		listening = true
+		log.Info("vertex-kernel started", vlog.String("socket", config.KernelCurrent.KernelSocket))
+	}
+
+	if config.KernelCurrent.ListenTCP || !listening {
+		addr := fmt.Sprintf(":%s", config.KernelCurrent.PortKernel)
+		go func() { errs <- r.Start(addr) }()
+		log.Info("vertex-kernel started", vlog.String("url", config.KernelCurrent.KernelURL()))
+	}
+
+	err := <-errs
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Error(err)
 		os.Exit(1)
 	}
 }
 
+// listenSocket listens on the unix socket at the given path. The socket is
+// only accessible by root and the unprivileged user running Vertex.
+func listenSocket(socket string) (net.Listener, error) {
+	err := os.MkdirAll(path.Dir(socket), os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the socket left by a previous run.
+	err = os.Remove(socket)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.Chmod(socket, 0660)
+	if err == nil && runtime.GOOS != "windows" {
+		err = os.Chown(socket, int(config.KernelCurrent.Uid), int(config.KernelCurrent.Gid))
+	}
+	if err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
 func stopRouter() {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
@@ -23,6 +23,7 @@ func runVertex(args ...string) (*exec.Cmd, error) {
 	cmd := exec.Command("./vertex", args...)
 	cmd.SysProcAttr = &syscall.SysProcAttr{}
 	cmd.SysProcAttr.Credential = &syscall.Credential{Uid: uid, Gid: gid}
+	cmd.Env = append(os.Environ(), config.KernelSecretEnv+"="+config.KernelCurrent.KernelSecret)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
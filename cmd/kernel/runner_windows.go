@@ -5,10 +5,13 @@ package main
 import (
 	"os"
 	"os/exec"
+
+	"github.com/vertex-center/vertex/config"
 )
 
 func runVertex(args ...string) (*exec.Cmd, error) {
 	cmd := exec.Command("vertex.exe", args...)
+	cmd.Env = append(os.Environ(), config.KernelSecretEnv+"="+config.KernelCurrent.KernelSecret)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
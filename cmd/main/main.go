@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"flag"
 	"fmt"
@@ -51,6 +53,11 @@ var (
 	settingsFSAdapter   port.SettingsAdapter
 	sshKernelApiAdapter port.SshAdapter
 	baselinesApiAdapter port.BaselinesAdapter
+	userFSAdapter       port.UserAdapter
+	sessionFSAdapter    port.SessionAdapter
+	roleFSAdapter       port.RoleAdapter
+	tokenFSAdapter      port.TokenAdapter
+	auditFSAdapter      port.AuditAdapter
 
 	appsService          port.AppsService
 	notificationsService service.NotificationsService
@@ -58,6 +65,14 @@ var (
 	settingsService      port.SettingsService
 	sshService           port.SshService
 	updateService        port.UpdateService
+	authService          port.AuthService
+	userService          port.UserService
+	roleService          port.RoleService
+	tokenService         port.TokenService
+	auditService         port.AuditService
+
+	authHandler  port.AuthHandler
+	auditHandler port.AuditHandler
 )
 
 func main() {
@@ -72,12 +87,23 @@ func main() {
 
 	parseArgs()
 
+	// The kernel secret is already loaded in the config, so it doesn't
+	// need to be visible to other processes anymore.
+	_ = os.Unsetenv(config.KernelSecretEnv)
+
 	checkNotRoot()
+	initInternalToken()
 
 	gin.SetMode(gin.ReleaseMode)
 	ctx = types.NewVertexContext()
 	r = router.New()
-	r.Use(cors.Default())
+	r.Use(cors.New(cors.Config{
+		AllowOrigins:     []string{config.Current.VertexURL()},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
+		AllowCredentials: true,
+		MaxAge:           12 * time.Hour,
+	}))
 	r.Use(ginutils.ErrorHandler())
 	r.Use(ginutils.Logger("MAIN"))
 	r.Use(gin.Recovery())
@@ -139,6 +165,14 @@ func parseArgs() {
 		flagPortKernel     = flag.String("port-kernel", config.Current.PortKernel, "The Vertex Kernel port")
 		flagPortProxy      = flag.String("port-proxy", config.Current.PortProxy, "The Vertex Proxy port")
 		flagPortPrometheus = flag.String("port-prometheus", config.Current.PortPrometheus, "The Prometheus port")
+
+		flagKernelSocket = flag.String("kernel-socket", config.DefaultKernelSocket(), "The Vertex Kernel unix socket. If empty, the kernel port is used")
+
+		flagTimeoutKernel = flag.Duration("timeout-kernel", config.Current.Timeouts.Kernel, "The timeout of the requests sent to the kernel")
+		flagTimeoutImage  = flag.Duration("timeout-image", config.Current.Timeouts.Image, "The timeout to pull or build an image")
+		flagTimeoutStop   = flag.Duration("timeout-stop", config.Current.Timeouts.Stop, "The time given to containers to stop before they are killed")
+		flagTimeoutHook   = flag.Duration("timeout-hook", config.Current.Timeouts.Hook, "The time given to the lifecycle hooks of the containers")
+		flagTimeoutJob    = flag.Duration("timeout-job", config.Current.Timeouts.Job, "The time given to the scheduled jobs before they are stopped")
 	)
 
 	flag.Parse()
@@ -160,6 +194,24 @@ func parseArgs() {
 	config.Current.PortKernel = *flagPortKernel
 	config.Current.PortProxy = *flagPortProxy
 	config.Current.PortPrometheus = *flagPortPrometheus
+	config.Current.KernelSocket = *flagKernelSocket
+	config.Current.Timeouts.Kernel = *flagTimeoutKernel
+	config.Current.Timeouts.Image = *flagTimeoutImage
+	config.Current.Timeouts.Stop = *flagTimeoutStop
+	config.Current.Timeouts.Hook = *flagTimeoutHook
+	config.Current.Timeouts.Job = *flagTimeoutJob
+}
+
+// initInternalToken generates the token used by Vertex to authenticate the
+// requests sent to its own API.
+func initInternalToken() {
+	token := make([]byte, 32)
+	_, err := rand.Read(token)
+	if err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+	config.Current.InternalToken = hex.EncodeToString(token)
 }
 
 func checkNotRoot() {
@@ -172,6 +224,11 @@ func initAdapters() {
 	settingsFSAdapter = adapter2.NewSettingsFSAdapter(nil)
 	sshKernelApiAdapter = adapter2.NewSshKernelApiAdapter()
 	baselinesApiAdapter = adapter2.NewBaselinesApiAdapter()
+	userFSAdapter = adapter2.NewUserFSAdapter(nil)
+	sessionFSAdapter = adapter2.NewSessionFSAdapter(nil)
+	roleFSAdapter = adapter2.NewRoleFSAdapter(nil)
+	tokenFSAdapter = adapter2.NewTokenFSAdapter(nil)
+	auditFSAdapter = adapter2.NewAuditFSAdapter(nil)
 }
 
 func initServices(about types.About) {
@@ -182,7 +239,34 @@ func initServices(about types.About) {
 		updates.NewVertexClientUpdater(path.Join(storage.Path, "client")),
 		updates.NewRepositoryUpdater("vertex_services", path.Join(storage.Path, "services"), "vertex-center", "vertex-services"),
 	})
-	appsService = service.NewAppsService(ctx, r,
+	authService = service.NewAuthService(service.AuthServiceParams{
+		UserAdapter:    userFSAdapter,
+		SessionAdapter: sessionFSAdapter,
+		TokenAdapter:   tokenFSAdapter,
+		InternalToken:  config.Current.InternalToken,
+	})
+	userService = service.NewUserService(service.UserServiceParams{
+		UserAdapter:    userFSAdapter,
+		SessionAdapter: sessionFSAdapter,
+		RoleAdapter:    roleFSAdapter,
+		TokenAdapter:   tokenFSAdapter,
+	})
+	roleService = service.NewRoleService(service.RoleServiceParams{
+		RoleAdapter: roleFSAdapter,
+		UserAdapter: userFSAdapter,
+	})
+	tokenService = service.NewTokenService(service.TokenServiceParams{
+		TokenAdapter: tokenFSAdapter,
+	})
+	auditService = service.NewAuditService(service.AuditServiceParams{
+		Ctx:          ctx,
+		AuditAdapter: auditFSAdapter,
+	})
+	auditHandler = handler.NewAuditHandler(auditService)
+	// The apps declare the permissions of their routes with the auth handler.
+	authHandler = handler.NewAuthHandler(authService, roleService)
+
+	appsService = service.NewAppsService(ctx, r, authHandler,
 		[]app.Interface{
 			sql.NewApp(),
 			tunnels.NewApp(),
@@ -199,6 +283,8 @@ func initServices(about types.About) {
 }
 
 func initRoutes(about types.About) {
+	r.UseHandlers(auditHandler.Record, authHandler.Authenticate)
+
 	r.NoRoute(func(c *gin.Context) {
 		c.JSON(http.StatusNotFound, router.Error{
 			Code:          "resource_not_found",
@@ -212,7 +298,7 @@ func initRoutes(about types.About) {
 	})
 
 	if config.Current.Debug() {
-		api.POST("/hard-reset", func(c *router.Context) {
+		api.POST("/hard-reset", authHandler.Require(types.PermissionAll, nil), func(c *router.Context) {
 			ctx.DispatchEvent(types.EventServerHardReset{})
 			c.OK()
 		})
@@ -228,19 +314,53 @@ func initRoutes(about types.About) {
 
 	updateHandler := handler.NewUpdateHandler(updateService, settingsService)
 	update := api.Group("/update")
-	update.GET("", updateHandler.Get)
-	update.POST("", updateHandler.Install)
+	update.GET("", authHandler.Require(types.PermissionUpdatesRead, nil), updateHandler.Get)
+	update.POST("", authHandler.Require(types.PermissionUpdatesWrite, nil), updateHandler.Install)
 
 	settingsHandler := handler.NewSettingsHandler(settingsService)
 	settings := api.Group("/settings")
-	settings.GET("", settingsHandler.Get)
-	settings.PATCH("", settingsHandler.Patch)
+	settings.GET("", authHandler.Require(types.PermissionSettingsRead, nil), settingsHandler.Get)
+	settings.PATCH("", authHandler.Require(types.PermissionSettingsWrite, nil), settingsHandler.Patch)
 
 	sshHandler := handler.NewSshHandler(sshService)
 	ssh := api.Group("/security/ssh")
-	ssh.GET("", sshHandler.Get)
-	ssh.POST("", sshHandler.Add)
-	ssh.DELETE("/:fingerprint", sshHandler.Delete)
+	ssh.GET("", authHandler.Require(types.PermissionSshRead, nil), sshHandler.Get)
+	ssh.POST("", authHandler.Require(types.PermissionSshWrite, nil), sshHandler.Add)
+	ssh.DELETE("/:fingerprint", authHandler.Require(types.PermissionSshWrite, nil), sshHandler.Delete)
+
+	tokenHandler := handler.NewTokenHandler(tokenService)
+	tokens := api.Group("/security/tokens")
+	tokens.GET("", tokenHandler.Get)
+	tokens.POST("", tokenHandler.Create)
+	tokens.DELETE("/:token_uuid", tokenHandler.Delete)
+
+	api.GET("/audit", authHandler.Require(types.PermissionAuditRead, nil), auditHandler.Get)
+
+	auth := api.Group("/auth")
+	auth.GET("/setup", authHandler.GetSetup)
+	auth.POST("/setup", authHandler.Setup)
+	auth.POST("/login", authHandler.Login)
+	auth.POST("/logout", authHandler.Logout)
+	auth.GET("/me", authHandler.Me)
+
+	userHandler := handler.NewUserHandler(userService)
+	users := api.Group("/users")
+	users.GET("", userHandler.Get)
+
+	user := api.Group("/user")
+	user.POST("", userHandler.Create)
+	user.DELETE("/:user_uuid", userHandler.Delete)
+	user.PUT("/:user_uuid/password", userHandler.SetPassword)
+	user.PUT("/:user_uuid/roles", userHandler.SetRoles)
+
+	roleHandler := handler.NewRoleHandler(roleService)
+	roles := api.Group("/roles")
+	roles.GET("", roleHandler.Get)
+	roles.GET("/permissions", roleHandler.GetPermissions)
+
+	role := api.Group("/role/:role_name")
+	role.PUT("", roleHandler.Set)
+	role.DELETE("", roleHandler.Delete)
 }
 
 func startRouter() {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"time"
 
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/net"
@@ -12,6 +13,10 @@ import (
 
 const urlFormat = "http://%s:%s"
 
+// KernelSecretEnv is the environment variable used by the kernel to pass
+// its secret to the Vertex process.
+const KernelSecretEnv = "VERTEX_KERNEL_SECRET"
+
 var Current = New()
 
 type Mode string
@@ -30,6 +35,40 @@ type Config struct {
 	PortKernel     string `json:"port_kernel"`
 	PortProxy      string `json:"port_proxy"`
 	PortPrometheus string `json:"port_prometheus"`
+
+	// KernelSocket is the path of the unix socket of the kernel. If empty,
+	// the kernel is reached over TCP on PortKernel.
+	KernelSocket string `json:"kernel_socket"`
+
+	// KernelSecret authenticates the requests sent to the kernel.
+	KernelSecret string `json:"-"`
+
+	// InternalToken authenticates the requests sent by Vertex to its own
+	// API. It is generated at startup.
+	InternalToken string `json:"-"`
+
+	Timeouts Timeouts `json:"timeouts"`
+}
+
+// Timeouts are the maximum durations of the operations sent to the kernel.
+type Timeouts struct {
+	// Kernel is the timeout of the short requests, like creating or
+	// inspecting a container.
+	Kernel time.Duration `json:"kernel"`
+
+	// Image is the timeout to pull or build an image.
+	Image time.Duration `json:"image"`
+
+	// Stop is the time given to a container to stop gracefully before it
+	// is killed, if its service doesn't set one.
+	Stop time.Duration `json:"stop"`
+
+	// Hook is the time a lifecycle hook can run, if the hook doesn't set
+	// one.
+	Hook time.Duration `json:"hook"`
+
+	// Job is the time a scheduled job can run before it is stopped.
+	Job time.Duration `json:"job"`
 }
 
 func New() Config {
@@ -48,6 +87,16 @@ func New() Config {
 		PortKernel:     "6131",
 		PortProxy:      "80",
 		PortPrometheus: "2112",
+
+		KernelSecret: os.Getenv(KernelSecretEnv),
+
+		Timeouts: Timeouts{
+			Kernel: 30 * time.Second,
+			Image:  30 * time.Minute,
+			Stop:   10 * time.Second,
+			Hook:   time.Minute,
+			Job:    12 * time.Hour,
+		},
 	}
 
 	if os.Getenv("DEBUG") == "1" {
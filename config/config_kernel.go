@@ -1,5 +1,12 @@
 package config
 
+import (
+	"path"
+	"runtime"
+
+	"github.com/vertex-center/vertex/pkg/storage"
+)
+
 var KernelCurrent = NewKernel()
 
 type Kernel struct {
@@ -7,6 +14,18 @@ type Kernel struct {
 
 	Uid uint32 `json:"uid"`
 	Gid uint32 `json:"gid"`
+
+	// ListenTCP makes the kernel also listen on PortKernel.
+	ListenTCP bool `json:"listen_tcp"`
+}
+
+// DefaultKernelSocket returns the default path of the kernel socket. Unix
+// sockets are not used by default on Windows.
+func DefaultKernelSocket() string {
+	if runtime.GOOS == "windows" {
+		return ""
+	}
+	return path.Join(storage.Path, "kernel.sock")
 }
 
 func NewKernel() Kernel {
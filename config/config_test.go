@@ -26,3 +26,10 @@ func (suite *ConfigTestSuite) TestNewDebug() {
 
 	suite.Equal(DebugMode, cfg.mode)
 }
+
+func (suite *ConfigTestSuite) TestNewKernelSecret() {
+	suite.T().Setenv(KernelSecretEnv, "secret")
+	cfg := New()
+
+	suite.Equal("secret", cfg.KernelSecret)
+}
@@ -3,6 +3,7 @@ package port
 import (
 	"context"
 	types2 "github.com/docker/docker/api/types"
+	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/core/types"
 	"io"
 )
@@ -15,18 +16,32 @@ type (
 	}
 
 	DockerAdapter interface {
-		ListContainers() ([]types.Container, error)
-		DeleteContainer(id string) error
-		CreateContainer(options types.CreateContainerOptions) (types.CreateContainerResponse, error)
-		StartContainer(id string) error
-		StopContainer(id string) error
-		InfoContainer(id string) (types.InfoContainerResponse, error)
-		LogsStdoutContainer(id string) (io.ReadCloser, error)
-		LogsStderrContainer(id string) (io.ReadCloser, error)
-		WaitContainer(id string, cond types.WaitContainerCondition) error
-		InfoImage(id string) (types.InfoImageResponse, error)
-		PullImage(options types.PullImageOptions) (io.ReadCloser, error)
-		BuildImage(options types.BuildImageOptions) (types2.ImageBuildResponse, error)
+		ListContainers(ctx context.Context) ([]types.Container, error)
+		DeleteContainer(ctx context.Context, id string) error
+		CreateContainer(ctx context.Context, options types.CreateContainerOptions) (types.CreateContainerResponse, error)
+		StartContainer(ctx context.Context, id string) error
+		StopContainer(ctx context.Context, id string, options types.StopContainerOptions) error
+		InfoContainer(ctx context.Context, id string) (types.InfoContainerResponse, error)
+		LogsStdoutContainer(ctx context.Context, id string, options types.LogsContainerOptions) (io.ReadCloser, error)
+		LogsStderrContainer(ctx context.Context, id string, options types.LogsContainerOptions) (io.ReadCloser, error)
+		WaitContainer(ctx context.Context, id string, cond types.WaitContainerCondition) error
+		ExecContainer(ctx context.Context, id string, options types.ExecContainerOptions) (types.ExecContainerResponse, error)
+		ListImages(ctx context.Context) ([]types.Image, error)
+		DeleteImage(ctx context.Context, id string) error
+		InfoImage(ctx context.Context, id string) (types.InfoImageResponse, error)
+		PullImage(ctx context.Context, options types.PullImageOptions) (io.ReadCloser, error)
+		BuildImage(ctx context.Context, options types.BuildImageOptions) (types2.ImageBuildResponse, error)
+		PruneBuildCache(ctx context.Context) (types.PruneBuildCacheResponse, error)
+		ListVolumes(ctx context.Context) ([]types.Volume, error)
+		CreateVolume(ctx context.Context, options types.CreateVolumeOptions) (types.Volume, error)
+		InspectVolume(ctx context.Context, name string) (types.Volume, error)
+		DeleteVolume(ctx context.Context, name string) error
+		Events(ctx context.Context) (<-chan types.DockerEvent, <-chan error)
+		ListNetworks(ctx context.Context) ([]types.Network, error)
+		CreateNetwork(ctx context.Context, options types.CreateNetworkOptions) (types.CreateNetworkResponse, error)
+		DeleteNetwork(ctx context.Context, id string) error
+		ConnectNetwork(ctx context.Context, id string, options types.ConnectNetworkOptions) error
+		DisconnectNetwork(ctx context.Context, id string, options types.DisconnectNetworkOptions) error
 	}
 
 	SettingsAdapter interface {
@@ -42,4 +57,51 @@ type (
 		Add(key string) error
 		Remove(fingerprint string) error
 	}
+
+	UserAdapter interface {
+		GetAll() ([]types.User, error)
+		Get(id uuid.UUID) (types.User, error)
+		GetByUsername(username string) (types.User, error)
+		Create(user types.User) error
+		Update(user types.User) error
+		Delete(id uuid.UUID) error
+	}
+
+	SessionAdapter interface {
+		Get(tokenHash string) (types.Session, error)
+		Create(session types.Session) error
+		Delete(tokenHash string) error
+		// DeleteUser deletes all the sessions of a user.
+		DeleteUser(userID uuid.UUID) error
+		DeleteExpired() error
+	}
+
+	RoleAdapter interface {
+		GetAll() ([]types.Role, error)
+		Get(name string) (types.Role, error)
+		// Set creates the role, or replaces it if it already exists.
+		Set(role types.Role) error
+		Delete(name string) error
+	}
+
+	TokenAdapter interface {
+		GetAll() ([]types.Token, error)
+		Get(id uuid.UUID) (types.Token, error)
+		GetByHash(hash string) (types.Token, error)
+		Create(token types.Token) error
+		Update(token types.Token) error
+		Delete(id uuid.UUID) error
+	}
+
+	AuditAdapter interface {
+		// Append adds an entry at the end of the log.
+		Append(entry types.AuditEntry) error
+		// Query returns the entries matching the filter, most recent first.
+		Query(filter types.AuditFilter) ([]types.AuditEntry, error)
+	}
+
+	KernelPolicyAdapter interface {
+		// Get returns the current policy of the kernel.
+		Get() (types.KernelPolicy, error)
+	}
 )
@@ -1,6 +1,10 @@
 package port
 
-import "github.com/vertex-center/vertex/pkg/router"
+import (
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+	"github.com/vertex-center/vertex/pkg/router"
+)
 
 type (
 	AppsHandler interface {
@@ -55,12 +59,40 @@ type (
 		LogsStderrContainer(c *router.Context)
 		// WaitContainer handles the waiting for a Docker container to reach a certain condition.
 		WaitContainer(c *router.Context)
+		// ExecContainer handles the execution of a command inside a running Docker container.
+		ExecContainer(c *router.Context)
+		// ListImages handles the retrieval of all Docker images.
+		ListImages(c *router.Context)
+		// DeleteImage handles the deletion of a Docker image.
+		DeleteImage(c *router.Context)
 		// InfoImage handles the retrieval of information about a Docker image.
 		InfoImage(c *router.Context)
 		// PullImage handles the pulling of a Docker image.
 		PullImage(c *router.Context)
 		// BuildImage handles the building of a Docker image.
 		BuildImage(c *router.Context)
+		// PruneBuildCache handles the removal of the Docker build cache.
+		PruneBuildCache(c *router.Context)
+		// ListVolumes handles the retrieval of all Docker volumes.
+		ListVolumes(c *router.Context)
+		// CreateVolume handles the creation of a Docker volume.
+		CreateVolume(c *router.Context)
+		// InspectVolume handles the retrieval of a Docker volume.
+		InspectVolume(c *router.Context)
+		// DeleteVolume handles the deletion of a Docker volume.
+		DeleteVolume(c *router.Context)
+		// Events handles the stream of the Docker container events.
+		Events(c *router.Context)
+		// ListNetworks handles the retrieval of all Docker networks.
+		ListNetworks(c *router.Context)
+		// CreateNetwork handles the creation of a Docker network.
+		CreateNetwork(c *router.Context)
+		// DeleteNetwork handles the deletion of a Docker network.
+		DeleteNetwork(c *router.Context)
+		// ConnectNetwork handles the connection of a container to a Docker network.
+		ConnectNetwork(c *router.Context)
+		// DisconnectNetwork handles the disconnection of a container from a Docker network.
+		DisconnectNetwork(c *router.Context)
 	}
 
 	SshKernelHandler interface {
@@ -71,4 +103,66 @@ type (
 		// Delete handles the deletion of an SSH key.
 		Delete(c *router.Context)
 	}
+
+	AuthHandler interface {
+		// GetSetup handles the retrieval of the setup status.
+		GetSetup(c *router.Context)
+		// Setup handles the creation of the first admin.
+		Setup(c *router.Context)
+		// Login handles the login of a user.
+		Login(c *router.Context)
+		// Logout handles the logout of the current user.
+		Logout(c *router.Context)
+		// Me handles the retrieval of the current user.
+		Me(c *router.Context)
+		// Authenticate is a middleware that rejects the unauthenticated requests.
+		Authenticate(c *router.Context)
+		// Require returns a middleware that rejects the requests of the users
+		// without the permission. Roles limited to some tags are accepted only
+		// if the tags of the resource, returned by scope, match.
+		Require(permission types.Permission, scope app.ScopeFunc) router.HandlerFunc
+		// RequireFiltered is like Require, but accepts the roles limited to
+		// some tags for handlers that filter the resources themselves.
+		RequireFiltered(permission types.Permission) router.HandlerFunc
+	}
+
+	UserHandler interface {
+		// Get handles the retrieval of all users.
+		Get(c *router.Context)
+		// Create handles the creation of a user.
+		Create(c *router.Context)
+		// Delete handles the deletion of a user.
+		Delete(c *router.Context)
+		// SetPassword handles the change of the password of a user.
+		SetPassword(c *router.Context)
+		// SetRoles handles the change of the roles of a user.
+		SetRoles(c *router.Context)
+	}
+
+	RoleHandler interface {
+		// Get handles the retrieval of all roles.
+		Get(c *router.Context)
+		// GetPermissions handles the retrieval of all the permissions.
+		GetPermissions(c *router.Context)
+		// Set handles the creation or the update of a role.
+		Set(c *router.Context)
+		// Delete handles the deletion of a role.
+		Delete(c *router.Context)
+	}
+
+	TokenHandler interface {
+		// Get handles the retrieval of the tokens of the current user.
+		Get(c *router.Context)
+		// Create handles the creation of a token.
+		Create(c *router.Context)
+		// Delete handles the revocation of a token.
+		Delete(c *router.Context)
+	}
+
+	AuditHandler interface {
+		// Get handles the retrieval of the audit entries.
+		Get(c *router.Context)
+		// Record is a middleware that records the mutating requests.
+		Record(c *router.Context)
+	}
 )
@@ -1,7 +1,10 @@
 package port
 
 import (
+	"context"
+
 	dockertypes "github.com/docker/docker/api/types"
+	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/core/types/app"
 	"io"
@@ -13,18 +16,32 @@ type (
 	}
 
 	DockerService interface {
-		ListContainers() ([]types.Container, error)
-		DeleteContainer(id string) error
-		CreateContainer(options types.CreateContainerOptions) (types.CreateContainerResponse, error)
-		StartContainer(id string) error
-		StopContainer(id string) error
-		InfoContainer(id string) (types.InfoContainerResponse, error)
-		LogsStdoutContainer(id string) (io.ReadCloser, error)
-		LogsStderrContainer(id string) (io.ReadCloser, error)
-		WaitContainer(id string, cond types.WaitContainerCondition) error
-		InfoImage(id string) (types.InfoImageResponse, error)
-		PullImage(options types.PullImageOptions) (io.ReadCloser, error)
-		BuildImage(options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error)
+		ListContainers(ctx context.Context) ([]types.Container, error)
+		DeleteContainer(ctx context.Context, id string) error
+		CreateContainer(ctx context.Context, options types.CreateContainerOptions) (types.CreateContainerResponse, error)
+		StartContainer(ctx context.Context, id string) error
+		StopContainer(ctx context.Context, id string, options types.StopContainerOptions) error
+		InfoContainer(ctx context.Context, id string) (types.InfoContainerResponse, error)
+		LogsStdoutContainer(ctx context.Context, id string, options types.LogsContainerOptions) (io.ReadCloser, error)
+		LogsStderrContainer(ctx context.Context, id string, options types.LogsContainerOptions) (io.ReadCloser, error)
+		WaitContainer(ctx context.Context, id string, cond types.WaitContainerCondition) error
+		ExecContainer(ctx context.Context, id string, options types.ExecContainerOptions) (types.ExecContainerResponse, error)
+		ListImages(ctx context.Context) ([]types.Image, error)
+		DeleteImage(ctx context.Context, id string) error
+		InfoImage(ctx context.Context, id string) (types.InfoImageResponse, error)
+		PullImage(ctx context.Context, options types.PullImageOptions) (io.ReadCloser, error)
+		BuildImage(ctx context.Context, options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error)
+		PruneBuildCache(ctx context.Context) (types.PruneBuildCacheResponse, error)
+		ListVolumes(ctx context.Context) ([]types.Volume, error)
+		CreateVolume(ctx context.Context, options types.CreateVolumeOptions) (types.Volume, error)
+		InspectVolume(ctx context.Context, name string) (types.Volume, error)
+		DeleteVolume(ctx context.Context, name string) error
+		Events(ctx context.Context) (<-chan types.DockerEvent, <-chan error)
+		ListNetworks(ctx context.Context) ([]types.Network, error)
+		CreateNetwork(ctx context.Context, options types.CreateNetworkOptions) (types.CreateNetworkResponse, error)
+		DeleteNetwork(ctx context.Context, id string) error
+		ConnectNetwork(ctx context.Context, id string, options types.ConnectNetworkOptions) error
+		DisconnectNetwork(ctx context.Context, id string, options types.DisconnectNetworkOptions) error
 	}
 
 	HardwareService interface {
@@ -50,4 +67,47 @@ type (
 		GetUpdate(channel types.SettingsUpdatesChannel) (*types.Update, error)
 		InstallLatest(channel types.SettingsUpdatesChannel) error
 	}
+
+	AuthService interface {
+		// SetupRequired returns true if no user exists yet.
+		SetupRequired() (bool, error)
+		// Setup creates the first admin. It fails if a user already exists.
+		Setup(credentials types.Credentials) (types.User, error)
+		Login(credentials types.Credentials) (types.LoginResponse, error)
+		Logout(token string) error
+		// Authenticate returns the identity of the owner of the given session
+		// or API token.
+		Authenticate(token string) (types.Authentication, error)
+	}
+
+	UserService interface {
+		GetAll() ([]types.User, error)
+		Create(options types.CreateUserOptions) (types.User, error)
+		Delete(id uuid.UUID) error
+		SetPassword(id uuid.UUID, password string) error
+		SetRoles(id uuid.UUID, roles []string) error
+	}
+
+	RoleService interface {
+		GetAll() ([]types.Role, error)
+		Set(role types.Role) error
+		Delete(name string) error
+		// Authorize checks if the user has the permission, and to which
+		// container tags it is limited.
+		Authorize(user types.User, permission types.Permission) (types.Authorization, error)
+	}
+
+	TokenService interface {
+		// GetAll returns the tokens of a user.
+		GetAll(userID uuid.UUID) ([]types.Token, error)
+		Create(userID uuid.UUID, options types.CreateTokenOptions) (types.CreateTokenResponse, error)
+		// Delete revokes a token. Only its owner or an admin can revoke it.
+		Delete(user types.User, id uuid.UUID) error
+	}
+
+	AuditService interface {
+		// Record saves an entry, and dispatches it on the event bus.
+		Record(entry types.AuditEntry) error
+		Query(filter types.AuditFilter) ([]types.AuditEntry, error)
+	}
 )
@@ -13,20 +13,22 @@ import (
 )
 
 type AppsService struct {
-	uuid     uuid.UUID
-	ctx      *types.VertexContext
-	apps     []app.Interface
-	registry *app.AppsRegistry
-	router   *router.Router
+	uuid       uuid.UUID
+	ctx        *types.VertexContext
+	apps       []app.Interface
+	registry   *app.AppsRegistry
+	router     *router.Router
+	authorizer app.Authorizer
 }
 
-func NewAppsService(ctx *types.VertexContext, router *router.Router, apps []app.Interface) port.AppsService {
+func NewAppsService(ctx *types.VertexContext, router *router.Router, authorizer app.Authorizer, apps []app.Interface) port.AppsService {
 	s := &AppsService{
-		uuid:     uuid.New(),
-		ctx:      ctx,
-		apps:     apps,
-		registry: app.NewAppsRegistry(ctx),
-		router:   router,
+		uuid:       uuid.New(),
+		ctx:        ctx,
+		apps:       apps,
+		registry:   app.NewAppsRegistry(ctx),
+		router:     router,
+		authorizer: authorizer,
 	}
 	s.ctx.AddListener(s)
 	return s
@@ -63,7 +65,7 @@ func (s *AppsService) StartApps() {
 }
 
 func (s *AppsService) startApp(impl app.Interface) error {
-	a := app.New(s.ctx)
+	a := app.New(s.ctx, s.authorizer)
 	err := s.registry.RegisterApp(a, impl)
 	if err != nil {
 		log.Error(errors.New("failed to initialize app"), vlog.String("error", err.Error()))
@@ -23,13 +23,13 @@ func TestAppsServiceTestSuite(t *testing.T) {
 func (suite *AppsServiceTestSuite) SetupTest() {
 	ctx := types.NewVertexContext()
 	suite.app = &MockApp{}
-	suite.service = NewAppsService(ctx, router.New(), []app.Interface{
+	suite.service = NewAppsService(ctx, router.New(), nil, []app.Interface{
 		suite.app,
 	}).(*AppsService)
 }
 
 func (suite *AppsServiceTestSuite) TestStartApps() {
-	a := app.New(suite.service.ctx)
+	a := app.New(suite.service.ctx, nil)
 
 	suite.app.On("Initialize", a).Return(nil)
 	suite.service.StartApps()
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+)
+
+type AuditService struct {
+	ctx          *types.VertexContext
+	auditAdapter port.AuditAdapter
+	source       string
+}
+
+type AuditServiceParams struct {
+	// Ctx is used to dispatch the entries. It is nil in the kernel, which
+	// has no event bus.
+	Ctx          *types.VertexContext
+	AuditAdapter port.AuditAdapter
+	Source       string
+}
+
+func NewAuditService(params AuditServiceParams) port.AuditService {
+	if params.Source == "" {
+		params.Source = types.AuditSourceVertex
+	}
+	return &AuditService{
+		ctx:          params.Ctx,
+		auditAdapter: params.AuditAdapter,
+		source:       params.Source,
+	}
+}
+
+func (s *AuditService) Record(entry types.AuditEntry) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	entry.Source = s.source
+
+	err := s.auditAdapter.Append(entry)
+	if err != nil {
+		return err
+	}
+
+	if s.ctx != nil {
+		s.ctx.DispatchEvent(types.EventAuditEntry{Entry: entry})
+	}
+	return nil
+}
+
+func (s *AuditService) Query(filter types.AuditFilter) ([]types.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = types.AuditDefaultLimit
+	}
+	if filter.Limit > types.AuditMaxLimit {
+		filter.Limit = types.AuditMaxLimit
+	}
+	return s.auditAdapter.Query(filter)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/core/types"
+)
+
+type AuditServiceTestSuite struct {
+	suite.Suite
+
+	ctx     *types.VertexContext
+	service *AuditService
+	adapter *MockAuditAdapter
+}
+
+func TestAuditServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditServiceTestSuite))
+}
+
+func (suite *AuditServiceTestSuite) SetupTest() {
+	suite.ctx = types.NewVertexContext()
+	suite.adapter = &MockAuditAdapter{}
+	suite.service = NewAuditService(AuditServiceParams{
+		Ctx:          suite.ctx,
+		AuditAdapter: suite.adapter,
+	}).(*AuditService)
+}
+
+func (suite *AuditServiceTestSuite) TestRecord() {
+	suite.adapter.On("Append", mock.MatchedBy(func(e types.AuditEntry) bool {
+		return e.ID != uuid.Nil && !e.Time.IsZero() && e.Source == types.AuditSourceVertex
+	})).Return(nil)
+
+	var dispatched []types.AuditEntry
+	suite.ctx.AddListener(types.NewTempListener(func(e interface{}) {
+		if e, ok := e.(types.EventAuditEntry); ok {
+			dispatched = append(dispatched, e.Entry)
+		}
+	}))
+
+	err := suite.service.Record(types.AuditEntry{Method: "DELETE", Route: "/api/user/:user_uuid"})
+
+	suite.NoError(err)
+	suite.Len(dispatched, 1)
+	suite.Equal("/api/user/:user_uuid", dispatched[0].Route)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *AuditServiceTestSuite) TestQueryLimit() {
+	suite.adapter.On("Query", types.AuditFilter{Limit: types.AuditDefaultLimit}).Return([]types.AuditEntry{}, nil).Once()
+	suite.adapter.On("Query", types.AuditFilter{Limit: types.AuditMaxLimit}).Return([]types.AuditEntry{}, nil).Once()
+
+	_, err := suite.service.Query(types.AuditFilter{})
+	suite.NoError(err)
+
+	_, err = suite.service.Query(types.AuditFilter{Limit: types.AuditMaxLimit + 1})
+	suite.NoError(err)
+
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+type MockAuditAdapter struct {
+	mock.Mock
+}
+
+func (m *MockAuditAdapter) Append(entry types.AuditEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditAdapter) Query(filter types.AuditFilter) ([]types.AuditEntry, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]types.AuditEntry), args.Error(1)
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyPasswordHash is compared to the password of unknown users, so that
+// a login takes the same time whether the user exists or not.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("vertex-dummy-password"), bcrypt.DefaultCost)
+
+// tokenLastUsedInterval is the precision of the last usage time of the
+// tokens, to avoid writing them on every request.
+const tokenLastUsedInterval = time.Minute
+
+// internalUser is the user of the requests sent by Vertex to its own API.
+var internalUser = types.User{
+	Username: "vertex",
+	Admin:    true,
+}
+
+// internalTokenScopes are the permissions needed by the apps to call each
+// other through their API.
+var internalTokenScopes = []types.Permission{
+	"containers:*",
+	"sql:*",
+}
+
+type AuthService struct {
+	userAdapter    port.UserAdapter
+	sessionAdapter port.SessionAdapter
+	tokenAdapter   port.TokenAdapter
+
+	internalToken string
+
+	// setupMutex prevents two admins from being created by the setup.
+	setupMutex sync.Mutex
+}
+
+type AuthServiceParams struct {
+	UserAdapter    port.UserAdapter
+	SessionAdapter port.SessionAdapter
+	TokenAdapter   port.TokenAdapter
+
+	// InternalToken authenticates the requests sent by Vertex to itself.
+	InternalToken string
+}
+
+func NewAuthService(params AuthServiceParams) port.AuthService {
+	return &AuthService{
+		userAdapter:    params.UserAdapter,
+		sessionAdapter: params.SessionAdapter,
+		tokenAdapter:   params.TokenAdapter,
+		internalToken:  params.InternalToken,
+	}
+}
+
+func (s *AuthService) SetupRequired() (bool, error) {
+	users, err := s.userAdapter.GetAll()
+	if err != nil {
+		return false, err
+	}
+	return len(users) == 0, nil
+}
+
+func (s *AuthService) Setup(credentials types.Credentials) (types.User, error) {
+	s.setupMutex.Lock()
+	defer s.setupMutex.Unlock()
+
+	required, err := s.SetupRequired()
+	if err != nil {
+		return types.User{}, err
+	}
+	if !required {
+		return types.User{}, types.ErrSetupAlreadyDone
+	}
+
+	user, err := newUser(types.CreateUserOptions{
+		Credentials: credentials,
+		Admin:       true,
+	})
+	if err != nil {
+		return types.User{}, err
+	}
+
+	err = s.userAdapter.Create(user)
+	if err != nil {
+		return types.User{}, err
+	}
+
+	log.Info("admin created", vlog.String("username", user.Username))
+	return user, nil
+}
+
+func (s *AuthService) Login(credentials types.Credentials) (types.LoginResponse, error) {
+	user, err := s.userAdapter.GetByUsername(credentials.Username)
+	if errors.Is(err, types.ErrUserNotFound) {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(credentials.Password))
+		return types.LoginResponse{}, types.ErrInvalidCredentials
+	} else if err != nil {
+		return types.LoginResponse{}, err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(credentials.Password))
+	if err != nil {
+		log.Warn("failed login attempt", vlog.String("username", credentials.Username))
+		return types.LoginResponse{}, types.ErrInvalidCredentials
+	}
+
+	err = s.sessionAdapter.DeleteExpired()
+	if err != nil {
+		log.Error(err)
+	}
+
+	token, err := newSessionToken()
+	if err != nil {
+		return types.LoginResponse{}, err
+	}
+
+	now := time.Now()
+	session := types.Session{
+		TokenHash: hashToken(token),
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(types.SessionDuration),
+	}
+
+	err = s.sessionAdapter.Create(session)
+	if err != nil {
+		return types.LoginResponse{}, err
+	}
+
+	log.Info("user logged in", vlog.String("username", user.Username))
+	return types.LoginResponse{
+		Token:     token,
+		ExpiresAt: session.ExpiresAt,
+		User:      user,
+	}, nil
+}
+
+func (s *AuthService) Logout(token string) error {
+	return s.sessionAdapter.Delete(hashToken(token))
+}
+
+func (s *AuthService) Authenticate(token string) (types.Authentication, error) {
+	if token == "" {
+		return types.Authentication{}, types.ErrSessionNotFound
+	}
+
+	if s.internalToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.internalToken)) == 1 {
+		return types.Authentication{
+			User:   internalUser,
+			Scopes: internalTokenScopes,
+		}, nil
+	}
+
+	if strings.HasPrefix(token, types.TokenPrefix) {
+		return s.authenticateToken(token)
+	}
+
+	session, err := s.sessionAdapter.Get(hashToken(token))
+	if err != nil {
+		return types.Authentication{}, err
+	}
+
+	if session.Expired() {
+		_ = s.sessionAdapter.Delete(session.TokenHash)
+		return types.Authentication{}, types.ErrSessionExpired
+	}
+
+	user, err := s.userAdapter.Get(session.UserID)
+	if err != nil {
+		return types.Authentication{}, err
+	}
+	return types.Authentication{User: user}, nil
+}
+
+func (s *AuthService) authenticateToken(secret string) (types.Authentication, error) {
+	token, err := s.tokenAdapter.GetByHash(hashToken(secret))
+	if err != nil {
+		return types.Authentication{}, err
+	}
+
+	if token.Expired() {
+		return types.Authentication{}, types.ErrTokenExpired
+	}
+
+	user, err := s.userAdapter.Get(token.UserID)
+	if err != nil {
+		return types.Authentication{}, err
+	}
+
+	now := time.Now()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > tokenLastUsedInterval {
+		token.LastUsedAt = &now
+		err = s.tokenAdapter.Update(token)
+		if err != nil {
+			log.Error(err)
+		}
+	}
+
+	scopes := token.Scopes
+	if scopes == nil {
+		scopes = []types.Permission{}
+	}
+	return types.Authentication{
+		User:   user,
+		Scopes: scopes,
+	}, nil
+}
+
+func newSessionToken() (string, error) {
+	token := make([]byte, 32)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// hashToken returns the hash of a session token. Only the hash is stored,
+// so that a leak of the sessions file doesn't leak valid tokens.
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
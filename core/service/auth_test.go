@@ -0,0 +1,162 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/core/types"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AuthServiceTestSuite struct {
+	suite.Suite
+
+	service        *AuthService
+	userAdapter    *MockUserAdapter
+	sessionAdapter *MockSessionAdapter
+	tokenAdapter   *MockTokenAdapter
+}
+
+func TestAuthServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthServiceTestSuite))
+}
+
+func (suite *AuthServiceTestSuite) SetupTest() {
+	suite.userAdapter = &MockUserAdapter{}
+	suite.sessionAdapter = &MockSessionAdapter{}
+	suite.tokenAdapter = &MockTokenAdapter{}
+	suite.service = NewAuthService(AuthServiceParams{
+		UserAdapter:    suite.userAdapter,
+		SessionAdapter: suite.sessionAdapter,
+		TokenAdapter:   suite.tokenAdapter,
+		InternalToken:  "internal",
+	}).(*AuthService)
+}
+
+func (suite *AuthServiceTestSuite) TestSetup() {
+	suite.userAdapter.On("GetAll").Return([]types.User{}, nil).Once()
+	suite.userAdapter.On("Create", mock.AnythingOfType("types.User")).Return(nil)
+
+	user, err := suite.service.Setup(types.Credentials{Username: "admin", Password: "password123"})
+
+	suite.NoError(err)
+	suite.True(user.Admin)
+	suite.userAdapter.AssertExpectations(suite.T())
+}
+
+func (suite *AuthServiceTestSuite) TestSetupAlreadyDone() {
+	suite.userAdapter.On("GetAll").Return([]types.User{{Username: "admin"}}, nil)
+
+	_, err := suite.service.Setup(types.Credentials{Username: "other", Password: "password123"})
+
+	suite.ErrorIs(err, types.ErrSetupAlreadyDone)
+	suite.userAdapter.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestLoginAuthenticate() {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+
+	user := types.User{ID: uuid.New(), Username: "alice", PasswordHash: string(hash)}
+	suite.userAdapter.On("GetByUsername", "alice").Return(user, nil)
+	suite.userAdapter.On("Get", user.ID).Return(user, nil)
+	suite.sessionAdapter.On("DeleteExpired").Return(nil)
+
+	var session types.Session
+	suite.sessionAdapter.On("Create", mock.AnythingOfType("types.Session")).
+		Run(func(args mock.Arguments) { session = args.Get(0).(types.Session) }).
+		Return(nil)
+
+	res, err := suite.service.Login(types.Credentials{Username: "alice", Password: "password123"})
+	suite.Require().NoError(err)
+	suite.NotEmpty(res.Token)
+	suite.NotEqual(res.Token, session.TokenHash)
+	suite.Equal(user.ID, session.UserID)
+
+	suite.sessionAdapter.On("Get", session.TokenHash).Return(session, nil)
+
+	authenticated, err := suite.service.Authenticate(res.Token)
+	suite.NoError(err)
+	suite.Equal(user.ID, authenticated.User.ID)
+	suite.Nil(authenticated.Scopes)
+}
+
+func (suite *AuthServiceTestSuite) TestLoginInvalidCredentials() {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+
+	suite.userAdapter.On("GetByUsername", "alice").Return(types.User{Username: "alice", PasswordHash: string(hash)}, nil)
+	suite.userAdapter.On("GetByUsername", "bob").Return(types.User{}, types.ErrUserNotFound)
+
+	_, err = suite.service.Login(types.Credentials{Username: "alice", Password: "wrong-password"})
+	suite.ErrorIs(err, types.ErrInvalidCredentials)
+
+	_, err = suite.service.Login(types.Credentials{Username: "bob", Password: "password123"})
+	suite.ErrorIs(err, types.ErrInvalidCredentials)
+
+	suite.sessionAdapter.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestAuthenticateExpired() {
+	session := types.Session{
+		TokenHash: hashToken("token"),
+		UserID:    uuid.New(),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+	suite.sessionAdapter.On("Get", session.TokenHash).Return(session, nil)
+	suite.sessionAdapter.On("Delete", session.TokenHash).Return(nil)
+
+	_, err := suite.service.Authenticate("token")
+
+	suite.ErrorIs(err, types.ErrSessionExpired)
+	suite.sessionAdapter.AssertExpectations(suite.T())
+}
+
+func (suite *AuthServiceTestSuite) TestAuthenticateInternal() {
+	auth, err := suite.service.Authenticate("internal")
+
+	suite.NoError(err)
+	suite.True(auth.User.Admin)
+	suite.True(auth.Grants(types.PermissionContainersStart))
+	suite.False(auth.Grants(types.PermissionSettingsWrite))
+}
+
+func (suite *AuthServiceTestSuite) TestAuthenticateToken() {
+	user := types.User{ID: uuid.New(), Username: "ci"}
+	token := types.Token{
+		ID:     uuid.New(),
+		UserID: user.ID,
+		Scopes: []types.Permission{types.PermissionContainersStart},
+		Hash:   hashToken(types.TokenPrefix + "secret"),
+	}
+	suite.tokenAdapter.On("GetByHash", token.Hash).Return(token, nil)
+	suite.tokenAdapter.On("Update", mock.MatchedBy(func(t types.Token) bool {
+		return t.ID == token.ID && t.LastUsedAt != nil
+	})).Return(nil).Once()
+	suite.userAdapter.On("Get", user.ID).Return(user, nil)
+
+	auth, err := suite.service.Authenticate(types.TokenPrefix + "secret")
+
+	suite.NoError(err)
+	suite.Equal(user.ID, auth.User.ID)
+	suite.True(auth.Grants(types.PermissionContainersStart))
+	suite.False(auth.Grants(types.PermissionContainersEnvWrite))
+	suite.tokenAdapter.AssertExpectations(suite.T())
+}
+
+func (suite *AuthServiceTestSuite) TestAuthenticateTokenExpired() {
+	expiresAt := time.Now().Add(-time.Hour)
+	token := types.Token{
+		ID:        uuid.New(),
+		ExpiresAt: &expiresAt,
+		Hash:      hashToken(types.TokenPrefix + "secret"),
+	}
+	suite.tokenAdapter.On("GetByHash", token.Hash).Return(token, nil)
+
+	_, err := suite.service.Authenticate(types.TokenPrefix + "secret")
+
+	suite.ErrorIs(err, types.ErrTokenExpired)
+}
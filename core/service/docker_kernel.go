@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"github.com/vertex-center/vertex/core/port"
 	"github.com/vertex-center/vertex/core/types"
 	"io"
@@ -12,60 +13,159 @@ import (
 
 type DockerKernelService struct {
 	dockerAdapter port.DockerAdapter
+	policyAdapter port.KernelPolicyAdapter
 }
 
-func NewDockerKernelService(dockerAdapter port.DockerAdapter) port.DockerService {
+func NewDockerKernelService(dockerAdapter port.DockerAdapter, policyAdapter port.KernelPolicyAdapter) port.DockerService {
 	return &DockerKernelService{
 		dockerAdapter: dockerAdapter,
+		policyAdapter: policyAdapter,
 	}
 }
 
-func (s DockerKernelService) ListContainers() ([]types.Container, error) {
-	return s.dockerAdapter.ListContainers()
+func (s DockerKernelService) ListContainers(ctx context.Context) ([]types.Container, error) {
+	return s.dockerAdapter.ListContainers(ctx)
 }
 
-func (s DockerKernelService) DeleteContainer(id string) error {
-	return s.dockerAdapter.DeleteContainer(id)
+func (s DockerKernelService) DeleteContainer(ctx context.Context, id string) error {
+	return s.dockerAdapter.DeleteContainer(ctx, id)
 }
 
-func (s DockerKernelService) CreateContainer(options types.CreateContainerOptions) (types.CreateContainerResponse, error) {
-	return s.dockerAdapter.CreateContainer(options)
+// CreateContainer creates a container, if its options are allowed by the
+// kernel policy. A types.KernelPolicyViolation is returned otherwise.
+func (s DockerKernelService) CreateContainer(ctx context.Context, options types.CreateContainerOptions) (types.CreateContainerResponse, error) {
+	policy, err := s.policyAdapter.Get()
+	if err != nil {
+		return types.CreateContainerResponse{}, err
+	}
+
+	violation := policy.Check(options)
+	if violation != nil {
+		log.Warn("container rejected by the kernel policy",
+			vlog.String("container_name", options.ContainerName),
+			vlog.String("rule", violation.Rule),
+			vlog.String("value", violation.Value),
+		)
+		return types.CreateContainerResponse{}, *violation
+	}
+
+	return s.dockerAdapter.CreateContainer(ctx, options)
+}
+
+func (s DockerKernelService) StartContainer(ctx context.Context, id string) error {
+	return s.dockerAdapter.StartContainer(ctx, id)
+}
+
+func (s DockerKernelService) StopContainer(ctx context.Context, id string, options types.StopContainerOptions) error {
+	return s.dockerAdapter.StopContainer(ctx, id, options)
+}
+
+func (s DockerKernelService) InfoContainer(ctx context.Context, id string) (types.InfoContainerResponse, error) {
+	return s.dockerAdapter.InfoContainer(ctx, id)
 }
 
-func (s DockerKernelService) StartContainer(id string) error {
-	return s.dockerAdapter.StartContainer(id)
+func (s DockerKernelService) LogsStdoutContainer(ctx context.Context, id string, options types.LogsContainerOptions) (io.ReadCloser, error) {
+	return s.dockerAdapter.LogsStdoutContainer(ctx, id, options)
 }
 
-func (s DockerKernelService) StopContainer(id string) error {
-	return s.dockerAdapter.StopContainer(id)
+func (s DockerKernelService) LogsStderrContainer(ctx context.Context, id string, options types.LogsContainerOptions) (io.ReadCloser, error) {
+	return s.dockerAdapter.LogsStderrContainer(ctx, id, options)
 }
 
-func (s DockerKernelService) InfoContainer(id string) (types.InfoContainerResponse, error) {
-	return s.dockerAdapter.InfoContainer(id)
+func (s DockerKernelService) WaitContainer(ctx context.Context, id string, cond types.WaitContainerCondition) error {
+	return s.dockerAdapter.WaitContainer(ctx, id, cond)
 }
 
-func (s DockerKernelService) LogsStdoutContainer(id string) (io.ReadCloser, error) {
-	return s.dockerAdapter.LogsStdoutContainer(id)
+func (s DockerKernelService) ExecContainer(ctx context.Context, id string, options types.ExecContainerOptions) (types.ExecContainerResponse, error) {
+	log.Info("executing command in container", vlog.String("id", id))
+	return s.dockerAdapter.ExecContainer(ctx, id, options)
 }
 
-func (s DockerKernelService) LogsStderrContainer(id string) (io.ReadCloser, error) {
-	return s.dockerAdapter.LogsStderrContainer(id)
+func (s DockerKernelService) ListImages(ctx context.Context) ([]types.Image, error) {
+	return s.dockerAdapter.ListImages(ctx)
 }
 
-func (s DockerKernelService) WaitContainer(id string, cond types.WaitContainerCondition) error {
-	return s.dockerAdapter.WaitContainer(id, cond)
+func (s DockerKernelService) DeleteImage(ctx context.Context, id string) error {
+	log.Info("deleting image", vlog.String("id", id))
+	return s.dockerAdapter.DeleteImage(ctx, id)
 }
 
-func (s DockerKernelService) InfoImage(id string) (types.InfoImageResponse, error) {
-	return s.dockerAdapter.InfoImage(id)
+func (s DockerKernelService) InfoImage(ctx context.Context, id string) (types.InfoImageResponse, error) {
+	return s.dockerAdapter.InfoImage(ctx, id)
 }
 
-func (s DockerKernelService) PullImage(options types.PullImageOptions) (io.ReadCloser, error) {
+func (s DockerKernelService) PullImage(ctx context.Context, options types.PullImageOptions) (io.ReadCloser, error) {
 	log.Info("pulling image", vlog.String("image", options.Image))
-	return s.dockerAdapter.PullImage(options)
+	return s.dockerAdapter.PullImage(ctx, options)
 }
 
-func (s DockerKernelService) BuildImage(options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error) {
+func (s DockerKernelService) BuildImage(ctx context.Context, options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error) {
 	log.Info("building image", vlog.String("dockerfile", options.Dockerfile))
-	return s.dockerAdapter.BuildImage(options)
+	return s.dockerAdapter.BuildImage(ctx, options)
+}
+
+func (s DockerKernelService) PruneBuildCache(ctx context.Context) (types.PruneBuildCacheResponse, error) {
+	log.Info("pruning build cache")
+	return s.dockerAdapter.PruneBuildCache(ctx)
+}
+
+func (s DockerKernelService) ListVolumes(ctx context.Context) ([]types.Volume, error) {
+	return s.dockerAdapter.ListVolumes(ctx)
+}
+
+// CreateVolume creates a volume, if its options are allowed by the kernel
+// policy. A types.KernelPolicyViolation is returned otherwise.
+func (s DockerKernelService) CreateVolume(ctx context.Context, options types.CreateVolumeOptions) (types.Volume, error) {
+	policy, err := s.policyAdapter.Get()
+	if err != nil {
+		return types.Volume{}, err
+	}
+
+	violation := policy.CheckVolume(options)
+	if violation != nil {
+		log.Warn("volume rejected by the kernel policy",
+			vlog.String("name", options.Name),
+			vlog.String("rule", violation.Rule),
+			vlog.String("value", violation.Value),
+		)
+		return types.Volume{}, *violation
+	}
+
+	log.Info("creating volume", vlog.String("name", options.Name))
+	return s.dockerAdapter.CreateVolume(ctx, options)
+}
+
+func (s DockerKernelService) InspectVolume(ctx context.Context, name string) (types.Volume, error) {
+	return s.dockerAdapter.InspectVolume(ctx, name)
+}
+
+func (s DockerKernelService) DeleteVolume(ctx context.Context, name string) error {
+	log.Info("deleting volume", vlog.String("name", name))
+	return s.dockerAdapter.DeleteVolume(ctx, name)
+}
+
+func (s DockerKernelService) Events(ctx context.Context) (<-chan types.DockerEvent, <-chan error) {
+	return s.dockerAdapter.Events(ctx)
+}
+
+func (s DockerKernelService) ListNetworks(ctx context.Context) ([]types.Network, error) {
+	return s.dockerAdapter.ListNetworks(ctx)
+}
+
+func (s DockerKernelService) CreateNetwork(ctx context.Context, options types.CreateNetworkOptions) (types.CreateNetworkResponse, error) {
+	log.Info("creating network", vlog.String("name", options.Name))
+	return s.dockerAdapter.CreateNetwork(ctx, options)
+}
+
+func (s DockerKernelService) DeleteNetwork(ctx context.Context, id string) error {
+	log.Info("deleting network", vlog.String("id", id))
+	return s.dockerAdapter.DeleteNetwork(ctx, id)
+}
+
+func (s DockerKernelService) ConnectNetwork(ctx context.Context, id string, options types.ConnectNetworkOptions) error {
+	return s.dockerAdapter.ConnectNetwork(ctx, id, options)
+}
+
+func (s DockerKernelService) DisconnectNetwork(ctx context.Context, id string, options types.DisconnectNetworkOptions) error {
+	return s.dockerAdapter.DisconnectNetwork(ctx, id, options)
 }
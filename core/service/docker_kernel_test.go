@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"github.com/vertex-center/vertex/core/types"
 	"io"
 	"testing"
@@ -14,8 +15,9 @@ import (
 type DockerKernelServiceTestSuite struct {
 	suite.Suite
 
-	service *DockerKernelService
-	adapter MockDockerAdapter
+	service       *DockerKernelService
+	adapter       MockDockerAdapter
+	policyAdapter MockKernelPolicyAdapter
 }
 
 func TestDockerKernelServiceTestSuite(t *testing.T) {
@@ -24,13 +26,15 @@ func TestDockerKernelServiceTestSuite(t *testing.T) {
 
 func (suite *DockerKernelServiceTestSuite) SetupSuite() {
 	suite.adapter = MockDockerAdapter{}
-	suite.service = NewDockerKernelService(&suite.adapter).(*DockerKernelService)
+	suite.policyAdapter = MockKernelPolicyAdapter{}
+	suite.policyAdapter.On("Get").Return(types.KernelPolicy{}, nil)
+	suite.service = NewDockerKernelService(&suite.adapter, &suite.policyAdapter).(*DockerKernelService)
 }
 
 func (suite *DockerKernelServiceTestSuite) TestListContainers() {
 	suite.adapter.On("ListContainers").Return([]types.Container{}, nil)
 
-	containers, err := suite.service.ListContainers()
+	containers, err := suite.service.ListContainers(context.Background())
 
 	suite.NoError(err)
 	suite.Equal([]types.Container{}, containers)
@@ -40,7 +44,7 @@ func (suite *DockerKernelServiceTestSuite) TestListContainers() {
 func (suite *DockerKernelServiceTestSuite) TestDeleteContainer() {
 	suite.adapter.On("DeleteContainer", mock.Anything).Return(nil)
 
-	err := suite.service.DeleteContainer("")
+	err := suite.service.DeleteContainer(context.Background(), "")
 
 	suite.NoError(err)
 	suite.adapter.AssertExpectations(suite.T())
@@ -49,26 +53,41 @@ func (suite *DockerKernelServiceTestSuite) TestDeleteContainer() {
 func (suite *DockerKernelServiceTestSuite) TestCreateContainer() {
 	suite.adapter.On("CreateContainer", mock.Anything).Return(types.CreateContainerResponse{}, nil)
 
-	container, err := suite.service.CreateContainer(types.CreateContainerOptions{})
+	container, err := suite.service.CreateContainer(context.Background(), types.CreateContainerOptions{})
 
 	suite.NoError(err)
 	suite.Equal(types.CreateContainerResponse{}, container)
 	suite.adapter.AssertExpectations(suite.T())
 }
 
+func (suite *DockerKernelServiceTestSuite) TestCreateContainerPolicyViolation() {
+	_, err := suite.service.CreateContainer(context.Background(), types.CreateContainerOptions{
+		CapAdd: []string{"SYS_ADMIN"},
+	})
+
+	var violation types.KernelPolicyViolation
+	suite.ErrorAs(err, &violation)
+	suite.Equal(types.KernelPolicyRuleCapabilities, violation.Rule)
+	suite.adapter.AssertNotCalled(suite.T(), "CreateContainer", mock.MatchedBy(func(o types.CreateContainerOptions) bool {
+		return len(o.CapAdd) > 0
+	}))
+}
+
 func (suite *DockerKernelServiceTestSuite) TestStartContainer() {
 	suite.adapter.On("StartContainer", mock.Anything).Return(nil)
 
-	err := suite.service.StartContainer("")
+	err := suite.service.StartContainer(context.Background(), "")
 
 	suite.NoError(err)
 	suite.adapter.AssertExpectations(suite.T())
 }
 
 func (suite *DockerKernelServiceTestSuite) TestStopContainer() {
-	suite.adapter.On("StopContainer", mock.Anything).Return(nil)
+	timeout := 30
+	options := types.StopContainerOptions{Timeout: &timeout}
+	suite.adapter.On("StopContainer", "id", options).Return(nil)
 
-	err := suite.service.StopContainer("")
+	err := suite.service.StopContainer(context.Background(), "id", options)
 
 	suite.NoError(err)
 	suite.adapter.AssertExpectations(suite.T())
@@ -77,7 +96,7 @@ func (suite *DockerKernelServiceTestSuite) TestStopContainer() {
 func (suite *DockerKernelServiceTestSuite) TestInfoContainer() {
 	suite.adapter.On("InfoContainer", mock.Anything).Return(types.InfoContainerResponse{}, nil)
 
-	info, err := suite.service.InfoContainer("")
+	info, err := suite.service.InfoContainer(context.Background(), "")
 
 	suite.NoError(err)
 	suite.Equal(types.InfoContainerResponse{}, info)
@@ -87,7 +106,7 @@ func (suite *DockerKernelServiceTestSuite) TestInfoContainer() {
 func (suite *DockerKernelServiceTestSuite) TestLogsStdoutContainer() {
 	suite.adapter.On("LogsStdoutContainer", mock.Anything).Return(nil, nil)
 
-	stdout, err := suite.service.LogsStdoutContainer("")
+	stdout, err := suite.service.LogsStdoutContainer(context.Background(), "", types.LogsContainerOptions{})
 
 	suite.NoError(err)
 	suite.Nil(stdout)
@@ -97,7 +116,7 @@ func (suite *DockerKernelServiceTestSuite) TestLogsStdoutContainer() {
 func (suite *DockerKernelServiceTestSuite) TestLogsStderrContainer() {
 	suite.adapter.On("LogsStderrContainer", mock.Anything).Return(nil, nil)
 
-	stderr, err := suite.service.LogsStderrContainer("")
+	stderr, err := suite.service.LogsStderrContainer(context.Background(), "", types.LogsContainerOptions{})
 
 	suite.NoError(err)
 	suite.Nil(stderr)
@@ -107,16 +126,26 @@ func (suite *DockerKernelServiceTestSuite) TestLogsStderrContainer() {
 func (suite *DockerKernelServiceTestSuite) TestWaitContainer() {
 	suite.adapter.On("WaitContainer", mock.Anything, mock.Anything).Return(nil)
 
-	err := suite.service.WaitContainer("", types.WaitContainerCondition(container.WaitConditionNotRunning))
+	err := suite.service.WaitContainer(context.Background(), "", types.WaitContainerCondition(container.WaitConditionNotRunning))
+
+	suite.NoError(err)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *DockerKernelServiceTestSuite) TestExecContainer() {
+	suite.adapter.On("ExecContainer", "id", types.ExecContainerOptions{Cmd: []string{"true"}}).Return(types.ExecContainerResponse{ExitCode: 0}, nil)
+
+	res, err := suite.service.ExecContainer(context.Background(), "id", types.ExecContainerOptions{Cmd: []string{"true"}})
 
 	suite.NoError(err)
+	suite.Equal(0, res.ExitCode)
 	suite.adapter.AssertExpectations(suite.T())
 }
 
 func (suite *DockerKernelServiceTestSuite) TestInfoImage() {
 	suite.adapter.On("InfoImage", mock.Anything).Return(types.InfoImageResponse{}, nil)
 
-	info, err := suite.service.InfoImage("")
+	info, err := suite.service.InfoImage(context.Background(), "")
 
 	suite.NoError(err)
 	suite.Equal(types.InfoImageResponse{}, info)
@@ -126,7 +155,7 @@ func (suite *DockerKernelServiceTestSuite) TestInfoImage() {
 func (suite *DockerKernelServiceTestSuite) TestPullImage() {
 	suite.adapter.On("PullImage", mock.Anything).Return(nil, nil)
 
-	image, err := suite.service.PullImage(types.PullImageOptions{})
+	image, err := suite.service.PullImage(context.Background(), types.PullImageOptions{})
 
 	suite.NoError(err)
 	suite.Nil(image)
@@ -136,73 +165,245 @@ func (suite *DockerKernelServiceTestSuite) TestPullImage() {
 func (suite *DockerKernelServiceTestSuite) TestBuildImage() {
 	suite.adapter.On("BuildImage", mock.Anything).Return(dockertypes.ImageBuildResponse{}, nil)
 
-	image, err := suite.service.BuildImage(types.BuildImageOptions{})
+	image, err := suite.service.BuildImage(context.Background(), types.BuildImageOptions{})
 
 	suite.NoError(err)
 	suite.Equal(dockertypes.ImageBuildResponse{}, image)
 	suite.adapter.AssertExpectations(suite.T())
 }
 
+func (suite *DockerKernelServiceTestSuite) TestListImages() {
+	suite.adapter.On("ListImages").Return([]types.Image{}, nil)
+
+	images, err := suite.service.ListImages(context.Background())
+
+	suite.NoError(err)
+	suite.Equal([]types.Image{}, images)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *DockerKernelServiceTestSuite) TestDeleteImage() {
+	suite.adapter.On("DeleteImage", mock.Anything).Return(nil)
+
+	err := suite.service.DeleteImage(context.Background(), "")
+
+	suite.NoError(err)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *DockerKernelServiceTestSuite) TestPruneBuildCache() {
+	suite.adapter.On("PruneBuildCache").Return(types.PruneBuildCacheResponse{SpaceReclaimed: 42}, nil)
+
+	res, err := suite.service.PruneBuildCache(context.Background())
+
+	suite.NoError(err)
+	suite.Equal(uint64(42), res.SpaceReclaimed)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *DockerKernelServiceTestSuite) TestListVolumes() {
+	suite.adapter.On("ListVolumes").Return([]types.Volume{}, nil)
+
+	volumes, err := suite.service.ListVolumes(context.Background())
+
+	suite.NoError(err)
+	suite.Equal([]types.Volume{}, volumes)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *DockerKernelServiceTestSuite) TestCreateNetwork() {
+	options := types.CreateNetworkOptions{Name: "vertex_network"}
+	suite.adapter.On("CreateNetwork", options).Return(types.CreateNetworkResponse{ID: "id"}, nil)
+
+	res, err := suite.service.CreateNetwork(context.Background(), options)
+
+	suite.NoError(err)
+	suite.Equal("id", res.ID)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *DockerKernelServiceTestSuite) TestConnectNetwork() {
+	options := types.ConnectNetworkOptions{Container: "container", Aliases: []string{"db"}}
+	suite.adapter.On("ConnectNetwork", "id", options).Return(nil)
+
+	err := suite.service.ConnectNetwork(context.Background(), "id", options)
+
+	suite.NoError(err)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *DockerKernelServiceTestSuite) TestCreateVolume() {
+	options := types.CreateVolumeOptions{Name: "vertex_volume"}
+	suite.adapter.On("CreateVolume", options).Return(types.Volume{Name: "vertex_volume"}, nil)
+
+	volume, err := suite.service.CreateVolume(context.Background(), options)
+
+	suite.NoError(err)
+	suite.Equal("vertex_volume", volume.Name)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *DockerKernelServiceTestSuite) TestCreateVolumePolicyViolation() {
+	_, err := suite.service.CreateVolume(context.Background(), types.CreateVolumeOptions{
+		DriverOptions: map[string]string{"type": "none", "o": "bind", "device": "/etc"},
+	})
+
+	var violation types.KernelPolicyViolation
+	suite.ErrorAs(err, &violation)
+	suite.Equal(types.KernelPolicyRuleBinds, violation.Rule)
+	suite.adapter.AssertNotCalled(suite.T(), "CreateVolume", mock.MatchedBy(func(o types.CreateVolumeOptions) bool {
+		return o.DriverOptions["device"] != ""
+	}))
+}
+
+func (suite *DockerKernelServiceTestSuite) TestDeleteVolume() {
+	suite.adapter.On("DeleteVolume", mock.Anything).Return(nil)
+
+	err := suite.service.DeleteVolume(context.Background(), "")
+
+	suite.NoError(err)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
 type MockDockerAdapter struct {
 	mock.Mock
 }
 
-func (m *MockDockerAdapter) ListContainers() ([]types.Container, error) {
+func (m *MockDockerAdapter) ListContainers(ctx context.Context) ([]types.Container, error) {
 	args := m.Called()
 	return args.Get(0).([]types.Container), args.Error(1)
 }
 
-func (m *MockDockerAdapter) DeleteContainer(id string) error {
+func (m *MockDockerAdapter) DeleteContainer(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockDockerAdapter) CreateContainer(options types.CreateContainerOptions) (types.CreateContainerResponse, error) {
+func (m *MockDockerAdapter) CreateContainer(ctx context.Context, options types.CreateContainerOptions) (types.CreateContainerResponse, error) {
 	args := m.Called(options)
 	return args.Get(0).(types.CreateContainerResponse), args.Error(1)
 }
 
-func (m *MockDockerAdapter) StartContainer(id string) error {
+func (m *MockDockerAdapter) StartContainer(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockDockerAdapter) StopContainer(id string) error {
-	args := m.Called(id)
+func (m *MockDockerAdapter) StopContainer(ctx context.Context, id string, options types.StopContainerOptions) error {
+	args := m.Called(id, options)
 	return args.Error(0)
 }
 
-func (m *MockDockerAdapter) InfoContainer(id string) (types.InfoContainerResponse, error) {
+func (m *MockDockerAdapter) InfoContainer(ctx context.Context, id string) (types.InfoContainerResponse, error) {
 	args := m.Called(id)
 	return args.Get(0).(types.InfoContainerResponse), args.Error(1)
 }
 
-func (m *MockDockerAdapter) LogsStdoutContainer(id string) (io.ReadCloser, error) {
+func (m *MockDockerAdapter) LogsStdoutContainer(ctx context.Context, id string, options types.LogsContainerOptions) (io.ReadCloser, error) {
 	args := m.Called(id)
 	return nil, args.Error(1)
 }
 
-func (m *MockDockerAdapter) LogsStderrContainer(id string) (io.ReadCloser, error) {
+func (m *MockDockerAdapter) LogsStderrContainer(ctx context.Context, id string, options types.LogsContainerOptions) (io.ReadCloser, error) {
 	args := m.Called(id)
 	return nil, args.Error(1)
 }
 
-func (m *MockDockerAdapter) WaitContainer(id string, cond types.WaitContainerCondition) error {
+func (m *MockDockerAdapter) WaitContainer(ctx context.Context, id string, cond types.WaitContainerCondition) error {
 	args := m.Called(id, cond)
 	return args.Error(0)
 }
 
-func (m *MockDockerAdapter) InfoImage(id string) (types.InfoImageResponse, error) {
+func (m *MockDockerAdapter) ExecContainer(ctx context.Context, id string, options types.ExecContainerOptions) (types.ExecContainerResponse, error) {
+	args := m.Called(id, options)
+	return args.Get(0).(types.ExecContainerResponse), args.Error(1)
+}
+
+func (m *MockDockerAdapter) InfoImage(ctx context.Context, id string) (types.InfoImageResponse, error) {
 	args := m.Called(id)
 	return args.Get(0).(types.InfoImageResponse), args.Error(1)
 }
 
-func (m *MockDockerAdapter) PullImage(options types.PullImageOptions) (io.ReadCloser, error) {
+func (m *MockDockerAdapter) PullImage(ctx context.Context, options types.PullImageOptions) (io.ReadCloser, error) {
 	args := m.Called(options)
 	return nil, args.Error(1)
 }
 
-func (m *MockDockerAdapter) BuildImage(options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error) {
+func (m *MockDockerAdapter) BuildImage(ctx context.Context, options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error) {
 	args := m.Called(options)
 	return args.Get(0).(dockertypes.ImageBuildResponse), args.Error(1)
 }
+
+func (m *MockDockerAdapter) ListImages(ctx context.Context) ([]types.Image, error) {
+	args := m.Called()
+	return args.Get(0).([]types.Image), args.Error(1)
+}
+
+func (m *MockDockerAdapter) DeleteImage(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDockerAdapter) PruneBuildCache(ctx context.Context) (types.PruneBuildCacheResponse, error) {
+	args := m.Called()
+	return args.Get(0).(types.PruneBuildCacheResponse), args.Error(1)
+}
+
+func (m *MockDockerAdapter) ListVolumes(ctx context.Context) ([]types.Volume, error) {
+	args := m.Called()
+	return args.Get(0).([]types.Volume), args.Error(1)
+}
+
+func (m *MockDockerAdapter) CreateVolume(ctx context.Context, options types.CreateVolumeOptions) (types.Volume, error) {
+	args := m.Called(options)
+	return args.Get(0).(types.Volume), args.Error(1)
+}
+
+func (m *MockDockerAdapter) InspectVolume(ctx context.Context, name string) (types.Volume, error) {
+	args := m.Called(name)
+	return args.Get(0).(types.Volume), args.Error(1)
+}
+
+func (m *MockDockerAdapter) Events(ctx context.Context) (<-chan types.DockerEvent, <-chan error) {
+	args := m.Called(ctx)
+	return args.Get(0).(<-chan types.DockerEvent), args.Get(1).(<-chan error)
+}
+
+func (m *MockDockerAdapter) DeleteVolume(ctx context.Context, name string) error {
+	args := m.Called(name)
+	return args.Error(0)
+}
+
+func (m *MockDockerAdapter) ListNetworks(ctx context.Context) ([]types.Network, error) {
+	args := m.Called()
+	return args.Get(0).([]types.Network), args.Error(1)
+}
+
+func (m *MockDockerAdapter) CreateNetwork(ctx context.Context, options types.CreateNetworkOptions) (types.CreateNetworkResponse, error) {
+	args := m.Called(options)
+	return args.Get(0).(types.CreateNetworkResponse), args.Error(1)
+}
+
+func (m *MockDockerAdapter) DeleteNetwork(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDockerAdapter) ConnectNetwork(ctx context.Context, id string, options types.ConnectNetworkOptions) error {
+	args := m.Called(id, options)
+	return args.Error(0)
+}
+
+func (m *MockDockerAdapter) DisconnectNetwork(ctx context.Context, id string, options types.DisconnectNetworkOptions) error {
+	args := m.Called(id, options)
+	return args.Error(0)
+}
+
+type MockKernelPolicyAdapter struct {
+	mock.Mock
+}
+
+func (m *MockKernelPolicyAdapter) Get() (types.KernelPolicy, error) {
+	args := m.Called()
+	return args.Get(0).(types.KernelPolicy), args.Error(1)
+}
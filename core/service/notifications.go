@@ -1,12 +1,29 @@
 package service
 
 import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/webhook"
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers/core/types"
 	"github.com/vertex-center/vertex/core/port"
 	types2 "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+const (
+	// notificationsAuditQueueSize is the number of audit entries waiting to
+	// be sent. The entries are dropped when the queue is full.
+	notificationsAuditQueueSize = 100
+
+	// notificationsFailedAuthInterval is the interval at which the failed
+	// authentications are notified, in a single message.
+	notificationsFailedAuthInterval = time.Minute
 )
 
 // TODO: Move webhooks use to a Discord adapter
@@ -16,6 +33,12 @@ type NotificationsService struct {
 	ctx             *types2.VertexContext
 	settingsAdapter port.SettingsAdapter
 	client          webhook.Client
+
+	// audits are sent by a goroutine, so that the webhook never blocks
+	// the dispatch of the events.
+	audits chan types2.AuditEntry
+	stop   chan struct{}
+	done   chan struct{}
 }
 
 func NewNotificationsService(ctx *types2.VertexContext, settingsAdapter port.SettingsAdapter) NotificationsService {
@@ -38,6 +61,11 @@ func (s *NotificationsService) StartWebhook() error {
 		return err
 	}
 
+	s.audits = make(chan types2.AuditEntry, notificationsAuditQueueSize)
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.sendAudits()
+
 	s.ctx.AddListener(s)
 
 	return nil
@@ -45,6 +73,11 @@ func (s *NotificationsService) StartWebhook() error {
 
 func (s *NotificationsService) StopWebhook() {
 	s.ctx.RemoveListener(s)
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+		s.stop = nil
+	}
 }
 
 func (s *NotificationsService) GetUUID() uuid.UUID {
@@ -57,6 +90,104 @@ func (s *NotificationsService) OnEvent(e interface{}) {
 		if e.Status == types.ContainerStatusOff || e.Status == types.ContainerStatusError || e.Status == types.ContainerStatusRunning {
 			s.sendStatus(e.Name, e.Status)
 		}
+	case types2.EventAuditEntry:
+		if !e.Entry.Sensitive() && !isFailedAuth(e.Entry) {
+			return
+		}
+		select {
+		case s.audits <- e.Entry:
+		default:
+			log.Warn("notifications queue is full, audit entry dropped",
+				vlog.String("route", e.Entry.Route),
+			)
+		}
+	}
+}
+
+// sendAudits sends the queued audit entries. The failed authentications
+// are grouped and sent once per notificationsFailedAuthInterval, so that a
+// brute-force attempt doesn't flood the webhook.
+func (s *NotificationsService) sendAudits() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(notificationsFailedAuthInterval)
+	defer ticker.Stop()
+
+	// failed counts the failed authentications by IP.
+	failed := map[string]int{}
+
+	for {
+		select {
+		case entry := <-s.audits:
+			if isFailedAuth(entry) {
+				failed[entry.IP]++
+				continue
+			}
+			s.sendAudit(entry)
+		case <-ticker.C:
+			s.sendFailedAuths(failed)
+			failed = map[string]int{}
+		case <-s.stop:
+			s.sendFailedAuths(failed)
+			return
+		}
+	}
+}
+
+// isFailedAuth returns true if the entry is a request rejected because the
+// user is not authenticated or not allowed.
+func isFailedAuth(entry types2.AuditEntry) bool {
+	return !entry.Success && (entry.Status == http.StatusUnauthorized || entry.Status == http.StatusForbidden)
+}
+
+func (s *NotificationsService) sendFailedAuths(failed map[string]int) {
+	if len(failed) == 0 {
+		return
+	}
+
+	total := 0
+	var ips []string
+	for ip, count := range failed {
+		total += count
+		if ip == "" {
+			ip = "unknown"
+		}
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+
+	embed := discord.NewEmbedBuilder().
+		SetTitlef("%d failed authentications", total).
+		SetDescriptionf("From %s.", strings.Join(ips, ", ")).
+		SetColor(15548997).
+		Build()
+
+	_, err := s.client.CreateEmbeds([]discord.Embed{embed})
+	if err != nil {
+		return
+	}
+}
+
+func (s *NotificationsService) sendAudit(entry types2.AuditEntry) {
+	actor := entry.Actor
+	if actor == "" {
+		actor = "anonymous"
+	}
+
+	color := 3447003
+	if !entry.Success {
+		color = 15548997
+	}
+
+	embed := discord.NewEmbedBuilder().
+		SetTitlef("%s %s", entry.Method, entry.Route).
+		SetDescriptionf("By %s from %s. Status: %d", actor, entry.IP, entry.Status).
+		SetColor(color).
+		Build()
+
+	_, err := s.client.CreateEmbeds([]discord.Embed{embed})
+	if err != nil {
+		return
 	}
 }
 
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+type RoleService struct {
+	roleAdapter port.RoleAdapter
+	userAdapter port.UserAdapter
+}
+
+type RoleServiceParams struct {
+	RoleAdapter port.RoleAdapter
+	UserAdapter port.UserAdapter
+}
+
+func NewRoleService(params RoleServiceParams) port.RoleService {
+	return &RoleService{
+		roleAdapter: params.RoleAdapter,
+		userAdapter: params.UserAdapter,
+	}
+}
+
+func (s *RoleService) GetAll() ([]types.Role, error) {
+	return s.roleAdapter.GetAll()
+}
+
+func (s *RoleService) Set(role types.Role) error {
+	err := role.Validate()
+	if err != nil {
+		return err
+	}
+	if role.Permissions == nil {
+		role.Permissions = []types.Permission{}
+	}
+
+	log.Info("role saved", vlog.String("name", role.Name))
+	return s.roleAdapter.Set(role)
+}
+
+// Delete deletes a role, and removes it from the users having it.
+func (s *RoleService) Delete(name string) error {
+	err := s.roleAdapter.Delete(name)
+	if err != nil {
+		return err
+	}
+
+	users, err := s.userAdapter.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		roles := make([]string, 0, len(user.Roles))
+		for _, r := range user.Roles {
+			if r != name {
+				roles = append(roles, r)
+			}
+		}
+		if len(roles) == len(user.Roles) {
+			continue
+		}
+		user.Roles = roles
+		err = s.userAdapter.Update(user)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Info("role deleted", vlog.String("name", name))
+	return nil
+}
+
+func (s *RoleService) Authorize(user types.User, permission types.Permission) (types.Authorization, error) {
+	if user.Admin {
+		return types.Authorization{Allowed: true}, nil
+	}
+
+	auth := types.Authorization{}
+	for _, name := range user.Roles {
+		role, err := s.roleAdapter.Get(name)
+		if errors.Is(err, types.ErrRoleNotFound) {
+			continue
+		} else if err != nil {
+			return types.Authorization{}, err
+		}
+
+		if !role.Grants(permission) {
+			continue
+		}
+
+		if len(role.Tags) == 0 {
+			return types.Authorization{Allowed: true}, nil
+		}
+
+		auth.Allowed = true
+		auth.Tags = append(auth.Tags, role.Tags...)
+	}
+	return auth, nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/core/types"
+)
+
+type RoleServiceTestSuite struct {
+	suite.Suite
+
+	service     *RoleService
+	roleAdapter *MockRoleAdapter
+	userAdapter *MockUserAdapter
+}
+
+func TestRoleServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(RoleServiceTestSuite))
+}
+
+func (suite *RoleServiceTestSuite) SetupTest() {
+	suite.roleAdapter = &MockRoleAdapter{}
+	suite.userAdapter = &MockUserAdapter{}
+	suite.service = NewRoleService(RoleServiceParams{
+		RoleAdapter: suite.roleAdapter,
+		UserAdapter: suite.userAdapter,
+	}).(*RoleService)
+
+	suite.roleAdapter.On("Get", "viewer").Return(types.Role{
+		Name:        "viewer",
+		Permissions: []types.Permission{types.PermissionContainersRead},
+	}, nil)
+	suite.roleAdapter.On("Get", "media").Return(types.Role{
+		Name:        "media",
+		Permissions: []types.Permission{types.PermissionContainersStart},
+		Tags:        []string{"media"},
+	}, nil)
+	suite.roleAdapter.On("Get", "deleted").Return(types.Role{}, types.ErrRoleNotFound)
+}
+
+func (suite *RoleServiceTestSuite) TestAuthorizeAdmin() {
+	auth, err := suite.service.Authorize(types.User{Admin: true}, types.PermissionSettingsWrite)
+
+	suite.NoError(err)
+	suite.True(auth.Allowed)
+	suite.Nil(auth.Tags)
+}
+
+func (suite *RoleServiceTestSuite) TestAuthorize() {
+	user := types.User{Roles: []string{"deleted", "viewer", "media"}}
+
+	auth, err := suite.service.Authorize(user, types.PermissionContainersRead)
+	suite.NoError(err)
+	suite.True(auth.Allowed)
+	suite.Nil(auth.Tags)
+
+	auth, err = suite.service.Authorize(user, types.PermissionContainersStart)
+	suite.NoError(err)
+	suite.True(auth.Allowed)
+	suite.Equal([]string{"media"}, auth.Tags)
+
+	auth, err = suite.service.Authorize(user, types.PermissionContainersEnvWrite)
+	suite.NoError(err)
+	suite.False(auth.Allowed)
+}
+
+func (suite *RoleServiceTestSuite) TestDelete() {
+	user := types.User{ID: uuid.New(), Roles: []string{"viewer", "media"}}
+	suite.roleAdapter.On("Delete", "media").Return(nil)
+	suite.userAdapter.On("GetAll").Return([]types.User{user, {ID: uuid.New()}}, nil)
+	suite.userAdapter.On("Update", mock.MatchedBy(func(u types.User) bool {
+		return u.ID == user.ID && len(u.Roles) == 1 && u.Roles[0] == "viewer"
+	})).Return(nil).Once()
+
+	err := suite.service.Delete("media")
+
+	suite.NoError(err)
+	suite.userAdapter.AssertExpectations(suite.T())
+}
+
+type MockRoleAdapter struct {
+	mock.Mock
+}
+
+func (m *MockRoleAdapter) GetAll() ([]types.Role, error) {
+	args := m.Called()
+	return args.Get(0).([]types.Role), args.Error(1)
+}
+
+func (m *MockRoleAdapter) Get(name string) (types.Role, error) {
+	args := m.Called(name)
+	return args.Get(0).(types.Role), args.Error(1)
+}
+
+func (m *MockRoleAdapter) Set(role types.Role) error {
+	args := m.Called(role)
+	return args.Error(0)
+}
+
+func (m *MockRoleAdapter) Delete(name string) error {
+	args := m.Called(name)
+	return args.Error(0)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+type TokenService struct {
+	tokenAdapter port.TokenAdapter
+}
+
+type TokenServiceParams struct {
+	TokenAdapter port.TokenAdapter
+}
+
+func NewTokenService(params TokenServiceParams) port.TokenService {
+	return &TokenService{
+		tokenAdapter: params.TokenAdapter,
+	}
+}
+
+func (s *TokenService) GetAll(userID uuid.UUID) ([]types.Token, error) {
+	all, err := s.tokenAdapter.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := []types.Token{}
+	for _, t := range all {
+		if t.UserID == userID {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens, nil
+}
+
+func (s *TokenService) Create(userID uuid.UUID, options types.CreateTokenOptions) (types.CreateTokenResponse, error) {
+	err := options.Validate()
+	if err != nil {
+		return types.CreateTokenResponse{}, err
+	}
+
+	secret, err := newSessionToken()
+	if err != nil {
+		return types.CreateTokenResponse{}, err
+	}
+	secret = types.TokenPrefix + secret
+
+	token := types.Token{
+		ID:        uuid.New(),
+		Name:      options.Name,
+		UserID:    userID,
+		Scopes:    options.Scopes,
+		CreatedAt: time.Now(),
+		ExpiresAt: options.ExpiresAt,
+		Hash:      hashToken(secret),
+	}
+
+	err = s.tokenAdapter.Create(token)
+	if err != nil {
+		return types.CreateTokenResponse{}, err
+	}
+
+	log.Info("token created",
+		vlog.String("name", token.Name),
+		vlog.String("user_id", userID.String()),
+	)
+	return types.CreateTokenResponse{
+		Token:  token,
+		Secret: secret,
+	}, nil
+}
+
+func (s *TokenService) Delete(user types.User, id uuid.UUID) error {
+	token, err := s.tokenAdapter.Get(id)
+	if err != nil {
+		return err
+	}
+
+	// The tokens of the other users are hidden to non-admins.
+	if token.UserID != user.ID && !user.Admin {
+		return types.ErrTokenNotFound
+	}
+
+	log.Info("token revoked", vlog.String("name", token.Name))
+	return s.tokenAdapter.Delete(id)
+}
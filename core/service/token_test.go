@@ -0,0 +1,104 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/core/types"
+)
+
+type TokenServiceTestSuite struct {
+	suite.Suite
+
+	service *TokenService
+	adapter *MockTokenAdapter
+}
+
+func TestTokenServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TokenServiceTestSuite))
+}
+
+func (suite *TokenServiceTestSuite) SetupTest() {
+	suite.adapter = &MockTokenAdapter{}
+	suite.service = NewTokenService(TokenServiceParams{
+		TokenAdapter: suite.adapter,
+	}).(*TokenService)
+}
+
+func (suite *TokenServiceTestSuite) TestCreate() {
+	suite.adapter.On("Create", mock.AnythingOfType("types.Token")).Return(nil)
+
+	userID := uuid.New()
+	res, err := suite.service.Create(userID, types.CreateTokenOptions{
+		Name:   "deploy",
+		Scopes: []types.Permission{types.PermissionContainersStart},
+	})
+
+	suite.NoError(err)
+	suite.True(strings.HasPrefix(res.Secret, types.TokenPrefix))
+	suite.Equal(hashToken(res.Secret), res.Token.Hash)
+	suite.Equal(userID, res.Token.UserID)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *TokenServiceTestSuite) TestCreateInvalid() {
+	_, err := suite.service.Create(uuid.New(), types.CreateTokenOptions{Name: "deploy"})
+	suite.ErrorIs(err, types.ErrTokenScopeInvalid)
+
+	_, err = suite.service.Create(uuid.New(), types.CreateTokenOptions{
+		Name:   "deploy",
+		Scopes: []types.Permission{"containers:everything"},
+	})
+	suite.ErrorIs(err, types.ErrTokenScopeInvalid)
+
+	suite.adapter.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *TokenServiceTestSuite) TestDeleteOtherUser() {
+	token := types.Token{ID: uuid.New(), UserID: uuid.New()}
+	suite.adapter.On("Get", token.ID).Return(token, nil)
+
+	err := suite.service.Delete(types.User{ID: uuid.New()}, token.ID)
+	suite.ErrorIs(err, types.ErrTokenNotFound)
+
+	suite.adapter.On("Delete", token.ID).Return(nil)
+	err = suite.service.Delete(types.User{ID: uuid.New(), Admin: true}, token.ID)
+	suite.NoError(err)
+}
+
+type MockTokenAdapter struct {
+	mock.Mock
+}
+
+func (m *MockTokenAdapter) GetAll() ([]types.Token, error) {
+	args := m.Called()
+	return args.Get(0).([]types.Token), args.Error(1)
+}
+
+func (m *MockTokenAdapter) Get(id uuid.UUID) (types.Token, error) {
+	args := m.Called(id)
+	return args.Get(0).(types.Token), args.Error(1)
+}
+
+func (m *MockTokenAdapter) GetByHash(hash string) (types.Token, error) {
+	args := m.Called(hash)
+	return args.Get(0).(types.Token), args.Error(1)
+}
+
+func (m *MockTokenAdapter) Create(token types.Token) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockTokenAdapter) Update(token types.Token) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockTokenAdapter) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const usernameMaxLength = 64
+
+type UserService struct {
+	userAdapter    port.UserAdapter
+	sessionAdapter port.SessionAdapter
+	roleAdapter    port.RoleAdapter
+	tokenAdapter   port.TokenAdapter
+}
+
+type UserServiceParams struct {
+	UserAdapter    port.UserAdapter
+	SessionAdapter port.SessionAdapter
+	RoleAdapter    port.RoleAdapter
+	TokenAdapter   port.TokenAdapter
+}
+
+func NewUserService(params UserServiceParams) port.UserService {
+	return &UserService{
+		userAdapter:    params.UserAdapter,
+		sessionAdapter: params.SessionAdapter,
+		roleAdapter:    params.RoleAdapter,
+		tokenAdapter:   params.TokenAdapter,
+	}
+}
+
+func (s *UserService) GetAll() ([]types.User, error) {
+	return s.userAdapter.GetAll()
+}
+
+func (s *UserService) Create(options types.CreateUserOptions) (types.User, error) {
+	user, err := newUser(options)
+	if err != nil {
+		return types.User{}, err
+	}
+
+	err = s.userAdapter.Create(user)
+	if err != nil {
+		return types.User{}, err
+	}
+
+	log.Info("user created",
+		vlog.String("username", user.Username),
+		vlog.Bool("admin", user.Admin),
+	)
+	return user, nil
+}
+
+func (s *UserService) Delete(id uuid.UUID) error {
+	user, err := s.userAdapter.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if user.Admin {
+		users, err := s.userAdapter.GetAll()
+		if err != nil {
+			return err
+		}
+		if countAdmins(users) <= 1 {
+			return types.ErrLastAdmin
+		}
+	}
+
+	err = s.sessionAdapter.DeleteUser(id)
+	if err != nil {
+		return err
+	}
+
+	err = s.deleteTokens(id)
+	if err != nil {
+		return err
+	}
+
+	log.Info("user deleted", vlog.String("username", user.Username))
+	return s.userAdapter.Delete(id)
+}
+
+// SetPassword changes the password of a user. All the sessions of the user
+// are revoked.
+func (s *UserService) SetPassword(id uuid.UUID, password string) error {
+	user, err := s.userAdapter.Get(id)
+	if err != nil {
+		return err
+	}
+
+	user.PasswordHash, err = hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	err = s.userAdapter.Update(user)
+	if err != nil {
+		return err
+	}
+	return s.sessionAdapter.DeleteUser(id)
+}
+
+// SetRoles replaces the roles of a user. All the roles must exist.
+func (s *UserService) SetRoles(id uuid.UUID, roles []string) error {
+	user, err := s.userAdapter.Get(id)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range roles {
+		_, err := s.roleAdapter.Get(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	user.Roles = roles
+	return s.userAdapter.Update(user)
+}
+
+func (s *UserService) deleteTokens(userID uuid.UUID) error {
+	tokens, err := s.tokenAdapter.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, t := range tokens {
+		if t.UserID != userID {
+			continue
+		}
+		err = s.tokenAdapter.Delete(t.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newUser(options types.CreateUserOptions) (types.User, error) {
+	err := validateUsername(options.Username)
+	if err != nil {
+		return types.User{}, err
+	}
+
+	hash, err := hashPassword(options.Password)
+	if err != nil {
+		return types.User{}, err
+	}
+
+	return types.User{
+		ID:           uuid.New(),
+		Username:     options.Username,
+		Admin:        options.Admin,
+		CreatedAt:    time.Now(),
+		PasswordHash: hash,
+	}, nil
+}
+
+func validateUsername(username string) error {
+	if username == "" || len(username) > usernameMaxLength {
+		return types.ErrUsernameInvalid
+	}
+	if strings.IndexFunc(username, func(r rune) bool {
+		return unicode.IsSpace(r) || !unicode.IsPrint(r)
+	}) != -1 {
+		return types.ErrUsernameInvalid
+	}
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < types.PasswordMinLength {
+		return "", types.ErrPasswordTooShort
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func countAdmins(users []types.User) int {
+	count := 0
+	for _, u := range users {
+		if u.Admin {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/core/types"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type UserServiceTestSuite struct {
+	suite.Suite
+
+	service        *UserService
+	userAdapter    *MockUserAdapter
+	sessionAdapter *MockSessionAdapter
+	roleAdapter    *MockRoleAdapter
+	tokenAdapter   *MockTokenAdapter
+}
+
+func TestUserServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(UserServiceTestSuite))
+}
+
+func (suite *UserServiceTestSuite) SetupTest() {
+	suite.userAdapter = &MockUserAdapter{}
+	suite.sessionAdapter = &MockSessionAdapter{}
+	suite.roleAdapter = &MockRoleAdapter{}
+	suite.tokenAdapter = &MockTokenAdapter{}
+	suite.service = NewUserService(UserServiceParams{
+		UserAdapter:    suite.userAdapter,
+		SessionAdapter: suite.sessionAdapter,
+		RoleAdapter:    suite.roleAdapter,
+		TokenAdapter:   suite.tokenAdapter,
+	}).(*UserService)
+}
+
+func (suite *UserServiceTestSuite) TestCreate() {
+	suite.userAdapter.On("Create", mock.AnythingOfType("types.User")).Return(nil)
+
+	user, err := suite.service.Create(types.CreateUserOptions{
+		Credentials: types.Credentials{Username: "alice", Password: "password123"},
+	})
+
+	suite.NoError(err)
+	suite.Equal("alice", user.Username)
+	suite.False(user.Admin)
+	suite.NoError(bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("password123")))
+	suite.userAdapter.AssertExpectations(suite.T())
+}
+
+func (suite *UserServiceTestSuite) TestCreateInvalid() {
+	_, err := suite.service.Create(types.CreateUserOptions{
+		Credentials: types.Credentials{Username: "alice", Password: "short"},
+	})
+	suite.ErrorIs(err, types.ErrPasswordTooShort)
+
+	_, err = suite.service.Create(types.CreateUserOptions{
+		Credentials: types.Credentials{Username: "al ice", Password: "password123"},
+	})
+	suite.ErrorIs(err, types.ErrUsernameInvalid)
+
+	suite.userAdapter.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *UserServiceTestSuite) TestDeleteLastAdmin() {
+	admin := types.User{ID: uuid.New(), Username: "admin", Admin: true}
+	suite.userAdapter.On("Get", admin.ID).Return(admin, nil)
+	suite.userAdapter.On("GetAll").Return([]types.User{admin, {ID: uuid.New()}}, nil)
+
+	err := suite.service.Delete(admin.ID)
+
+	suite.ErrorIs(err, types.ErrLastAdmin)
+	suite.userAdapter.AssertNotCalled(suite.T(), "Delete", admin.ID)
+}
+
+func (suite *UserServiceTestSuite) TestDelete() {
+	user := types.User{ID: uuid.New(), Username: "alice"}
+	suite.userAdapter.On("Get", user.ID).Return(user, nil)
+	suite.userAdapter.On("Delete", user.ID).Return(nil)
+	suite.sessionAdapter.On("DeleteUser", user.ID).Return(nil)
+
+	token := types.Token{ID: uuid.New(), UserID: user.ID}
+	suite.tokenAdapter.On("GetAll").Return([]types.Token{token, {ID: uuid.New(), UserID: uuid.New()}}, nil)
+	suite.tokenAdapter.On("Delete", token.ID).Return(nil).Once()
+
+	err := suite.service.Delete(user.ID)
+
+	suite.NoError(err)
+	suite.userAdapter.AssertExpectations(suite.T())
+	suite.sessionAdapter.AssertExpectations(suite.T())
+	suite.tokenAdapter.AssertExpectations(suite.T())
+}
+
+func (suite *UserServiceTestSuite) TestSetRoles() {
+	user := types.User{ID: uuid.New(), Username: "alice"}
+	suite.userAdapter.On("Get", user.ID).Return(user, nil)
+	suite.roleAdapter.On("Get", "family").Return(types.Role{Name: "family"}, nil)
+	suite.roleAdapter.On("Get", "unknown").Return(types.Role{}, types.ErrRoleNotFound)
+	suite.userAdapter.On("Update", mock.MatchedBy(func(u types.User) bool {
+		return len(u.Roles) == 1 && u.Roles[0] == "family"
+	})).Return(nil)
+
+	err := suite.service.SetRoles(user.ID, []string{"family", "unknown"})
+	suite.ErrorIs(err, types.ErrRoleNotFound)
+
+	err = suite.service.SetRoles(user.ID, []string{"family"})
+	suite.NoError(err)
+	suite.userAdapter.AssertExpectations(suite.T())
+}
+
+type MockUserAdapter struct {
+	mock.Mock
+}
+
+func (m *MockUserAdapter) GetAll() ([]types.User, error) {
+	args := m.Called()
+	return args.Get(0).([]types.User), args.Error(1)
+}
+
+func (m *MockUserAdapter) Get(id uuid.UUID) (types.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(types.User), args.Error(1)
+}
+
+func (m *MockUserAdapter) GetByUsername(username string) (types.User, error) {
+	args := m.Called(username)
+	return args.Get(0).(types.User), args.Error(1)
+}
+
+func (m *MockUserAdapter) Create(user types.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserAdapter) Update(user types.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserAdapter) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type MockSessionAdapter struct {
+	mock.Mock
+}
+
+func (m *MockSessionAdapter) Get(tokenHash string) (types.Session, error) {
+	args := m.Called(tokenHash)
+	return args.Get(0).(types.Session), args.Error(1)
+}
+
+func (m *MockSessionAdapter) Create(session types.Session) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *MockSessionAdapter) Delete(tokenHash string) error {
+	args := m.Called(tokenHash)
+	return args.Error(0)
+}
+
+func (m *MockSessionAdapter) DeleteUser(userID uuid.UUID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockSessionAdapter) DeleteExpired() error {
+	args := m.Called()
+	return args.Error(0)
+}
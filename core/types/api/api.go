@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"net"
 	"net/http"
 
 	"github.com/carlmjohnson/requests"
@@ -47,10 +49,53 @@ func HandleError(requestError error, apiError Error) *Error {
 func AppRequest(AppRoute string) *requests.Builder {
 	return requests.New(func(rb *requests.Builder) {
 		path := "/api/app" + AppRoute + "/"
-		rb.BaseURL(config.Current.VertexURL()).Path(path)
+		rb.BaseURL(config.Current.VertexURL()).Path(path).
+			Bearer(config.Current.InternalToken)
 		rb.AddValidator(func(response *http.Response) error {
 			log.Request("request from app", vlog.String("path", response.Request.URL.Path))
 			return nil
 		})
 	})
 }
+
+// kernelSocketURL is the base URL used when the kernel is reached through
+// its unix socket. The host is ignored by the transport.
+const kernelSocketURL = "http://kernel"
+
+var kernelSocketTransport = &http.Transport{
+	DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", config.Current.KernelSocket)
+	},
+}
+
+// KernelTransport returns the transport to reach the kernel: its unix socket
+// if configured, TCP otherwise.
+func KernelTransport() http.RoundTripper {
+	if config.Current.KernelSocket != "" {
+		return kernelSocketTransport
+	}
+	return http.DefaultTransport
+}
+
+// KernelClient returns an HTTP client for the requests to the kernel that
+// are not built with KernelRequest, like streamed responses.
+func KernelClient() *http.Client {
+	return &http.Client{Transport: KernelTransport()}
+}
+
+// KernelConfig configures a request to the Vertex Kernel API. The requests
+// are authenticated with the kernel secret.
+func KernelConfig(rb *requests.Builder) {
+	if config.Current.KernelSocket != "" {
+		rb.BaseURL(kernelSocketURL)
+	} else {
+		rb.BaseURL(config.Current.KernelURL())
+	}
+	rb.Transport(KernelTransport()).
+		Bearer(config.Current.KernelSecret)
+}
+
+func KernelRequest() *requests.Builder {
+	return requests.New(KernelConfig)
+}
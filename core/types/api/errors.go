@@ -15,16 +15,39 @@ const (
 	ErrFailedToListContainers    router.ErrCode = "failed_to_list_containers"
 	ErrFailedToDeleteContainer   router.ErrCode = "failed_to_delete_container"
 	ErrFailedToCreateContainer   router.ErrCode = "failed_to_create_container"
+	ErrKernelPolicyViolation     router.ErrCode = "kernel_policy_violation"
 	ErrFailedToStartContainer    router.ErrCode = "failed_to_start_container"
 	ErrFailedToStopContainer     router.ErrCode = "failed_to_stop_container"
+	ErrInvalidStopTimeout        router.ErrCode = "invalid_stop_timeout"
 	ErrFailedToRecreateContainer router.ErrCode = "failed_to_recreate_container"
 	ErrFailedToGetContainerLogs  router.ErrCode = "failed_to_get_container_logs"
 	ErrFailedToWaitContainer     router.ErrCode = "failed_to_wait_container"
+	ErrFailedToExecContainer     router.ErrCode = "failed_to_exec_container"
 	ErrFailedToGetContainerInfo  router.ErrCode = "failed_to_get_container_info"
+	ErrFailedToListImages        router.ErrCode = "failed_to_list_images"
+	ErrFailedToDeleteImage       router.ErrCode = "failed_to_delete_image"
 	ErrFailedToGetImageInfo      router.ErrCode = "failed_to_get_image_info"
 	ErrFailedToPullImage         router.ErrCode = "failed_to_pull_image"
 	ErrFailedToBuildImage        router.ErrCode = "failed_to_build_image"
+	ErrFailedToPruneBuildCache   router.ErrCode = "failed_to_prune_build_cache"
+	ErrFailedToListVolumes       router.ErrCode = "failed_to_list_volumes"
+	ErrFailedToCreateVolume      router.ErrCode = "failed_to_create_volume"
+	ErrFailedToInspectVolume     router.ErrCode = "failed_to_inspect_volume"
+	ErrFailedToDeleteVolume      router.ErrCode = "failed_to_delete_volume"
 	ErrContainerNotFound         router.ErrCode = "container_not_found"
+	ErrImageNotFound             router.ErrCode = "image_not_found"
+	ErrImageInUse                router.ErrCode = "image_in_use"
+	ErrVolumeNotFound            router.ErrCode = "volume_not_found"
+	ErrVolumeInUse               router.ErrCode = "volume_in_use"
+	ErrFailedToListNetworks      router.ErrCode = "failed_to_list_networks"
+	ErrFailedToCreateNetwork     router.ErrCode = "failed_to_create_network"
+	ErrFailedToDeleteNetwork     router.ErrCode = "failed_to_delete_network"
+	ErrFailedToConnectNetwork    router.ErrCode = "failed_to_connect_network"
+	ErrFailedToDisconnectNetwork router.ErrCode = "failed_to_disconnect_network"
+	ErrNetworkNotFound           router.ErrCode = "network_not_found"
+	ErrNetworkInUse              router.ErrCode = "network_in_use"
+	ErrNetworkAlreadyExists      router.ErrCode = "network_already_exists"
+	ErrNetworkAlreadyConnected   router.ErrCode = "network_already_connected"
 
 	ErrFailedToGetSSHKeys   router.ErrCode = "failed_to_get_ssh_keys"
 	ErrFailedToAddSSHKey    router.ErrCode = "failed_to_add_ssh_key"
@@ -33,4 +56,41 @@ const (
 	ErrInvalidFingerprint   router.ErrCode = "invalid_fingerprint"
 
 	ErrFailedToPatchSettings router.ErrCode = "failed_to_patch_settings"
+
+	ErrFailedToGetSetup    router.ErrCode = "failed_to_get_setup"
+	ErrFailedToSetup       router.ErrCode = "failed_to_setup"
+	ErrSetupAlreadyDone    router.ErrCode = "setup_already_done"
+	ErrFailedToLogin       router.ErrCode = "failed_to_login"
+	ErrInvalidCredentials  router.ErrCode = "invalid_credentials"
+	ErrFailedToLogout      router.ErrCode = "failed_to_logout"
+	ErrForbidden           router.ErrCode = "forbidden"
+	ErrFailedToGetUsers    router.ErrCode = "failed_to_get_users"
+	ErrFailedToCreateUser  router.ErrCode = "failed_to_create_user"
+	ErrFailedToDeleteUser  router.ErrCode = "failed_to_delete_user"
+	ErrFailedToSetPassword router.ErrCode = "failed_to_set_password"
+	ErrUserNotFound        router.ErrCode = "user_not_found"
+	ErrUserAlreadyExists   router.ErrCode = "user_already_exists"
+	ErrInvalidUserUUID     router.ErrCode = "invalid_user_uuid"
+	ErrInvalidUsername     router.ErrCode = "invalid_username"
+	ErrPasswordTooShort    router.ErrCode = "password_too_short"
+	ErrLastAdmin           router.ErrCode = "last_admin"
+
+	ErrFailedToGetRoles   router.ErrCode = "failed_to_get_roles"
+	ErrFailedToSetRole    router.ErrCode = "failed_to_set_role"
+	ErrFailedToDeleteRole router.ErrCode = "failed_to_delete_role"
+	ErrFailedToSetRoles   router.ErrCode = "failed_to_set_roles"
+	ErrRoleNotFound       router.ErrCode = "role_not_found"
+	ErrInvalidRoleName    router.ErrCode = "invalid_role_name"
+	ErrInvalidPermission  router.ErrCode = "invalid_permission"
+
+	ErrFailedToGetTokens   router.ErrCode = "failed_to_get_tokens"
+	ErrFailedToCreateToken router.ErrCode = "failed_to_create_token"
+	ErrFailedToDeleteToken router.ErrCode = "failed_to_delete_token"
+	ErrTokenNotFound       router.ErrCode = "token_not_found"
+	ErrInvalidTokenUUID    router.ErrCode = "invalid_token_uuid"
+	ErrInvalidTokenName    router.ErrCode = "invalid_token_name"
+	ErrInvalidTokenScope   router.ErrCode = "invalid_token_scope"
+
+	ErrFailedToGetAuditEntries router.ErrCode = "failed_to_get_audit_entries"
+	ErrInvalidAuditFilter      router.ErrCode = "invalid_audit_filter"
 )
@@ -28,13 +28,15 @@ type App struct {
 	ctx          *Context
 	httpHandlers map[string]HttpHandler
 	routersMutex *sync.RWMutex
+	authorizer   Authorizer
 }
 
-func New(ctx *types.VertexContext) *App {
+func New(ctx *types.VertexContext, authorizer Authorizer) *App {
 	return &App{
 		ctx:          NewContext(ctx),
 		httpHandlers: map[string]HttpHandler{},
 		routersMutex: &sync.RWMutex{},
+		authorizer:   authorizer,
 	}
 }
 
@@ -48,6 +50,19 @@ func (app *App) RegisterRoutes(route string, handler HttpHandler) {
 	app.httpHandlers[route] = handler
 }
 
+// Require returns a middleware that rejects the requests of the users
+// without the permission. If scope is not nil, the roles limited to some
+// container tags are accepted if the tags of the resource match.
+func (app *App) Require(permission types.Permission, scope ScopeFunc) router.HandlerFunc {
+	return app.authorizer.Require(permission, scope)
+}
+
+// RequireFiltered is like Require, for the handlers returning lists that
+// they filter with AllowedTags.
+func (app *App) RequireFiltered(permission types.Permission) router.HandlerFunc {
+	return app.authorizer.RequireFiltered(permission)
+}
+
 func (app *App) HttpHandlers() map[string]HttpHandler {
 	return app.httpHandlers
 }
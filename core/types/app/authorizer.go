@@ -0,0 +1,36 @@
+package app
+
+import (
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+// allowedTagsKey is the key of the tags allowed by Require and
+// RequireFiltered in the request context.
+const allowedTagsKey = "allowed_tags"
+
+// ScopeFunc returns the container tags of the resource targeted by a request.
+type ScopeFunc func(c *router.Context) ([]string, error)
+
+// Authorizer creates the middlewares that check the permissions of the users.
+type Authorizer interface {
+	Require(permission types.Permission, scope ScopeFunc) router.HandlerFunc
+	RequireFiltered(permission types.Permission) router.HandlerFunc
+}
+
+// SetAllowedTags stores the tags the current user is limited to.
+func SetAllowedTags(c *router.Context, tags []string) {
+	c.Set(allowedTagsKey, tags)
+}
+
+// AllowedTags returns the tags the current user is limited to, if any. The
+// handlers using RequireFiltered must only return the resources having one
+// of these tags, and the handlers setting tags must only set these tags.
+func AllowedTags(c *router.Context) ([]string, bool) {
+	v, ok := c.Get(allowedTagsKey)
+	if !ok {
+		return nil, false
+	}
+	tags, ok := v.([]string)
+	return tags, ok
+}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditSourceVertex = "vertex"
+	AuditSourceKernel = "kernel"
+)
+
+const (
+	// AuditDefaultLimit is the number of entries returned by a query that
+	// doesn't specify a limit.
+	AuditDefaultLimit = 100
+	AuditMaxLimit     = 1000
+)
+
+var ErrAuditFilterInvalid = errors.New("audit filter is invalid")
+
+// sensitiveRoutes are the routes that change the security of Vertex. The
+// entries of these routes are always considered sensitive.
+var sensitiveRoutes = []string{
+	"/api/security",
+	"/api/user",
+	"/api/role",
+	"/api/settings",
+	"/api/update",
+	"/api/hard-reset",
+	"/api/app/vx-reverse-proxy",
+}
+
+// AuditEntry is a mutating action done on Vertex or on the kernel.
+type AuditEntry struct {
+	ID     uuid.UUID `json:"id"`
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+
+	// Actor is the username of the user who did the action. It is empty
+	// for anonymous requests, and for the kernel, which is only called by
+	// Vertex.
+	Actor   string     `json:"actor,omitempty"`
+	ActorID *uuid.UUID `json:"actor_id,omitempty"`
+	IP      string     `json:"ip,omitempty"`
+
+	Method string `json:"method"`
+	Route  string `json:"route"`
+	Path   string `json:"path"`
+
+	// Targets are the IDs of the resources targeted by the action, taken
+	// from the route parameters.
+	Targets map[string]string `json:"targets,omitempty"`
+
+	// Details are additional information given by the handler, like the
+	// capabilities of a created container.
+	Details map[string]string `json:"details,omitempty"`
+
+	Status  int  `json:"status"`
+	Success bool `json:"success"`
+}
+
+// Sensitive returns true if the action should be notified to the admins.
+func (e AuditEntry) Sensitive() bool {
+	if e.Source == AuditSourceKernel || e.Method == "DELETE" {
+		return true
+	}
+	for _, route := range sensitiveRoutes {
+		if strings.HasPrefix(e.Route, route) {
+			return true
+		}
+	}
+	return false
+}
+
+type AuditFilter struct {
+	Actor string
+	Since time.Time
+
+	// Resource matches the entries whose route or targets contain it.
+	Resource string
+
+	Limit int
+}
+
+func (f AuditFilter) Matches(e AuditEntry) bool {
+	if f.Actor != "" && f.Actor != e.Actor {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if f.Resource == "" || strings.Contains(e.Route, f.Resource) {
+		return true
+	}
+	for _, target := range e.Targets {
+		if target == f.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+type EventAuditEntry struct {
+	Entry AuditEntry
+}
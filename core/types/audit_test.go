@@ -0,0 +1,40 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditTestSuite struct {
+	suite.Suite
+}
+
+func TestAuditTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditTestSuite))
+}
+
+func (suite *AuditTestSuite) TestFilterMatches() {
+	entry := AuditEntry{
+		Time:    time.Now(),
+		Actor:   "alice",
+		Method:  "DELETE",
+		Route:   "/api/app/vx-containers/container/:container_uuid",
+		Targets: map[string]string{"container_uuid": "1234"},
+	}
+
+	suite.True(AuditFilter{}.Matches(entry))
+	suite.True(AuditFilter{Actor: "alice", Resource: "containers"}.Matches(entry))
+	suite.True(AuditFilter{Resource: "1234"}.Matches(entry))
+	suite.False(AuditFilter{Actor: "bob"}.Matches(entry))
+	suite.False(AuditFilter{Since: time.Now().Add(time.Minute)}.Matches(entry))
+	suite.False(AuditFilter{Resource: "tunnels"}.Matches(entry))
+}
+
+func (suite *AuditTestSuite) TestEntrySensitive() {
+	suite.True(AuditEntry{Method: "DELETE", Route: "/api/app/vx-containers/container/:container_uuid"}.Sensitive())
+	suite.True(AuditEntry{Method: "POST", Route: "/api/security/ssh"}.Sensitive())
+	suite.True(AuditEntry{Method: "POST", Source: AuditSourceKernel}.Sensitive())
+	suite.False(AuditEntry{Method: "POST", Route: "/api/app/vx-containers/container/:container_uuid/start"}.Sensitive())
+}
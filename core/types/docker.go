@@ -1,21 +1,62 @@
 package types
 
 import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
 	dockertypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/go-connections/nat"
 )
 
+const (
+	DockerEventStart        = "start"
+	DockerEventDie          = "die"
+	DockerEventOOM          = "oom"
+	DockerEventHealthStatus = "health_status"
+	DockerEventDestroy      = "destroy"
+)
+
+// DockerEventActions are the container events streamed by the kernel.
+var DockerEventActions = []string{
+	DockerEventStart,
+	DockerEventDie,
+	DockerEventOOM,
+	DockerEventHealthStatus,
+	DockerEventDestroy,
+}
+
+// DockerEvent is an event of a container sent by the Docker daemon.
+type DockerEvent struct {
+	Action        string    `json:"action"`
+	ContainerID   string    `json:"container_id"`
+	ContainerName string    `json:"container_name,omitempty"`
+	ExitCode      *int      `json:"exit_code,omitempty"`
+	Health        string    `json:"health,omitempty"`
+	Time          time.Time `json:"time"`
+}
+
 type Container struct {
 	ID      string   `json:"id,omitempty"`
+	Image   string   `json:"image,omitempty"`
 	ImageID string   `json:"image_id,omitempty"`
 	Names   []string `json:"names,omitempty"`
 	Mounts  []Mount  `json:"mounts,omitempty"`
+
+	// State is the state of the container, like "running" or "exited".
+	State string `json:"state,omitempty"`
 }
 
 type Mount struct {
+	Type        string `json:"type,omitempty"`
+	Name        string `json:"name,omitempty"`
 	Source      string `json:"source,omitempty"`
 	Destination string `json:"destination,omitempty"`
+	ReadOnly    bool   `json:"read_only,omitempty"`
 }
 
 type CreateContainerOptions struct {
@@ -28,6 +69,46 @@ type CreateContainerOptions struct {
 	CapAdd        []string          `json:"cap_add,omitempty"`
 	Sysctls       map[string]string `json:"sysctls,omitempty"`
 	Cmd           []string          `json:"cmd,omitempty"`
+	Privileged    bool              `json:"privileged,omitempty"`
+	NetworkMode   string            `json:"network_mode,omitempty"`
+	VolumeMounts  []VolumeMount     `json:"volume_mounts,omitempty"`
+
+	// Tmpfs is a map containing the target path as a key, and the tmpfs
+	// options, like "size=64m", as a value.
+	Tmpfs map[string]string `json:"tmpfs,omitempty"`
+}
+
+type StopContainerOptions struct {
+	// Timeout is the number of seconds to wait for the container to stop
+	// gracefully, before killing it. The Docker default is used if nil.
+	Timeout *int `json:"timeout,omitempty"`
+}
+
+// LogsContainerOptions filters the logs of a container. The logs are
+// followed until the container stops.
+type LogsContainerOptions struct {
+	// Tail is the number of past lines to return, or "all". Only the new
+	// lines are returned if empty.
+	Tail string `json:"tail,omitempty"`
+}
+
+// ExecContainerOptions runs a command inside a running container.
+type ExecContainerOptions struct {
+	Cmd []string `json:"cmd"`
+}
+
+// ExecContainerResponse is the output of a command run inside a container.
+type ExecContainerResponse struct {
+	ExitCode int    `json:"exit_code"`
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+}
+
+// VolumeMount mounts a named volume in a container.
+type VolumeMount struct {
+	Name     string `json:"name,omitempty"`
+	Target   string `json:"target,omitempty"`
+	ReadOnly bool   `json:"read_only,omitempty"`
 }
 
 type BuildImageOptions struct {
@@ -45,19 +126,141 @@ type CreateContainerResponse struct {
 	Warnings []string `json:"warnings,omitempty"`
 }
 
+// InfoVersion is the version of the container and image info. It is
+// incremented when a field is removed or changes meaning, so that the API
+// clients can rely on the fields of a given version.
+const InfoVersion = 1
+
 type InfoContainerResponse struct {
-	ID       string `json:"id,omitempty"`
-	Name     string `json:"name,omitempty"`
-	Platform string `json:"platform,omitempty"`
-	Image    string `json:"image,omitempty"`
+	ID       string     `json:"id,omitempty"`
+	Name     string     `json:"name,omitempty"`
+	Platform string     `json:"platform,omitempty"`
+	Image    string     `json:"image,omitempty"`
+	Created  *time.Time `json:"created,omitempty"`
+
+	State     ContainerState     `json:"state"`
+	Mounts    []Mount            `json:"mounts"`
+	Networks  []ContainerNetwork `json:"networks"`
+	Ports     []ContainerPort    `json:"ports"`
+	Env       []string           `json:"env"`
+	Resources ContainerResources `json:"resources"`
+}
+
+type ContainerState struct {
+	Status       string     `json:"status,omitempty"`
+	Running      bool       `json:"running"`
+	ExitCode     int        `json:"exit_code"`
+	OOMKilled    bool       `json:"oom_killed"`
+	Error        string     `json:"error,omitempty"`
+	RestartCount int        `json:"restart_count"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+}
+
+// ContainerNetwork is a network the container is connected to.
+type ContainerNetwork struct {
+	Name       string   `json:"name"`
+	IPAddress  string   `json:"ip_address,omitempty"`
+	Gateway    string   `json:"gateway,omitempty"`
+	MacAddress string   `json:"mac_address,omitempty"`
+	Aliases    []string `json:"aliases,omitempty"`
+}
+
+// ContainerPort is a container port published on the host.
+type ContainerPort struct {
+	// Port is the container port, like "80/tcp".
+	Port     string `json:"port"`
+	HostIP   string `json:"host_ip,omitempty"`
+	HostPort string `json:"host_port,omitempty"`
+}
+
+// ContainerResources are the resource limits of a container. A zero value
+// means no limit.
+type ContainerResources struct {
+	// Memory is the memory limit in bytes.
+	Memory            int64 `json:"memory"`
+	MemoryReservation int64 `json:"memory_reservation"`
+	// NanoCPUs is the CPU quota in units of 10^-9 CPUs.
+	NanoCPUs  int64  `json:"nano_cpus"`
+	CPUShares int64  `json:"cpu_shares"`
+	PidsLimit *int64 `json:"pids_limit,omitempty"`
 }
 
 type InfoImageResponse struct {
-	ID           string   `json:"id,omitempty"`
-	Architecture string   `json:"architecture,omitempty"`
-	OS           string   `json:"os,omitempty"`
-	Size         int64    `json:"size,omitempty"`
-	Tags         []string `json:"tags,omitempty"`
+	ID           string     `json:"id,omitempty"`
+	Architecture string     `json:"architecture,omitempty"`
+	OS           string     `json:"os,omitempty"`
+	Size         int64      `json:"size,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+	Created      *time.Time `json:"created,omitempty"`
+
+	// Layers are the digests of the image layers, from the lowest to the
+	// highest.
+	Layers []string `json:"layers"`
+}
+
+type Image struct {
+	ID      string   `json:"id,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Size    int64    `json:"size,omitempty"`
+	Created int64    `json:"created,omitempty"`
+}
+
+type Volume struct {
+	Name       string            `json:"name,omitempty"`
+	Driver     string            `json:"driver,omitempty"`
+	Mountpoint string            `json:"mountpoint,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+
+	// Size is the disk usage of the volume, or -1 if unknown.
+	Size int64 `json:"size"`
+
+	// RefCount is the number of containers using this volume, or -1 if unknown.
+	RefCount int64 `json:"ref_count"`
+}
+
+type CreateVolumeOptions struct {
+	Name          string            `json:"name,omitempty"`
+	Driver        string            `json:"driver,omitempty"`
+	DriverOptions map[string]string `json:"driver_options,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+}
+
+type Network struct {
+	ID       string            `json:"id,omitempty"`
+	Name     string            `json:"name,omitempty"`
+	Driver   string            `json:"driver,omitempty"`
+	Internal bool              `json:"internal,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+type CreateNetworkOptions struct {
+	Name string `json:"name,omitempty"`
+
+	// Internal networks have no access to the outside.
+	Internal bool              `json:"internal,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+type CreateNetworkResponse struct {
+	ID       string `json:"id,omitempty"`
+	Warnings string `json:"warnings,omitempty"`
+}
+
+type ConnectNetworkOptions struct {
+	// Container is the ID or the name of the container to connect.
+	Container string `json:"container,omitempty"`
+
+	// Aliases are the additional host names of the container in the network.
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+type DisconnectNetworkOptions struct {
+	Container string `json:"container,omitempty"`
+}
+
+type PruneBuildCacheResponse struct {
+	SpaceReclaimed uint64 `json:"space_reclaimed"`
 }
 
 type WaitContainerCondition container.WaitCondition
@@ -65,9 +268,80 @@ type WaitContainerCondition container.WaitCondition
 func NewContainer(c dockertypes.Container) Container {
 	return Container{
 		ID:      c.ID,
+		Image:   c.Image,
 		ImageID: c.ImageID,
 		Names:   c.Names,
 		Mounts:  NewMounts(c.Mounts),
+		State:   c.State,
+	}
+}
+
+func NewImage(i dockertypes.ImageSummary) Image {
+	return Image{
+		ID:      i.ID,
+		Tags:    i.RepoTags,
+		Size:    i.Size,
+		Created: i.Created,
+	}
+}
+
+func NewVolume(v *volume.Volume) Volume {
+	vol := Volume{
+		Name:       v.Name,
+		Driver:     v.Driver,
+		Mountpoint: v.Mountpoint,
+		Labels:     v.Labels,
+		Size:       -1,
+		RefCount:   -1,
+	}
+	if v.UsageData != nil {
+		vol.Size = v.UsageData.Size
+		vol.RefCount = v.UsageData.RefCount
+	}
+	return vol
+}
+
+// NewDockerEvent converts a Docker message to a DockerEvent. It returns
+// false if the message is not a container event streamed by the kernel.
+func NewDockerEvent(m events.Message) (DockerEvent, bool) {
+	if m.Type != events.ContainerEventType {
+		return DockerEvent{}, false
+	}
+
+	// The health status is in the action, like "health_status: healthy".
+	action, health, _ := strings.Cut(m.Action, ":")
+
+	found := false
+	for _, a := range DockerEventActions {
+		if a == action {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return DockerEvent{}, false
+	}
+
+	e := DockerEvent{
+		Action:        action,
+		ContainerID:   m.Actor.ID,
+		ContainerName: m.Actor.Attributes["name"],
+		Health:        strings.TrimSpace(health),
+		Time:          time.Unix(0, m.TimeNano),
+	}
+	if code, err := strconv.Atoi(m.Actor.Attributes["exitCode"]); err == nil {
+		e.ExitCode = &code
+	}
+	return e, true
+}
+
+func NewNetwork(n dockertypes.NetworkResource) Network {
+	return Network{
+		ID:       n.ID,
+		Name:     n.Name,
+		Driver:   n.Driver,
+		Internal: n.Internal,
+		Labels:   n.Labels,
 	}
 }
 
@@ -81,7 +355,108 @@ func NewMounts(m []dockertypes.MountPoint) []Mount {
 
 func NewMount(m dockertypes.MountPoint) Mount {
 	return Mount{
+		Type:        string(m.Type),
+		Name:        m.Name,
 		Source:      m.Source,
 		Destination: m.Destination,
+		ReadOnly:    !m.RW,
+	}
+}
+
+func NewInfoContainer(info dockertypes.ContainerJSON) InfoContainerResponse {
+	res := InfoContainerResponse{
+		Mounts:   NewMounts(info.Mounts),
+		Networks: []ContainerNetwork{},
+		Ports:    []ContainerPort{},
+		Env:      []string{},
+	}
+	if info.ContainerJSONBase != nil {
+		res.ID = info.ID
+		res.Name = info.Name
+		res.Platform = info.Platform
+		res.Image = info.Image
+		res.Created = parseDockerTime(info.Created)
+		res.State.RestartCount = info.RestartCount
+		if info.State != nil {
+			res.State.Status = info.State.Status
+			res.State.Running = info.State.Running
+			res.State.ExitCode = info.State.ExitCode
+			res.State.OOMKilled = info.State.OOMKilled
+			res.State.Error = info.State.Error
+			res.State.StartedAt = parseDockerTime(info.State.StartedAt)
+			res.State.FinishedAt = parseDockerTime(info.State.FinishedAt)
+		}
+		if info.HostConfig != nil {
+			res.Resources = ContainerResources{
+				Memory:            info.HostConfig.Memory,
+				MemoryReservation: info.HostConfig.MemoryReservation,
+				NanoCPUs:          info.HostConfig.NanoCPUs,
+				CPUShares:         info.HostConfig.CPUShares,
+				PidsLimit:         info.HostConfig.PidsLimit,
+			}
+		}
+	}
+	if info.Config != nil && info.Config.Env != nil {
+		res.Env = info.Config.Env
+	}
+	if info.NetworkSettings != nil {
+		for name, n := range info.NetworkSettings.Networks {
+			if n == nil {
+				continue
+			}
+			res.Networks = append(res.Networks, ContainerNetwork{
+				Name:       name,
+				IPAddress:  n.IPAddress,
+				Gateway:    n.Gateway,
+				MacAddress: n.MacAddress,
+				Aliases:    n.Aliases,
+			})
+		}
+		sort.Slice(res.Networks, func(i, j int) bool {
+			return res.Networks[i].Name < res.Networks[j].Name
+		})
+
+		for port, bindings := range info.NetworkSettings.Ports {
+			for _, b := range bindings {
+				res.Ports = append(res.Ports, ContainerPort{
+					Port:     string(port),
+					HostIP:   b.HostIP,
+					HostPort: b.HostPort,
+				})
+			}
+		}
+		sort.Slice(res.Ports, func(i, j int) bool {
+			if res.Ports[i].Port != res.Ports[j].Port {
+				return res.Ports[i].Port < res.Ports[j].Port
+			}
+			return res.Ports[i].HostIP < res.Ports[j].HostIP
+		})
+	}
+	return res
+}
+
+func NewInfoImage(info dockertypes.ImageInspect) InfoImageResponse {
+	res := InfoImageResponse{
+		ID:           info.ID,
+		Architecture: info.Architecture,
+		OS:           info.Os,
+		Size:         info.Size,
+		Tags:         info.RepoTags,
+		Created:      parseDockerTime(info.Created),
+		Layers:       []string{},
+	}
+	if info.RootFS.Layers != nil {
+		res.Layers = info.RootFS.Layers
+	}
+	return res
+}
+
+// parseDockerTime parses a time returned by the Docker API. It returns nil
+// if the time is not set, which Docker reports as the zero time.
+func parseDockerTime(s string) *time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil || t.IsZero() {
+		return nil
 	}
+	return &t
 }
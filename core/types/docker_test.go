@@ -0,0 +1,102 @@
+package types
+
+import (
+	"testing"
+
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/suite"
+)
+
+type DockerEventTestSuite struct {
+	suite.Suite
+}
+
+func TestDockerEventTestSuite(t *testing.T) {
+	suite.Run(t, new(DockerEventTestSuite))
+}
+
+func (suite *DockerEventTestSuite) TestNewDockerEvent() {
+	e, ok := NewDockerEvent(events.Message{
+		Type:   events.ContainerEventType,
+		Action: "die",
+		Actor: events.Actor{
+			ID:         "abc",
+			Attributes: map[string]string{"name": "VERTEX_CONTAINER_1", "exitCode": "137"},
+		},
+	})
+
+	suite.True(ok)
+	suite.Equal(DockerEventDie, e.Action)
+	suite.Equal("abc", e.ContainerID)
+	suite.Equal("VERTEX_CONTAINER_1", e.ContainerName)
+	if suite.NotNil(e.ExitCode) {
+		suite.Equal(137, *e.ExitCode)
+	}
+}
+
+func (suite *DockerEventTestSuite) TestNewDockerEventHealthStatus() {
+	e, ok := NewDockerEvent(events.Message{
+		Type:   events.ContainerEventType,
+		Action: "health_status: unhealthy",
+	})
+
+	suite.True(ok)
+	suite.Equal(DockerEventHealthStatus, e.Action)
+	suite.Equal("unhealthy", e.Health)
+	suite.Nil(e.ExitCode)
+}
+
+func (suite *DockerEventTestSuite) TestNewDockerEventIgnored() {
+	_, ok := NewDockerEvent(events.Message{Type: events.ContainerEventType, Action: "exec_start: sh"})
+	suite.False(ok)
+
+	_, ok = NewDockerEvent(events.Message{Type: events.NetworkEventType, Action: "destroy"})
+	suite.False(ok)
+}
+
+func (suite *DockerEventTestSuite) TestNewInfoContainer() {
+	info := NewInfoContainer(dockertypes.ContainerJSON{
+		ContainerJSONBase: &dockertypes.ContainerJSONBase{
+			ID:           "abc",
+			Created:      "2023-10-01T12:00:00.5Z",
+			RestartCount: 2,
+			State: &dockertypes.ContainerState{
+				Status:     "exited",
+				ExitCode:   137,
+				OOMKilled:  true,
+				StartedAt:  "2023-10-01T12:00:01Z",
+				FinishedAt: "0001-01-01T00:00:00Z",
+			},
+			HostConfig: &container.HostConfig{
+				Resources: container.Resources{Memory: 512 * 1024 * 1024},
+			},
+		},
+		Mounts: []dockertypes.MountPoint{{Type: "volume", Name: "data", Destination: "/data"}},
+		Config: &container.Config{Env: []string{"A=1"}},
+		NetworkSettings: &dockertypes.NetworkSettings{
+			NetworkSettingsBase: dockertypes.NetworkSettingsBase{
+				Ports: nat.PortMap{"80/tcp": {{HostIP: "0.0.0.0", HostPort: "8080"}}},
+			},
+			Networks: map[string]*network.EndpointSettings{
+				"vertex": {IPAddress: "172.18.0.2"},
+			},
+		},
+	})
+
+	suite.Equal("abc", info.ID)
+	suite.Equal(137, info.State.ExitCode)
+	suite.True(info.State.OOMKilled)
+	suite.Equal(2, info.State.RestartCount)
+	suite.NotNil(info.State.StartedAt)
+	suite.Nil(info.State.FinishedAt)
+	suite.NotNil(info.Created)
+	suite.Equal([]Mount{{Type: "volume", Name: "data", Destination: "/data", ReadOnly: true}}, info.Mounts)
+	suite.Equal([]ContainerNetwork{{Name: "vertex", IPAddress: "172.18.0.2"}}, info.Networks)
+	suite.Equal([]ContainerPort{{Port: "80/tcp", HostIP: "0.0.0.0", HostPort: "8080"}}, info.Ports)
+	suite.Equal([]string{"A=1"}, info.Env)
+	suite.Equal(int64(512*1024*1024), info.Resources.Memory)
+}
@@ -0,0 +1,213 @@
+package types
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/vertex-center/vertex/pkg/storage"
+)
+
+const (
+	KernelPolicyRuleCapabilities = "allowed_capabilities"
+	KernelPolicyRuleBinds        = "allowed_bind_prefixes"
+	KernelPolicyRuleSysctls      = "forbidden_sysctls"
+	KernelPolicyRulePrivileged   = "allow_privileged"
+	KernelPolicyRuleHostNetwork  = "allow_host_network"
+)
+
+// KernelPolicy restricts the privileged options of the containers created
+// by the kernel, which runs as root.
+type KernelPolicy struct {
+	// AllowedCapabilities are the capabilities that can be added to a
+	// container, without the CAP_ prefix.
+	AllowedCapabilities []string `json:"allowed_capabilities"`
+
+	// AllowedBindPrefixes are the host directories that can be mounted in
+	// a container. Relative paths are relative to the kernel directory.
+	AllowedBindPrefixes []string `json:"allowed_bind_prefixes"`
+
+	// ForbiddenSysctls are the sysctls that cannot be set. A sysctl ending
+	// with ".*" forbids all the sysctls starting with it.
+	ForbiddenSysctls []string `json:"forbidden_sysctls"`
+
+	AllowPrivileged  bool `json:"allow_privileged"`
+	AllowHostNetwork bool `json:"allow_host_network"`
+}
+
+// KernelPolicyViolation is the rule of the policy that a container breaks.
+type KernelPolicyViolation struct {
+	Rule    string `json:"rule"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+func (v KernelPolicyViolation) Error() string {
+	return v.Message
+}
+
+// DefaultKernelPolicy only allows the options needed by the Vertex services.
+// The containers can only bind the directories of the containers, and not
+// the other data of Vertex, like the authentication data.
+func DefaultKernelPolicy() KernelPolicy {
+	return KernelPolicy{
+		AllowedCapabilities: []string{"NET_ADMIN", "NET_BIND_SERVICE", "NET_RAW"},
+		AllowedBindPrefixes: []string{path.Join(storage.Path, "apps", "vx-containers")},
+		ForbiddenSysctls:    []string{"kernel.*", "vm.*", "fs.*"},
+		AllowPrivileged:     false,
+		AllowHostNetwork:    false,
+	}
+}
+
+// Check returns the first violation of the policy by the container
+// options, or nil if the options are allowed.
+func (p KernelPolicy) Check(options CreateContainerOptions) *KernelPolicyViolation {
+	if options.Privileged && !p.AllowPrivileged {
+		return &KernelPolicyViolation{
+			Rule:    KernelPolicyRulePrivileged,
+			Message: "Privileged containers are not allowed.",
+		}
+	}
+
+	if !p.AllowHostNetwork && !allowsNetworkMode(options.NetworkMode) {
+		return &KernelPolicyViolation{
+			Rule:    KernelPolicyRuleHostNetwork,
+			Value:   options.NetworkMode,
+			Message: fmt.Sprintf("The network mode '%s' is not allowed.", options.NetworkMode),
+		}
+	}
+
+	for _, c := range options.CapAdd {
+		if !p.allowsCapability(c) {
+			return &KernelPolicyViolation{
+				Rule:    KernelPolicyRuleCapabilities,
+				Value:   c,
+				Message: fmt.Sprintf("The capability '%s' is not allowed.", c),
+			}
+		}
+	}
+
+	for _, b := range options.Binds {
+		if !p.allowsBind(b) {
+			return &KernelPolicyViolation{
+				Rule:    KernelPolicyRuleBinds,
+				Value:   b,
+				Message: fmt.Sprintf("The bind '%s' is outside of the allowed directories.", b),
+			}
+		}
+	}
+
+	for s := range options.Sysctls {
+		if p.forbidsSysctl(s) {
+			return &KernelPolicyViolation{
+				Rule:    KernelPolicyRuleSysctls,
+				Value:   s,
+				Message: fmt.Sprintf("The sysctl '%s' is forbidden.", s),
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckVolume returns the violation of the policy by the volume options, or
+// nil if the volume is allowed. The local driver can bind a host directory
+// with the device option, which must be in an allowed directory.
+func (p KernelPolicy) CheckVolume(options CreateVolumeOptions) *KernelPolicyViolation {
+	device, ok := options.DriverOptions["device"]
+	if ok && !p.allowsBind(device) {
+		return &KernelPolicyViolation{
+			Rule:    KernelPolicyRuleBinds,
+			Value:   device,
+			Message: fmt.Sprintf("The volume device '%s' is outside of the allowed directories.", device),
+		}
+	}
+	return nil
+}
+
+func (p KernelPolicy) allowsCapability(capability string) bool {
+	capability = normalizeCapability(capability)
+	for _, c := range p.AllowedCapabilities {
+		if normalizeCapability(c) == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsBind checks that the host path of the bind is inside an allowed
+// directory. Named volumes are always allowed.
+func (p KernelPolicy) allowsBind(bind string) bool {
+	source, _, _ := strings.Cut(bind, ":")
+	if !filepath.IsAbs(source) {
+		return true
+	}
+
+	source = resolvePath(source)
+	for _, prefix := range p.AllowedBindPrefixes {
+		prefix = resolvePath(prefix)
+		if source == prefix || strings.HasPrefix(source, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsNetworkMode checks that the network mode is bridge, none, or a named
+// network. The other modes, like host or "container:<id>", can share the
+// network of the host, possibly through another container.
+func allowsNetworkMode(mode string) bool {
+	switch mode {
+	case "", "default", "bridge", "none":
+		return true
+	case "host":
+		return false
+	}
+	// The names of the networks can't contain a colon, unlike the modes
+	// joining another namespace.
+	return !strings.Contains(mode, ":")
+}
+
+func (p KernelPolicy) forbidsSysctl(sysctl string) bool {
+	for _, s := range p.ForbiddenSysctls {
+		if s == sysctl {
+			return true
+		}
+		prefix, ok := strings.CutSuffix(s, "*")
+		if ok && strings.HasPrefix(sysctl, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeCapability(capability string) string {
+	return strings.TrimPrefix(strings.ToUpper(capability), "CAP_")
+}
+
+// resolvePath returns the absolute path, with its symbolic links resolved
+// so that they cannot be used to escape an allowed directory. The links of
+// the parents are resolved even if the path doesn't exist yet, because
+// Docker creates the missing directories.
+func resolvePath(p string) string {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return filepath.Clean(p)
+	}
+
+	rest := ""
+	dir := abs
+	for {
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return abs
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+}
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type KernelPolicyTestSuite struct {
+	suite.Suite
+
+	dir    string
+	policy KernelPolicy
+}
+
+func TestKernelPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(KernelPolicyTestSuite))
+}
+
+func (suite *KernelPolicyTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+	suite.policy = KernelPolicy{
+		AllowedCapabilities: []string{"NET_ADMIN"},
+		AllowedBindPrefixes: []string{path.Join(suite.dir, "volumes")},
+		ForbiddenSysctls:    []string{"kernel.*", "net.core.somaxconn"},
+	}
+}
+
+func (suite *KernelPolicyTestSuite) TestAllowed() {
+	violation := suite.policy.Check(CreateContainerOptions{
+		CapAdd:      []string{"CAP_NET_ADMIN", "net_admin"},
+		Binds:       []string{path.Join(suite.dir, "volumes", "data") + ":/data", "data:/data"},
+		Sysctls:     map[string]string{"net.ipv4.ip_forward": "1"},
+		NetworkMode: "vertex_network",
+	})
+	suite.Nil(violation)
+}
+
+func (suite *KernelPolicyTestSuite) TestViolations() {
+	tests := []struct {
+		options CreateContainerOptions
+		rule    string
+	}{
+		{CreateContainerOptions{Privileged: true}, KernelPolicyRulePrivileged},
+		{CreateContainerOptions{NetworkMode: "host"}, KernelPolicyRuleHostNetwork},
+		{CreateContainerOptions{NetworkMode: "container:vertex"}, KernelPolicyRuleHostNetwork},
+		{CreateContainerOptions{NetworkMode: "ns:/proc/1/ns/net"}, KernelPolicyRuleHostNetwork},
+		{CreateContainerOptions{CapAdd: []string{"SYS_ADMIN"}}, KernelPolicyRuleCapabilities},
+		{CreateContainerOptions{Binds: []string{"/:/host"}}, KernelPolicyRuleBinds},
+		{CreateContainerOptions{Binds: []string{path.Join(suite.dir, "volumes-other") + ":/data"}}, KernelPolicyRuleBinds},
+		{CreateContainerOptions{Binds: []string{path.Join(suite.dir, "volumes", "..", "etc") + ":/data"}}, KernelPolicyRuleBinds},
+		{CreateContainerOptions{Sysctls: map[string]string{"kernel.shmmax": "1"}}, KernelPolicyRuleSysctls},
+		{CreateContainerOptions{Sysctls: map[string]string{"net.core.somaxconn": "1"}}, KernelPolicyRuleSysctls},
+	}
+
+	for _, test := range tests {
+		violation := suite.policy.Check(test.options)
+		if suite.NotNil(violation) {
+			suite.Equal(test.rule, violation.Rule)
+		}
+	}
+}
+
+func (suite *KernelPolicyTestSuite) TestBindSymlink() {
+	volumes := path.Join(suite.dir, "volumes")
+	suite.Require().NoError(os.MkdirAll(volumes, os.ModePerm))
+	suite.Require().NoError(os.Symlink("/", path.Join(volumes, "root")))
+
+	violation := suite.policy.Check(CreateContainerOptions{
+		Binds: []string{path.Join(volumes, "root", "etc", "new") + ":/data"},
+	})
+	if suite.NotNil(violation) {
+		suite.Equal(KernelPolicyRuleBinds, violation.Rule)
+	}
+}
+
+func (suite *KernelPolicyTestSuite) TestCheckVolume() {
+	suite.Nil(suite.policy.CheckVolume(CreateVolumeOptions{Name: "data"}))
+	suite.Nil(suite.policy.CheckVolume(CreateVolumeOptions{
+		DriverOptions: map[string]string{"type": "nfs", "device": ":/exports/data"},
+	}))
+
+	violation := suite.policy.CheckVolume(CreateVolumeOptions{
+		DriverOptions: map[string]string{"type": "none", "o": "bind", "device": "/etc"},
+	})
+	if suite.NotNil(violation) {
+		suite.Equal(KernelPolicyRuleBinds, violation.Rule)
+	}
+}
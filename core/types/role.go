@@ -0,0 +1,156 @@
+package types
+
+import (
+	"errors"
+	"strings"
+)
+
+// Permission allows an action on a kind of resource. A permission ending
+// with ":*" grants all the permissions sharing its prefix, and "*" grants
+// everything.
+type Permission string
+
+const (
+	PermissionAll Permission = "*"
+
+	PermissionSettingsRead  Permission = "settings:read"
+	PermissionSettingsWrite Permission = "settings:write"
+	PermissionSshRead       Permission = "ssh:read"
+	PermissionSshWrite      Permission = "ssh:write"
+	PermissionUpdatesRead   Permission = "updates:read"
+	PermissionUpdatesWrite  Permission = "updates:write"
+	PermissionAuditRead     Permission = "audit:read"
+
+	PermissionContainersRead     Permission = "containers:read"
+	PermissionContainersWrite    Permission = "containers:write"
+	PermissionContainersStart    Permission = "containers:start"
+	PermissionContainersEnvWrite Permission = "containers:env:write"
+
+	PermissionProxyRead  Permission = "proxy:read"
+	PermissionProxyWrite Permission = "proxy:write"
+
+	PermissionSqlRead         Permission = "sql:read"
+	PermissionSqlWrite        Permission = "sql:write"
+	PermissionTunnelsWrite    Permission = "tunnels:write"
+	PermissionMonitoringRead  Permission = "monitoring:read"
+	PermissionMonitoringWrite Permission = "monitoring:write"
+)
+
+// Permissions are all the permissions that can be given to a role.
+var Permissions = []Permission{
+	PermissionSettingsRead,
+	PermissionSettingsWrite,
+	PermissionSshRead,
+	PermissionSshWrite,
+	PermissionUpdatesRead,
+	PermissionUpdatesWrite,
+	PermissionAuditRead,
+	PermissionContainersRead,
+	PermissionContainersWrite,
+	PermissionContainersStart,
+	PermissionContainersEnvWrite,
+	PermissionProxyRead,
+	PermissionProxyWrite,
+	PermissionSqlRead,
+	PermissionSqlWrite,
+	PermissionTunnelsWrite,
+	PermissionMonitoringRead,
+	PermissionMonitoringWrite,
+}
+
+var (
+	ErrRoleNotFound          = errors.New("role not found")
+	ErrRoleNameInvalid       = errors.New("role name is invalid")
+	ErrRolePermissionInvalid = errors.New("role permission is invalid")
+)
+
+// Grants returns true if the permission p grants the required permission.
+func (p Permission) Grants(required Permission) bool {
+	if p == PermissionAll || p == required {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(string(p), "*")
+	return ok && strings.HasSuffix(prefix, ":") && strings.HasPrefix(string(required), prefix)
+}
+
+// Valid returns true if the permission is known, or is a wildcard matching
+// at least one known permission.
+func (p Permission) Valid() bool {
+	for _, perm := range Permissions {
+		if p.Grants(perm) {
+			return true
+		}
+	}
+	return false
+}
+
+type Role struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Permissions []Permission `json:"permissions"`
+
+	// Tags limits the permissions of the role to the containers having at
+	// least one of these tags. If empty, the role applies to everything.
+	Tags []string `json:"tags,omitempty"`
+}
+
+func (r Role) Validate() error {
+	if r.Name == "" || strings.ContainsAny(r.Name, " /") {
+		return ErrRoleNameInvalid
+	}
+	for _, p := range r.Permissions {
+		if !p.Valid() {
+			return ErrRolePermissionInvalid
+		}
+	}
+	return nil
+}
+
+func (r Role) Grants(required Permission) bool {
+	for _, p := range r.Permissions {
+		if p.Grants(required) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorization is the result of a permission check.
+type Authorization struct {
+	Allowed bool
+
+	// Tags are the container tags the permission is limited to. It is nil
+	// if the permission is not limited.
+	Tags []string
+}
+
+// AllowsTags returns true if the authorization allows a resource having
+// the given tags.
+func (a Authorization) AllowsTags(tags []string) bool {
+	if !a.Allowed {
+		return false
+	}
+	if a.Tags == nil {
+		return true
+	}
+	for _, tag := range tags {
+		if contains(a.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetUserRolesOptions are the roles to assign to a user.
+type SetUserRolesOptions struct {
+	Roles []string `json:"roles"`
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RoleTestSuite struct {
+	suite.Suite
+}
+
+func TestRoleTestSuite(t *testing.T) {
+	suite.Run(t, new(RoleTestSuite))
+}
+
+func (suite *RoleTestSuite) TestPermissionGrants() {
+	suite.True(PermissionAll.Grants(PermissionSettingsWrite))
+	suite.True(PermissionContainersRead.Grants(PermissionContainersRead))
+	suite.False(PermissionContainersRead.Grants(PermissionContainersStart))
+
+	suite.True(Permission("containers:*").Grants(PermissionContainersEnvWrite))
+	suite.False(Permission("containers:*").Grants(PermissionProxyWrite))
+	suite.False(Permission("containers*").Grants(PermissionContainersRead))
+}
+
+func (suite *RoleTestSuite) TestValidate() {
+	suite.NoError(Role{Name: "family", Permissions: []Permission{"containers:*", PermissionProxyRead}}.Validate())
+	suite.ErrorIs(Role{Name: "family", Permissions: []Permission{"containers:delete_all"}}.Validate(), ErrRolePermissionInvalid)
+	suite.ErrorIs(Role{Name: "", Permissions: []Permission{}}.Validate(), ErrRoleNameInvalid)
+}
+
+func (suite *RoleTestSuite) TestAuthorizationAllowsTags() {
+	suite.True(Authorization{Allowed: true}.AllowsTags(nil))
+	suite.False(Authorization{}.AllowsTags(nil))
+
+	auth := Authorization{Allowed: true, Tags: []string{"media"}}
+	suite.True(auth.AllowsTags([]string{"media", "public"}))
+	suite.False(auth.AllowsTags([]string{"database"}))
+	suite.False(auth.AllowsTags(nil))
+}
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenPrefix is the prefix of the API tokens, to tell them apart from the
+// session tokens.
+const TokenPrefix = "vxt_"
+
+var (
+	ErrTokenNotFound     = errors.New("token not found")
+	ErrTokenExpired      = errors.New("token expired")
+	ErrTokenNameInvalid  = errors.New("token name is invalid")
+	ErrTokenScopeInvalid = errors.New("token scope is invalid")
+)
+
+// Token is a long-lived API token, used by scripts to access the API.
+type Token struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	UserID uuid.UUID `json:"user_id"`
+
+	// Scopes limits the permissions of the token. The token never has more
+	// permissions than its user.
+	Scopes []Permission `json:"scopes"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+
+	// Hash is the SHA-256 hash of the token. The token itself is only shown
+	// once, when created.
+	Hash string `json:"-"`
+}
+
+func (t Token) Expired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+type CreateTokenOptions struct {
+	Name      string       `json:"name"`
+	Scopes    []Permission `json:"scopes"`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty"`
+}
+
+func (o CreateTokenOptions) Validate() error {
+	if o.Name == "" {
+		return ErrTokenNameInvalid
+	}
+	if len(o.Scopes) == 0 {
+		return ErrTokenScopeInvalid
+	}
+	for _, s := range o.Scopes {
+		if !s.Valid() {
+			return ErrTokenScopeInvalid
+		}
+	}
+	return nil
+}
+
+// CreateTokenResponse is sent once, when a token is created.
+type CreateTokenResponse struct {
+	Token  Token  `json:"token"`
+	Secret string `json:"secret"`
+}
+
+// Authentication is the identity of a request.
+type Authentication struct {
+	User User
+
+	// Scopes limits the permissions of the request, when authenticated with
+	// a token. It is nil for the sessions.
+	Scopes []Permission
+}
+
+// Grants returns true if the scopes of the authentication allow the
+// permission. The roles of the user must be checked separately.
+func (a Authentication) Grants(permission Permission) bool {
+	if a.Scopes == nil {
+		return true
+	}
+	for _, s := range a.Scopes {
+		if s.Grants(permission) {
+			return true
+		}
+	}
+	return false
+}
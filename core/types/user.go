@@ -0,0 +1,83 @@
+package types
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// SessionCookie is the name of the cookie holding the session token.
+	SessionCookie = "vertex_session"
+
+	// SessionDuration is the time a session stays valid after the login.
+	SessionDuration = 7 * 24 * time.Hour
+
+	// PasswordMinLength is the minimum length of a user password.
+	PasswordMinLength = 8
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserAlreadyExists  = errors.New("user already exists")
+	ErrUsernameInvalid    = errors.New("username is invalid")
+	ErrPasswordTooShort   = errors.New("password is too short")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrSessionExpired     = errors.New("session expired")
+	ErrSetupAlreadyDone   = errors.New("the setup was already done")
+	ErrLastAdmin          = errors.New("the last admin cannot be deleted")
+)
+
+type User struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Admin     bool      `json:"admin"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Roles are the names of the roles of the user. Admins have all the
+	// permissions regardless of their roles.
+	Roles []string `json:"roles,omitempty"`
+
+	// PasswordHash is the bcrypt hash of the password. It is never sent
+	// to the clients.
+	PasswordHash string `json:"-"`
+}
+
+// Credentials are the username and password sent by a user to log in.
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CreateUserOptions are the options to create a new user.
+type CreateUserOptions struct {
+	Credentials
+	Admin bool `json:"admin"`
+}
+
+type Session struct {
+	// TokenHash is the SHA-256 hash of the session token. The token itself
+	// is only known by the client.
+	TokenHash string    `json:"token_hash"`
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (s Session) Expired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
+
+// LoginResponse is sent to the client after a successful login.
+type LoginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      User      `json:"user"`
+}
+
+// SetupStatus tells whether the first admin still needs to be created.
+type SetupStatus struct {
+	Required bool `json:"required"`
+}
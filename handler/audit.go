@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+// auditDetailsKey is the key of the details added by the handlers to the
+// audit entry of the request.
+const auditDetailsKey = "audit_details"
+
+type AuditHandler struct {
+	auditService port.AuditService
+}
+
+func NewAuditHandler(auditService port.AuditService) port.AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+func (h *AuditHandler) Get(c *router.Context) {
+	filter, err := getAuditFilter(c)
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           api.ErrInvalidAuditFilter,
+			PublicMessage:  "The audit filter is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	entries, err := h.auditService.Query(filter)
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToGetAuditEntries,
+			PublicMessage:  "Failed to get the audit entries.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(entries)
+}
+
+// Record records the requests that can change something, once they are
+// handled. It must run before the authentication, so that the rejected
+// requests are recorded too.
+func (h *AuditHandler) Record(c *router.Context) {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		c.Next()
+		return
+	}
+	if !strings.HasPrefix(c.Request.URL.Path, "/api/") {
+		c.Next()
+		return
+	}
+
+	c.Next()
+
+	entry := types.AuditEntry{
+		IP:      c.ClientIP(),
+		Method:  c.Request.Method,
+		Route:   c.FullPath(),
+		Path:    c.Request.URL.Path,
+		Status:  c.Writer.Status(),
+		Success: c.Writer.Status() < http.StatusBadRequest,
+	}
+
+	if user, ok := currentUser(c); ok {
+		entry.Actor = user.Username
+		if user.ID != uuid.Nil {
+			entry.ActorID = &user.ID
+		}
+	}
+
+	if len(c.Params) > 0 {
+		entry.Targets = map[string]string{}
+		for _, p := range c.Params {
+			entry.Targets[p.Key] = p.Value
+		}
+	}
+
+	if details, ok := c.Get(auditDetailsKey); ok {
+		entry.Details, _ = details.(map[string]string)
+	}
+
+	err := h.auditService.Record(entry)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// setAuditDetail adds a detail to the audit entry of the request.
+func setAuditDetail(c *router.Context, key string, value string) {
+	var details map[string]string
+	if v, ok := c.Get(auditDetailsKey); ok {
+		details, _ = v.(map[string]string)
+	}
+	if details == nil {
+		details = map[string]string{}
+		c.Set(auditDetailsKey, details)
+	}
+	details[key] = value
+}
+
+func getAuditFilter(c *router.Context) (types.AuditFilter, error) {
+	filter := types.AuditFilter{
+		Actor:    c.Query("actor"),
+		Resource: c.Query("resource"),
+	}
+
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return filter, err
+		}
+		filter.Since = t
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return filter, err
+		}
+		filter.Limit = l
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
+	"github.com/vertex-center/vertex/core/types/app"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+const (
+	// userKey is the key of the authenticated user in the request context.
+	userKey = "user"
+
+	// authenticationKey is the key of the identity of the request, with the
+	// scopes of its token, in the request context.
+	authenticationKey = "authentication"
+)
+
+// publicRoutes are the API routes that can be accessed without being
+// authenticated.
+var publicRoutes = map[string]bool{
+	"/api/about":       true,
+	"/api/auth/setup":  true,
+	"/api/auth/login":  true,
+	"/api/auth/logout": true,
+}
+
+type AuthHandler struct {
+	authService port.AuthService
+	roleService port.RoleService
+}
+
+func NewAuthHandler(authService port.AuthService, roleService port.RoleService) port.AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+		roleService: roleService,
+	}
+}
+
+func (h *AuthHandler) GetSetup(c *router.Context) {
+	required, err := h.authService.SetupRequired()
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToGetSetup,
+			PublicMessage:  "Failed to get the setup status.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(types.SetupStatus{
+		Required: required,
+	})
+}
+
+func (h *AuthHandler) Setup(c *router.Context) {
+	var credentials types.Credentials
+	err := c.ParseBody(&credentials)
+	if err != nil {
+		return
+	}
+
+	user, err := h.authService.Setup(credentials)
+	if errors.Is(err, types.ErrSetupAlreadyDone) {
+		c.Conflict(router.Error{
+			Code:           api.ErrSetupAlreadyDone,
+			PublicMessage:  "The setup was already done.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		abortWithUserError(c, err, router.Error{
+			Code:           api.ErrFailedToSetup,
+			PublicMessage:  "Failed to create the admin.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.Context.JSON(http.StatusCreated, user)
+}
+
+func (h *AuthHandler) Login(c *router.Context) {
+	var credentials types.Credentials
+	err := c.ParseBody(&credentials)
+	if err != nil {
+		return
+	}
+
+	res, err := h.authService.Login(credentials)
+	if errors.Is(err, types.ErrInvalidCredentials) {
+		c.AbortWithCode(http.StatusUnauthorized, router.Error{
+			Code:           api.ErrInvalidCredentials,
+			PublicMessage:  "Invalid username or password.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToLogin,
+			PublicMessage:  "Failed to log in.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	setSessionCookie(c, res.Token, int(time.Until(res.ExpiresAt).Seconds()))
+	c.JSON(res)
+}
+
+func (h *AuthHandler) Logout(c *router.Context) {
+	token := getToken(c)
+	if token != "" {
+		err := h.authService.Logout(token)
+		if err != nil && !errors.Is(err, types.ErrSessionNotFound) {
+			c.Abort(router.Error{
+				Code:           api.ErrFailedToLogout,
+				PublicMessage:  "Failed to log out.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+	}
+
+	setSessionCookie(c, "", -1)
+	c.OK()
+}
+
+func (h *AuthHandler) Me(c *router.Context) {
+	user, _ := currentUser(c)
+	c.JSON(user)
+}
+
+func (h *AuthHandler) Authenticate(c *router.Context) {
+	if !strings.HasPrefix(c.Request.URL.Path, "/api/") || publicRoutes[c.FullPath()] {
+		c.Next()
+		return
+	}
+
+	auth, err := h.authService.Authenticate(getToken(c))
+	if err != nil {
+		c.AbortWithCode(http.StatusUnauthorized, router.Error{
+			Code:           router.ErrUnauthorized,
+			PublicMessage:  "The request is not authenticated.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.Set(userKey, auth.User)
+	c.Set(authenticationKey, auth)
+	c.Next()
+}
+
+func (h *AuthHandler) Require(permission types.Permission, scope app.ScopeFunc) router.HandlerFunc {
+	return func(c *router.Context) {
+		auth, ok := h.authorize(c, permission)
+		if !ok {
+			return
+		}
+
+		if auth.Tags != nil {
+			var tags []string
+			var err error
+			if scope != nil {
+				tags, err = scope(c)
+			}
+			if scope == nil || err != nil || !auth.AllowsTags(tags) {
+				abortForbidden(c, permission)
+				return
+			}
+			app.SetAllowedTags(c, auth.Tags)
+		}
+
+		c.Next()
+	}
+}
+
+func (h *AuthHandler) RequireFiltered(permission types.Permission) router.HandlerFunc {
+	return func(c *router.Context) {
+		auth, ok := h.authorize(c, permission)
+		if !ok {
+			return
+		}
+
+		if auth.Tags != nil {
+			app.SetAllowedTags(c, auth.Tags)
+		}
+
+		c.Next()
+	}
+}
+
+// authorize checks that the current user has the permission. The request
+// is aborted if not.
+func (h *AuthHandler) authorize(c *router.Context, permission types.Permission) (types.Authorization, bool) {
+	if !currentAuthentication(c).Grants(permission) {
+		abortForbidden(c, permission)
+		return types.Authorization{}, false
+	}
+
+	user, _ := currentUser(c)
+
+	auth, err := h.roleService.Authorize(user, permission)
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrInternalError,
+			PublicMessage:  "Failed to check the permissions.",
+			PrivateMessage: err.Error(),
+		})
+		return types.Authorization{}, false
+	}
+
+	if !auth.Allowed {
+		abortForbidden(c, permission)
+		return types.Authorization{}, false
+	}
+	return auth, true
+}
+
+func abortForbidden(c *router.Context, permission types.Permission) {
+	c.AbortWithCode(http.StatusForbidden, router.Error{
+		Code:           api.ErrForbidden,
+		PublicMessage:  fmt.Sprintf("This action requires the '%s' permission.", permission),
+		PrivateMessage: "The user doesn't have the permission.",
+	})
+}
+
+// getToken returns the token of the request, sent either in the
+// Authorization header or in the session cookie.
+func getToken(c *router.Context) string {
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if found {
+		return token
+	}
+	token, err := c.Cookie(types.SessionCookie)
+	if err != nil {
+		return ""
+	}
+	return token
+}
+
+// setSessionCookie sets the session cookie. A negative maxAge deletes it.
+func setSessionCookie(c *router.Context, token string, maxAge int) {
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(types.SessionCookie, token, maxAge, "/", "", c.Request.TLS != nil, true)
+}
+
+// currentAuthentication returns the identity set by the Authenticate
+// middleware. Without it, no permission is granted.
+func currentAuthentication(c *router.Context) types.Authentication {
+	v, ok := c.Get(authenticationKey)
+	if !ok {
+		return types.Authentication{Scopes: []types.Permission{}}
+	}
+	auth, ok := v.(types.Authentication)
+	if !ok {
+		return types.Authentication{Scopes: []types.Permission{}}
+	}
+	return auth
+}
+
+// currentUser returns the user authenticated by the Authenticate middleware.
+func currentUser(c *router.Context) (types.User, bool) {
+	v, ok := c.Get(userKey)
+	if !ok {
+		return types.User{}, false
+	}
+	user, ok := v.(types.User)
+	return user, ok
+}
@@ -2,13 +2,19 @@ package handler
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"github.com/vertex-center/vertex/core/port"
 	"github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/core/types/api"
 	"io"
+	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/gin-contrib/sse"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/router"
 )
@@ -24,7 +30,7 @@ func NewDockerKernelHandler(dockerKernelService port.DockerService) port.DockerK
 }
 
 func (h *DockerKernelHandler) GetContainers(c *router.Context) {
-	containers, err := h.dockerService.ListContainers()
+	containers, err := h.dockerService.ListContainers(c.Request.Context())
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToListContainers,
@@ -44,8 +50,37 @@ func (h *DockerKernelHandler) CreateContainer(c *router.Context) {
 		return
 	}
 
-	res, err := h.dockerService.CreateContainer(options)
-	if err != nil {
+	setAuditDetail(c, "image", options.ImageName)
+	setAuditDetail(c, "container_name", options.ContainerName)
+	if len(options.CapAdd) > 0 {
+		setAuditDetail(c, "cap_add", strings.Join(options.CapAdd, ","))
+	}
+	if len(options.Sysctls) > 0 {
+		setAuditDetail(c, "sysctls", fmt.Sprintf("%v", options.Sysctls))
+	}
+	if len(options.Binds) > 0 {
+		setAuditDetail(c, "binds", strings.Join(options.Binds, ","))
+	}
+	if len(options.VolumeMounts) > 0 {
+		var names []string
+		for _, v := range options.VolumeMounts {
+			names = append(names, v.Name)
+		}
+		setAuditDetail(c, "volumes", strings.Join(names, ","))
+	}
+
+	res, err := h.dockerService.CreateContainer(c.Request.Context(), options)
+	var violation types.KernelPolicyViolation
+	if errors.As(err, &violation) {
+		setAuditDetail(c, "policy_rule", violation.Rule)
+		c.AbortWithCode(http.StatusForbidden, router.Error{
+			Code:           api.ErrKernelPolicyViolation,
+			PublicMessage:  fmt.Sprintf("The container was rejected by the kernel policy: %s", violation.Message),
+			PrivateMessage: err.Error(),
+			Details:        violation,
+		})
+		return
+	} else if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToCreateContainer,
 			PublicMessage:  "Failed to create container.",
@@ -60,7 +95,7 @@ func (h *DockerKernelHandler) CreateContainer(c *router.Context) {
 func (h *DockerKernelHandler) DeleteContainer(c *router.Context) {
 	id := c.Param("id")
 
-	err := h.dockerService.DeleteContainer(id)
+	err := h.dockerService.DeleteContainer(c.Request.Context(), id)
 	if err != nil && client.IsErrNotFound(err) {
 		c.NotFound(router.Error{
 			Code:           api.ErrContainerNotFound,
@@ -83,7 +118,7 @@ func (h *DockerKernelHandler) DeleteContainer(c *router.Context) {
 func (h *DockerKernelHandler) StartContainer(c *router.Context) {
 	id := c.Param("id")
 
-	err := h.dockerService.StartContainer(id)
+	err := h.dockerService.StartContainer(c.Request.Context(), id)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToStartContainer,
@@ -99,7 +134,21 @@ func (h *DockerKernelHandler) StartContainer(c *router.Context) {
 func (h *DockerKernelHandler) StopContainer(c *router.Context) {
 	id := c.Param("id")
 
-	err := h.dockerService.StopContainer(id)
+	var options types.StopContainerOptions
+	if timeout := c.Query("timeout"); timeout != "" {
+		t, err := strconv.Atoi(timeout)
+		if err != nil || t < 0 {
+			c.BadRequest(router.Error{
+				Code:           api.ErrInvalidStopTimeout,
+				PublicMessage:  fmt.Sprintf("Invalid stop timeout '%s'.", timeout),
+				PrivateMessage: fmt.Sprintf("the stop timeout must be a positive number of seconds: %s", timeout),
+			})
+			return
+		}
+		options.Timeout = &t
+	}
+
+	err := h.dockerService.StopContainer(c.Request.Context(), id, options)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToStopContainer,
@@ -115,8 +164,15 @@ func (h *DockerKernelHandler) StopContainer(c *router.Context) {
 func (h *DockerKernelHandler) InfoContainer(c *router.Context) {
 	id := c.Param("id")
 
-	info, err := h.dockerService.InfoContainer(id)
-	if err != nil {
+	info, err := h.dockerService.InfoContainer(c.Request.Context(), id)
+	if err != nil && client.IsErrNotFound(err) {
+		c.NotFound(router.Error{
+			Code:           api.ErrContainerNotFound,
+			PublicMessage:  fmt.Sprintf("Container %s not found.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToGetContainerInfo,
 			PublicMessage:  fmt.Sprintf("Failed to get info for container %s.", id),
@@ -131,7 +187,9 @@ func (h *DockerKernelHandler) InfoContainer(c *router.Context) {
 func (h *DockerKernelHandler) LogsStdoutContainer(c *router.Context) {
 	id := c.Param("id")
 
-	stdout, err := h.dockerService.LogsStdoutContainer(id)
+	stdout, err := h.dockerService.LogsStdoutContainer(c.Request.Context(), id, types.LogsContainerOptions{
+		Tail: c.Query("tail"),
+	})
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToGetContainerLogs,
@@ -164,7 +222,9 @@ func (h *DockerKernelHandler) LogsStdoutContainer(c *router.Context) {
 func (h *DockerKernelHandler) LogsStderrContainer(c *router.Context) {
 	id := c.Param("id")
 
-	stderr, err := h.dockerService.LogsStderrContainer(id)
+	stderr, err := h.dockerService.LogsStderrContainer(c.Request.Context(), id, types.LogsContainerOptions{
+		Tail: c.Query("tail"),
+	})
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToGetContainerLogs,
@@ -198,7 +258,7 @@ func (h *DockerKernelHandler) WaitContainer(c *router.Context) {
 	id := c.Param("id")
 	cond := c.Param("cond")
 
-	err := h.dockerService.WaitContainer(id, types.WaitContainerCondition(cond))
+	err := h.dockerService.WaitContainer(c.Request.Context(), id, types.WaitContainerCondition(cond))
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToWaitContainer,
@@ -211,11 +271,91 @@ func (h *DockerKernelHandler) WaitContainer(c *router.Context) {
 	c.OK()
 }
 
-func (h *DockerKernelHandler) InfoImage(c *router.Context) {
+func (h *DockerKernelHandler) ExecContainer(c *router.Context) {
 	id := c.Param("id")
 
-	info, err := h.dockerService.InfoImage(id)
+	var options types.ExecContainerOptions
+	err := c.ParseBody(&options)
 	if err != nil {
+		return
+	}
+
+	res, err := h.dockerService.ExecContainer(c.Request.Context(), id, options)
+	if err != nil && client.IsErrNotFound(err) {
+		c.NotFound(router.Error{
+			Code:           api.ErrContainerNotFound,
+			PublicMessage:  fmt.Sprintf("Container %s not found.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToExecContainer,
+			PublicMessage:  fmt.Sprintf("Failed to execute the command in container %s.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(res)
+}
+
+func (h *DockerKernelHandler) ListImages(c *router.Context) {
+	images, err := h.dockerService.ListImages(c.Request.Context())
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToListImages,
+			PublicMessage:  "Failed to list images.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(images)
+}
+
+func (h *DockerKernelHandler) DeleteImage(c *router.Context) {
+	id := c.Param("id")
+
+	err := h.dockerService.DeleteImage(c.Request.Context(), id)
+	if err != nil && client.IsErrNotFound(err) {
+		c.NotFound(router.Error{
+			Code:           api.ErrImageNotFound,
+			PublicMessage:  fmt.Sprintf("Image %s not found.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil && errdefs.IsConflict(err) {
+		c.Conflict(router.Error{
+			Code:           api.ErrImageInUse,
+			PublicMessage:  fmt.Sprintf("Image %s is used by a container.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToDeleteImage,
+			PublicMessage:  fmt.Sprintf("Failed to delete image %s.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (h *DockerKernelHandler) InfoImage(c *router.Context) {
+	id := c.Param("id")
+
+	info, err := h.dockerService.InfoImage(c.Request.Context(), id)
+	if err != nil && client.IsErrNotFound(err) {
+		c.NotFound(router.Error{
+			Code:           api.ErrImageNotFound,
+			PublicMessage:  fmt.Sprintf("Image %s not found.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToGetImageInfo,
 			PublicMessage:  fmt.Sprintf("Failed to get info for image %s.", id),
@@ -234,7 +374,7 @@ func (h *DockerKernelHandler) PullImage(c *router.Context) {
 		return
 	}
 
-	r, err := h.dockerService.PullImage(options)
+	r, err := h.dockerService.PullImage(c.Request.Context(), options)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToPullImage,
@@ -271,7 +411,7 @@ func (h *DockerKernelHandler) BuildImage(c *router.Context) {
 		return
 	}
 
-	res, err := h.dockerService.BuildImage(options)
+	res, err := h.dockerService.BuildImage(c.Request.Context(), options)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToBuildImage,
@@ -302,3 +442,306 @@ func (h *DockerKernelHandler) BuildImage(c *router.Context) {
 		return true
 	})
 }
+
+func (h *DockerKernelHandler) PruneBuildCache(c *router.Context) {
+	res, err := h.dockerService.PruneBuildCache(c.Request.Context())
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToPruneBuildCache,
+			PublicMessage:  "Failed to prune build cache.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(res)
+}
+
+func (h *DockerKernelHandler) ListVolumes(c *router.Context) {
+	volumes, err := h.dockerService.ListVolumes(c.Request.Context())
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToListVolumes,
+			PublicMessage:  "Failed to list volumes.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(volumes)
+}
+
+func (h *DockerKernelHandler) CreateVolume(c *router.Context) {
+	var options types.CreateVolumeOptions
+	err := c.ParseBody(&options)
+	if err != nil {
+		return
+	}
+
+	setAuditDetail(c, "volume_name", options.Name)
+	if device, ok := options.DriverOptions["device"]; ok {
+		setAuditDetail(c, "device", device)
+	}
+
+	res, err := h.dockerService.CreateVolume(c.Request.Context(), options)
+	var violation types.KernelPolicyViolation
+	if errors.As(err, &violation) {
+		setAuditDetail(c, "policy_rule", violation.Rule)
+		c.AbortWithCode(http.StatusForbidden, router.Error{
+			Code:           api.ErrKernelPolicyViolation,
+			PublicMessage:  fmt.Sprintf("The volume was rejected by the kernel policy: %s", violation.Message),
+			PrivateMessage: err.Error(),
+			Details:        violation,
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToCreateVolume,
+			PublicMessage:  fmt.Sprintf("Failed to create volume %s.", options.Name),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(res)
+}
+
+func (h *DockerKernelHandler) InspectVolume(c *router.Context) {
+	name := c.Param("name")
+
+	volume, err := h.dockerService.InspectVolume(c.Request.Context(), name)
+	if err != nil && client.IsErrNotFound(err) {
+		c.NotFound(router.Error{
+			Code:           api.ErrVolumeNotFound,
+			PublicMessage:  fmt.Sprintf("Volume %s not found.", name),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToInspectVolume,
+			PublicMessage:  fmt.Sprintf("Failed to inspect volume %s.", name),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(volume)
+}
+
+func (h *DockerKernelHandler) DeleteVolume(c *router.Context) {
+	name := c.Param("name")
+
+	err := h.dockerService.DeleteVolume(c.Request.Context(), name)
+	if err != nil && client.IsErrNotFound(err) {
+		c.NotFound(router.Error{
+			Code:           api.ErrVolumeNotFound,
+			PublicMessage:  fmt.Sprintf("Volume %s not found.", name),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil && errdefs.IsConflict(err) {
+		c.Conflict(router.Error{
+			Code:           api.ErrVolumeInUse,
+			PublicMessage:  fmt.Sprintf("Volume %s is used by a container.", name),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToDeleteVolume,
+			PublicMessage:  fmt.Sprintf("Failed to delete volume %s.", name),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+// Events streams the container events of the Docker daemon. Each event is
+// named after its action.
+func (h *DockerKernelHandler) Events(c *router.Context) {
+	events, errs := h.dockerService.Events(c.Request.Context())
+
+	first := true
+
+	c.Stream(func(w io.Writer) bool {
+		if first {
+			err := sse.Encode(w, sse.Event{
+				Event: "open",
+			})
+			if err != nil {
+				log.Error(err)
+				return false
+			}
+			first = false
+			return true
+		}
+
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return false
+			}
+			err := sse.Encode(w, sse.Event{
+				Event: e.Action,
+				Data:  e,
+			})
+			if err != nil {
+				log.Error(err)
+				return false
+			}
+			return true
+		case err := <-errs:
+			if err != nil {
+				log.Error(err)
+			}
+			return false
+		}
+	})
+}
+
+func (h *DockerKernelHandler) ListNetworks(c *router.Context) {
+	networks, err := h.dockerService.ListNetworks(c.Request.Context())
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToListNetworks,
+			PublicMessage:  "Failed to list networks.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(networks)
+}
+
+func (h *DockerKernelHandler) CreateNetwork(c *router.Context) {
+	var options types.CreateNetworkOptions
+	err := c.ParseBody(&options)
+	if err != nil {
+		return
+	}
+
+	setAuditDetail(c, "network_name", options.Name)
+
+	res, err := h.dockerService.CreateNetwork(c.Request.Context(), options)
+	if err != nil && errdefs.IsConflict(err) {
+		c.Conflict(router.Error{
+			Code:           api.ErrNetworkAlreadyExists,
+			PublicMessage:  fmt.Sprintf("Network %s already exists.", options.Name),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToCreateNetwork,
+			PublicMessage:  fmt.Sprintf("Failed to create network %s.", options.Name),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(res)
+}
+
+func (h *DockerKernelHandler) DeleteNetwork(c *router.Context) {
+	id := c.Param("id")
+
+	err := h.dockerService.DeleteNetwork(c.Request.Context(), id)
+	if err != nil && client.IsErrNotFound(err) {
+		c.NotFound(router.Error{
+			Code:           api.ErrNetworkNotFound,
+			PublicMessage:  fmt.Sprintf("Network %s not found.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil && (errdefs.IsConflict(err) || errdefs.IsForbidden(err)) {
+		c.Conflict(router.Error{
+			Code:           api.ErrNetworkInUse,
+			PublicMessage:  fmt.Sprintf("Network %s is used by a container.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToDeleteNetwork,
+			PublicMessage:  fmt.Sprintf("Failed to delete network %s.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (h *DockerKernelHandler) ConnectNetwork(c *router.Context) {
+	id := c.Param("id")
+
+	var options types.ConnectNetworkOptions
+	err := c.ParseBody(&options)
+	if err != nil {
+		return
+	}
+
+	setAuditDetail(c, "container", options.Container)
+
+	err = h.dockerService.ConnectNetwork(c.Request.Context(), id, options)
+	if err != nil && client.IsErrNotFound(err) {
+		c.NotFound(router.Error{
+			Code:           api.ErrNetworkNotFound,
+			PublicMessage:  fmt.Sprintf("Network %s or container %s not found.", id, options.Container),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil && (errdefs.IsConflict(err) || errdefs.IsForbidden(err)) {
+		// Docker returns a forbidden error when the container is
+		// already connected to the network.
+		c.Conflict(router.Error{
+			Code:           api.ErrNetworkAlreadyConnected,
+			PublicMessage:  fmt.Sprintf("Container %s is already connected to network %s.", options.Container, id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToConnectNetwork,
+			PublicMessage:  fmt.Sprintf("Failed to connect container %s to network %s.", options.Container, id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (h *DockerKernelHandler) DisconnectNetwork(c *router.Context) {
+	id := c.Param("id")
+
+	var options types.DisconnectNetworkOptions
+	err := c.ParseBody(&options)
+	if err != nil {
+		return
+	}
+
+	setAuditDetail(c, "container", options.Container)
+
+	err = h.dockerService.DisconnectNetwork(c.Request.Context(), id, options)
+	if err != nil && client.IsErrNotFound(err) {
+		c.NotFound(router.Error{
+			Code:           api.ErrNetworkNotFound,
+			PublicMessage:  fmt.Sprintf("Network %s or container %s not found.", id, options.Container),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToDisconnectNetwork,
+			PublicMessage:  fmt.Sprintf("Failed to disconnect container %s from network %s.", options.Container, id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
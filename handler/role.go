@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type RoleHandler struct {
+	roleService port.RoleService
+}
+
+func NewRoleHandler(roleService port.RoleService) port.RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+func (h *RoleHandler) Get(c *router.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	roles, err := h.roleService.GetAll()
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToGetRoles,
+			PublicMessage:  "Failed to get roles.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(roles)
+}
+
+func (h *RoleHandler) GetPermissions(c *router.Context) {
+	c.JSON(types.Permissions)
+}
+
+func (h *RoleHandler) Set(c *router.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var role types.Role
+	err := c.ParseBody(&role)
+	if err != nil {
+		return
+	}
+	role.Name = c.Param("role_name")
+
+	err = h.roleService.Set(role)
+	if errors.Is(err, types.ErrRoleNameInvalid) {
+		c.BadRequest(router.Error{
+			Code:           api.ErrInvalidRoleName,
+			PublicMessage:  "The role name is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if errors.Is(err, types.ErrRolePermissionInvalid) {
+		c.BadRequest(router.Error{
+			Code:           api.ErrInvalidPermission,
+			PublicMessage:  "The role contains an unknown permission.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToSetRole,
+			PublicMessage:  "Failed to save the role.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (h *RoleHandler) Delete(c *router.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	err := h.roleService.Delete(c.Param("role_name"))
+	if errors.Is(err, types.ErrRoleNotFound) {
+		c.NotFound(router.Error{
+			Code:           api.ErrRoleNotFound,
+			PublicMessage:  "Role not found.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToDeleteRole,
+			PublicMessage:  "Failed to delete the role.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
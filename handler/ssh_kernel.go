@@ -7,6 +7,7 @@ import (
 	"github.com/vertex-center/vertex/core/port"
 	"github.com/vertex-center/vertex/core/service"
 	"github.com/vertex-center/vertex/core/types/api"
+	"golang.org/x/crypto/ssh"
 	"net/http"
 
 	"github.com/vertex-center/vertex/pkg/router"
@@ -49,6 +50,11 @@ func (h *SshKernelHandler) Add(c *router.Context) {
 	}
 	key := buf.String()
 
+	pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+	if err == nil {
+		setAuditDetail(c, "fingerprint", ssh.FingerprintSHA256(pubKey))
+	}
+
 	err = h.sshService.Add(key)
 	if err != nil && errors.Is(err, service.ErrInvalidPublicKey) {
 		c.BadRequest(router.Error{
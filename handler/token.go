@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type TokenHandler struct {
+	tokenService port.TokenService
+}
+
+func NewTokenHandler(tokenService port.TokenService) port.TokenHandler {
+	return &TokenHandler{
+		tokenService: tokenService,
+	}
+}
+
+func (h *TokenHandler) Get(c *router.Context) {
+	user, _ := currentUser(c)
+
+	tokens, err := h.tokenService.GetAll(user.ID)
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToGetTokens,
+			PublicMessage:  "Failed to get tokens.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(tokens)
+}
+
+func (h *TokenHandler) Create(c *router.Context) {
+	// A token cannot be used to create tokens with more permissions.
+	if !currentAuthentication(c).Grants(types.PermissionAll) {
+		abortForbidden(c, types.PermissionAll)
+		return
+	}
+
+	var options types.CreateTokenOptions
+	err := c.ParseBody(&options)
+	if err != nil {
+		return
+	}
+
+	user, _ := currentUser(c)
+
+	res, err := h.tokenService.Create(user.ID, options)
+	if errors.Is(err, types.ErrTokenNameInvalid) {
+		c.BadRequest(router.Error{
+			Code:           api.ErrInvalidTokenName,
+			PublicMessage:  "The token name is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if errors.Is(err, types.ErrTokenScopeInvalid) {
+		c.BadRequest(router.Error{
+			Code:           api.ErrInvalidTokenScope,
+			PublicMessage:  "The token must have at least one valid scope.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToCreateToken,
+			PublicMessage:  "Failed to create token.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.Context.JSON(http.StatusCreated, res)
+}
+
+func (h *TokenHandler) Delete(c *router.Context) {
+	// A token with a limited scope cannot revoke the other tokens, which can
+	// have more permissions.
+	if !currentAuthentication(c).Grants(types.PermissionAll) {
+		abortForbidden(c, types.PermissionAll)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("token_uuid"))
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           api.ErrInvalidTokenUUID,
+			PublicMessage:  "Invalid token UUID.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	user, _ := currentUser(c)
+
+	err = h.tokenService.Delete(user, id)
+	if errors.Is(err, types.ErrTokenNotFound) {
+		c.NotFound(router.Error{
+			Code:           api.ErrTokenNotFound,
+			PublicMessage:  "Token not found.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToDeleteToken,
+			PublicMessage:  "Failed to revoke token.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
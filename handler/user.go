@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type UserHandler struct {
+	userService port.UserService
+}
+
+func NewUserHandler(userService port.UserService) port.UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+func (h *UserHandler) Get(c *router.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	users, err := h.userService.GetAll()
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToGetUsers,
+			PublicMessage:  "Failed to get users.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(users)
+}
+
+func (h *UserHandler) Create(c *router.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var options types.CreateUserOptions
+	err := c.ParseBody(&options)
+	if err != nil {
+		return
+	}
+
+	user, err := h.userService.Create(options)
+	if err != nil {
+		abortWithUserError(c, err, router.Error{
+			Code:           api.ErrFailedToCreateUser,
+			PublicMessage:  "Failed to create user.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.Context.JSON(http.StatusCreated, user)
+}
+
+func (h *UserHandler) Delete(c *router.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	id, ok := getParamUserUUID(c)
+	if !ok {
+		return
+	}
+
+	err := h.userService.Delete(id)
+	if err != nil {
+		abortWithUserError(c, err, router.Error{
+			Code:           api.ErrFailedToDeleteUser,
+			PublicMessage:  "Failed to delete user.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+type SetPasswordBody struct {
+	Password string `json:"password"`
+}
+
+// SetPassword changes the password of a user. Users can change their own
+// password, and admins can change the password of anyone.
+func (h *UserHandler) SetPassword(c *router.Context) {
+	id, ok := getParamUserUUID(c)
+	if !ok {
+		return
+	}
+
+	// The tokens need all the permissions to change a password.
+	user, _ := currentUser(c)
+	self := user.ID == id && currentAuthentication(c).Grants(types.PermissionAll)
+	if !self && !requireAdmin(c) {
+		return
+	}
+
+	var body SetPasswordBody
+	err := c.ParseBody(&body)
+	if err != nil {
+		return
+	}
+
+	err = h.userService.SetPassword(id, body.Password)
+	if err != nil {
+		abortWithUserError(c, err, router.Error{
+			Code:           api.ErrFailedToSetPassword,
+			PublicMessage:  "Failed to change the password.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (h *UserHandler) SetRoles(c *router.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	id, ok := getParamUserUUID(c)
+	if !ok {
+		return
+	}
+
+	var options types.SetUserRolesOptions
+	err := c.ParseBody(&options)
+	if err != nil {
+		return
+	}
+
+	err = h.userService.SetRoles(id, options.Roles)
+	if errors.Is(err, types.ErrRoleNotFound) {
+		c.NotFound(router.Error{
+			Code:           api.ErrRoleNotFound,
+			PublicMessage:  "Role not found.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		abortWithUserError(c, err, router.Error{
+			Code:           api.ErrFailedToSetRoles,
+			PublicMessage:  "Failed to change the roles of the user.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+// requireAdmin aborts the request if the current user is not an admin, or
+// if the request uses a token that doesn't have all the permissions.
+func requireAdmin(c *router.Context) bool {
+	user, ok := currentUser(c)
+	if !ok || !user.Admin || !currentAuthentication(c).Grants(types.PermissionAll) {
+		c.AbortWithCode(http.StatusForbidden, router.Error{
+			Code:           api.ErrForbidden,
+			PublicMessage:  "This action requires the admin role.",
+			PrivateMessage: "The current user is not an admin.",
+		})
+		return false
+	}
+	return true
+}
+
+func getParamUserUUID(c *router.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("user_uuid"))
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           api.ErrInvalidUserUUID,
+			PublicMessage:  "Invalid user UUID.",
+			PrivateMessage: err.Error(),
+		})
+		return uuid.UUID{}, false
+	}
+	return id, true
+}
+
+// abortWithUserError aborts the request with the error matching the user
+// error, or with the fallback error if none matches.
+func abortWithUserError(c *router.Context, err error, fallback router.Error) {
+	switch {
+	case errors.Is(err, types.ErrUserNotFound):
+		c.NotFound(router.Error{
+			Code:           api.ErrUserNotFound,
+			PublicMessage:  "User not found.",
+			PrivateMessage: err.Error(),
+		})
+	case errors.Is(err, types.ErrUserAlreadyExists):
+		c.Conflict(router.Error{
+			Code:           api.ErrUserAlreadyExists,
+			PublicMessage:  "A user with this username already exists.",
+			PrivateMessage: err.Error(),
+		})
+	case errors.Is(err, types.ErrUsernameInvalid):
+		c.BadRequest(router.Error{
+			Code:           api.ErrInvalidUsername,
+			PublicMessage:  "The username is invalid.",
+			PrivateMessage: err.Error(),
+		})
+	case errors.Is(err, types.ErrPasswordTooShort):
+		c.BadRequest(router.Error{
+			Code:           api.ErrPasswordTooShort,
+			PublicMessage:  "The password is too short.",
+			PrivateMessage: err.Error(),
+		})
+	case errors.Is(err, types.ErrLastAdmin):
+		c.Conflict(router.Error{
+			Code:           api.ErrLastAdmin,
+			PublicMessage:  "The last admin cannot be deleted.",
+			PrivateMessage: err.Error(),
+		})
+	default:
+		c.Abort(fallback)
+	}
+}
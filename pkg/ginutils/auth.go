@@ -0,0 +1,28 @@
+package ginutils
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+// BearerAuth rejects the requests that don't have the given secret
+// in their Authorization header.
+func BearerAuth(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			c.Header("Content-Type", "application/json")
+			_ = c.AbortWithError(http.StatusUnauthorized, router.Error{
+				Code:           router.ErrUnauthorized,
+				PublicMessage:  "The request is not authenticated.",
+				PrivateMessage: "The Authorization header is missing or invalid.",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package ginutils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type BearerAuthTestSuite struct {
+	suite.Suite
+
+	engine *gin.Engine
+}
+
+func TestBearerAuthTestSuite(t *testing.T) {
+	suite.Run(t, new(BearerAuthTestSuite))
+}
+
+func (suite *BearerAuthTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.engine = gin.New()
+	suite.engine.Use(ErrorHandler())
+	suite.engine.Use(BearerAuth("secret"))
+	suite.engine.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+}
+
+func (suite *BearerAuthTestSuite) request(authorization string) int {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	return w.Code
+}
+
+func (suite *BearerAuthTestSuite) TestBearerAuth() {
+	suite.Equal(http.StatusOK, suite.request("Bearer secret"))
+	suite.Equal(http.StatusUnauthorized, suite.request(""))
+	suite.Equal(http.StatusUnauthorized, suite.request("Bearer wrong"))
+	suite.Equal(http.StatusUnauthorized, suite.request("secret"))
+}
@@ -4,12 +4,16 @@ type ErrCode string
 
 const (
 	ErrFailedToParseBody ErrCode = "failed_to_parse_body"
+	ErrUnauthorized      ErrCode = "unauthorized"
 )
 
 type Error struct {
 	Code           ErrCode `json:"code"`
 	PublicMessage  string  `json:"message,omitempty"`
 	PrivateMessage string  `json:"-"`
+
+	// Details gives structured information about the error to the client.
+	Details interface{} `json:"details,omitempty"`
 }
 
 func (e Error) Error() string {
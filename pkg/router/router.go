@@ -2,7 +2,9 @@ package router
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,6 +12,7 @@ import (
 type Router struct {
 	*gin.Engine
 	server *http.Server
+	mutex  sync.Mutex
 }
 
 func New() *Router {
@@ -19,14 +22,32 @@ func New() *Router {
 }
 
 func (r *Router) Start(addr string) error {
-	r.server = &http.Server{
-		Addr:    addr,
-		Handler: r.Engine,
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return r.Serve(l)
+}
+
+// Serve serves the router on the given listener. It can be called for
+// multiple listeners, which are all closed by Stop.
+func (r *Router) Serve(l net.Listener) error {
+	r.mutex.Lock()
+	if r.server == nil {
+		r.server = &http.Server{
+			Handler: r.Engine,
+		}
 	}
-	return r.server.ListenAndServe()
+	server := r.server
+	r.mutex.Unlock()
+
+	return server.Serve(l)
 }
 
 func (r *Router) Stop(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
 	if r.server == nil {
 		return nil
 	}
@@ -38,6 +59,11 @@ func (r *Router) Stop(ctx context.Context) error {
 	return nil
 }
 
+// UseHandlers adds middlewares to the router, like Use, but with HandlerFunc.
+func (r *Router) UseHandlers(handlers ...HandlerFunc) {
+	r.Engine.Use(wrapHandlers(handlers...)...)
+}
+
 func (r *Router) Group(path string, handlers ...HandlerFunc) *Group {
 	return &Group{
 		RouterGroup: r.Engine.Group(path, wrapHandlers(handlers...)...),
@@ -0,0 +1,298 @@
+package vsemver
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const wildcard = "x"
+
+var (
+	ErrInvalidVersion    = errors.New("invalid semantic version")
+	ErrInvalidConstraint = errors.New("invalid version constraint")
+)
+
+// prereleases are the identifiers that start a prerelease suffix. Any
+// other suffix is a variant of the image, like "alpine" or "bookworm".
+var prereleases = map[string]bool{
+	"alpha":   true,
+	"beta":    true,
+	"rc":      true,
+	"pre":     true,
+	"preview": true,
+	"dev":     true,
+}
+
+// Version is a semantic version parsed from a container image tag.
+// Image tags are less strict than semver: "15", "15.4", "v1.2.3" and
+// "1.25.3-alpine" are all valid versions.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+
+	// Prerelease is the suffix after the first '-' if it starts with a
+	// prerelease identifier, like "rc.1" in "1.2.3-rc.1-alpine".
+	Prerelease string
+
+	// Variant is the rest of the suffix, like "alpine" in
+	// "1.2.3-rc.1-alpine" or "15.4-alpine".
+	Variant string
+
+	// Raw is the original tag.
+	Raw string
+}
+
+// Parse parses an image tag into a Version. It returns ErrInvalidVersion
+// if the tag is not a semantic version (e.g. "latest", "nightly").
+func Parse(tag string) (Version, error) {
+	v := Version{Raw: tag}
+
+	s := strings.TrimPrefix(tag, "v")
+	s, suffix, _ := strings.Cut(s, "-")
+	s, _, _ = strings.Cut(s, "+")
+	v.Prerelease, v.Variant = parseSuffix(suffix)
+
+	parts := strings.Split(s, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return Version{}, ErrInvalidVersion
+	}
+
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := parseNumber(p)
+		if err != nil {
+			return Version{}, ErrInvalidVersion
+		}
+		*numbers[i] = n
+	}
+
+	return v, nil
+}
+
+// IsPrerelease returns true if the version has a prerelease suffix.
+func (v Version) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Compare returns -1 if v < other, 0 if v == other and 1 if v > other.
+// A version without prerelease is greater than the same version with
+// a prerelease, as in semver. The variants are only compared to keep the
+// order stable.
+func (v Version) Compare(other Version) int {
+	if c := compareInt(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+	if c := comparePrerelease(v.Prerelease, other.Prerelease); c != 0 {
+		return c
+	}
+	return strings.Compare(v.Variant, other.Variant)
+}
+
+func (v Version) String() string {
+	return v.Raw
+}
+
+// Sort parses the given tags and returns the valid versions, sorted from the
+// newest to the oldest. Tags that are not semantic versions are dropped.
+func Sort(tags []string) []Version {
+	var versions []Version
+	for _, tag := range tags {
+		v, err := Parse(tag)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, v)
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Compare(versions[j]) > 0
+	})
+	return versions
+}
+
+// Constraint is a version pattern like "15.x" or "1.2.x-alpine". It allows
+// a container to track the latest version within a major or minor version,
+// and within a variant of the image.
+type Constraint struct {
+	parts   []*int
+	variant string
+	raw     string
+}
+
+// IsConstraint returns true if the given string is a wildcard constraint,
+// like "15.x", and not a fixed tag.
+func IsConstraint(s string) bool {
+	_, err := ParseConstraint(s)
+	return err == nil
+}
+
+// ParseConstraint parses a wildcard constraint. The last component must be
+// "x", and all previous components must be numbers. The constraint can be
+// followed by a variant, like "15.x-alpine", but not by a prerelease.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: s}
+
+	version, suffix, _ := strings.Cut(strings.TrimPrefix(s, "v"), "-")
+	prerelease, variant := parseSuffix(suffix)
+	if prerelease != "" {
+		return Constraint{}, ErrInvalidConstraint
+	}
+	c.variant = variant
+
+	parts := strings.Split(version, ".")
+	if len(parts) == 0 || len(parts) > 3 || parts[len(parts)-1] != wildcard {
+		return Constraint{}, ErrInvalidConstraint
+	}
+
+	for _, p := range parts {
+		if p == wildcard {
+			c.parts = append(c.parts, nil)
+			continue
+		}
+		n, err := parseNumber(p)
+		if err != nil {
+			return Constraint{}, ErrInvalidConstraint
+		}
+		c.parts = append(c.parts, &n)
+	}
+
+	return c, nil
+}
+
+// Match returns true if the version satisfies the constraint. The version
+// must have the same variant as the constraint. Prereleases never satisfy
+// a constraint.
+func (c Constraint) Match(v Version) bool {
+	if v.IsPrerelease() || v.Variant != c.variant {
+		return false
+	}
+	numbers := []int{v.Major, v.Minor, v.Patch}
+	for i, p := range c.parts {
+		if p == nil {
+			return true
+		}
+		if numbers[i] != *p {
+			return false
+		}
+	}
+	return true
+}
+
+// Latest returns the newest version of the list satisfying the constraint.
+// The versions must be sorted from the newest to the oldest, as returned
+// by Sort.
+func (c Constraint) Latest(versions []Version) (Version, bool) {
+	for _, v := range versions {
+		if c.Match(v) {
+			return v, true
+		}
+	}
+	return Version{}, false
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// parseSuffix splits the suffix of a tag into a prerelease and a variant.
+func parseSuffix(suffix string) (prerelease string, variant string) {
+	if suffix == "" {
+		return "", ""
+	}
+	first, rest, _ := strings.Cut(suffix, "-")
+	id, _, _ := strings.Cut(first, ".")
+	name, _ := splitIdentifier(strings.ToLower(id))
+	if !prereleases[name] {
+		return "", suffix
+	}
+	return first, rest
+}
+
+// comparePrerelease compares two prereleases. No prerelease is greater
+// than any prerelease. The dot-separated identifiers are compared one by
+// one, numerically if they are numbers.
+func comparePrerelease(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareIdentifier(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(as), len(bs))
+}
+
+// compareIdentifier compares two prerelease identifiers. Numbers are lower
+// than other identifiers, as in semver. Identifiers like "rc10" are
+// compared by name, then by number, so that "rc2" < "rc10".
+func compareIdentifier(a, b string) int {
+	an, aErr := parseNumber(a)
+	bn, bErr := parseNumber(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInt(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+
+	aName, aNumber := splitIdentifier(a)
+	bName, bNumber := splitIdentifier(b)
+	if aName == bName && aNumber != bNumber && aNumber != "" && bNumber != "" {
+		an, _ = parseNumber(aNumber)
+		bn, _ = parseNumber(bNumber)
+		return compareInt(an, bn)
+	}
+	return strings.Compare(a, b)
+}
+
+// splitIdentifier splits an identifier like "rc10" into its name and its
+// trailing number.
+func splitIdentifier(id string) (name string, number string) {
+	i := len(id)
+	for i > 0 && id[i-1] >= '0' && id[i-1] <= '9' {
+		i--
+	}
+	return id[:i], id[i:]
+}
+
+func parseNumber(s string) (int, error) {
+	if s == "" {
+		return 0, ErrInvalidVersion
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0, ErrInvalidVersion
+		}
+	}
+	return strconv.Atoi(s)
+}
+
+func compareInt(a, b int) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
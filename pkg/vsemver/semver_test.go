@@ -0,0 +1,108 @@
+package vsemver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SemverTestSuite struct {
+	suite.Suite
+}
+
+func TestSemverTestSuite(t *testing.T) {
+	suite.Run(t, new(SemverTestSuite))
+}
+
+func (suite *SemverTestSuite) TestParse() {
+	v, err := Parse("v1.25.3-alpine")
+	suite.NoError(err)
+	suite.Equal(1, v.Major)
+	suite.Equal(25, v.Minor)
+	suite.Equal(3, v.Patch)
+	suite.Equal("", v.Prerelease)
+	suite.Equal("alpine", v.Variant)
+	suite.Equal("v1.25.3-alpine", v.String())
+
+	v, err = Parse("1.2.3-rc.1-alpine")
+	suite.NoError(err)
+	suite.Equal("rc.1", v.Prerelease)
+	suite.Equal("alpine", v.Variant)
+
+	v, err = Parse("15")
+	suite.NoError(err)
+	suite.Equal(15, v.Major)
+	suite.Equal(0, v.Minor)
+
+	for _, tag := range []string{"latest", "nightly", "1.2.3.4", "1..2", "amd64-1.2"} {
+		_, err = Parse(tag)
+		suite.ErrorIs(err, ErrInvalidVersion, tag)
+	}
+}
+
+func (suite *SemverTestSuite) TestSort() {
+	versions := Sort([]string{"latest", "1.2.0", "1.10.0", "1.10.0-rc1", "2", "nightly"})
+
+	var tags []string
+	for _, v := range versions {
+		tags = append(tags, v.String())
+	}
+	suite.Equal([]string{"2", "1.10.0", "1.10.0-rc1", "1.2.0"}, tags)
+}
+
+func (suite *SemverTestSuite) TestComparePrerelease() {
+	compare := func(a, b string) int {
+		va, err := Parse(a)
+		suite.NoError(err)
+		vb, err := Parse(b)
+		suite.NoError(err)
+		return va.Compare(vb)
+	}
+
+	suite.Equal(1, compare("1.0.0-rc.10", "1.0.0-rc.2"))
+	suite.Equal(1, compare("1.0.0-rc10", "1.0.0-rc2"))
+	suite.Equal(1, compare("1.0.0-rc.1", "1.0.0-beta.5"))
+	suite.Equal(1, compare("1.0.0-beta.1.1", "1.0.0-beta.1"))
+	suite.Equal(1, compare("1.0.0", "1.0.0-rc.1"))
+	suite.Equal(0, compare("1.0.0-rc.1", "1.0.0-rc.1"))
+}
+
+func (suite *SemverTestSuite) TestConstraint() {
+	suite.True(IsConstraint("15.x"))
+	suite.True(IsConstraint("1.2.x"))
+	suite.False(IsConstraint("15"))
+	suite.False(IsConstraint("latest"))
+	suite.False(IsConstraint("x.1"))
+
+	c, err := ParseConstraint("15.x")
+	suite.NoError(err)
+
+	versions := Sort([]string{"14.9", "15.1", "15.4", "16.0", "15.5-beta"})
+	latest, ok := c.Latest(versions)
+	suite.True(ok)
+	suite.Equal("15.4", latest.String())
+
+	c, err = ParseConstraint("17.x")
+	suite.NoError(err)
+	_, ok = c.Latest(versions)
+	suite.False(ok)
+
+	_, err = ParseConstraint("15.x-rc.1")
+	suite.ErrorIs(err, ErrInvalidConstraint)
+}
+
+func (suite *SemverTestSuite) TestConstraintVariant() {
+	versions := Sort([]string{"15.4", "15.3-alpine", "15.4-alpine", "15.5-rc.1-alpine", "15.5-bookworm"})
+
+	c, err := ParseConstraint("15.x-alpine")
+	suite.NoError(err)
+	latest, ok := c.Latest(versions)
+	suite.True(ok)
+	suite.Equal("15.4-alpine", latest.String())
+
+	c, err = ParseConstraint("15.x")
+	suite.NoError(err)
+	latest, ok = c.Latest(versions)
+	suite.True(ok)
+	suite.Equal("15.4", latest.String())
+}